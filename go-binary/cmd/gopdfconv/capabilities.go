@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/converter"
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+)
+
+// FormatCapability describes how one input format is converted
+type FormatCapability struct {
+	Format              string   `json:"format"`
+	Extensions          []string `json:"extensions"`
+	NativeRenderer      bool     `json:"native_renderer"`      // Can be converted without LibreOffice
+	RequiresLibreOffice bool     `json:"requires_libreoffice"` // Full-fidelity output needs LibreOffice
+	Notes               string   `json:"notes,omitempty"`
+}
+
+// PageSizeCapability describes a supported page size in points
+type PageSizeCapability struct {
+	Name   string  `json:"name"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Capabilities is the -capabilities JSON document
+type Capabilities struct {
+	Version              string               `json:"version"`
+	Formats              []FormatCapability   `json:"formats"`
+	PageSizes            []PageSizeCapability `json:"page_sizes"`
+	Orientations         []string             `json:"orientations"`
+	LibreOfficeAvailable bool                 `json:"libreoffice_available"`
+}
+
+// supportedFormats lists every input format the CLI accepts
+var supportedFormats = []FormatCapability{
+	{Format: string(converter.FormatCSV), Extensions: []string{".csv"}, NativeRenderer: true},
+	{Format: string(converter.FormatTSV), Extensions: []string{".tsv"}, NativeRenderer: true},
+	{Format: string(converter.FormatXLSX), Extensions: []string{".xlsx"}, NativeRenderer: true},
+	{Format: string(converter.FormatXLSM), Extensions: []string{".xlsm"}, NativeRenderer: true},
+	{Format: string(converter.FormatXLS), Extensions: []string{".xls"}, RequiresLibreOffice: true,
+		Notes: "Converted to XLSX with LibreOffice first"},
+	{Format: string(converter.FormatODS), Extensions: []string{".ods"}, NativeRenderer: true,
+		Notes: "Cell text only; conditional formatting and tab colors are not read"},
+	{Format: string(converter.FormatPPTX), Extensions: []string{".pptx"}, NativeRenderer: true, RequiresLibreOffice: true,
+		Notes: "LibreOffice is used when available for best fidelity"},
+	{Format: string(converter.FormatPPT), Extensions: []string{".ppt"}, NativeRenderer: true, RequiresLibreOffice: true,
+		Notes: "Without LibreOffice only slide text is extracted"},
+	{Format: string(converter.FormatPNG), Extensions: []string{".png"}, NativeRenderer: true},
+	{Format: string(converter.FormatJPEG), Extensions: []string{".jpg", ".jpeg"}, NativeRenderer: true},
+	{Format: string(converter.FormatText), Extensions: []string{".txt", ".log"}, NativeRenderer: true,
+		Notes: "A .txt file with a consistent delimiter is converted as CSV"},
+	{Format: string(converter.FormatNDJSON), Extensions: []string{".ndjson", ".jsonl"}, NativeRenderer: true,
+		Notes: "One JSON object per line; the first object's keys are the columns"},
+}
+
+// printCapabilities prints the supported formats, page sizes and orientations as JSON
+func printCapabilities(libreOfficePath string) {
+	pptxConverter := converter.NewPPTXConverter()
+	if libreOfficePath != "" {
+		pptxConverter.SetLibreOfficePath(libreOfficePath)
+	}
+
+	caps := Capabilities{
+		Version:              Version,
+		Formats:              supportedFormats,
+		Orientations:         []string{string(pdf.Portrait), string(pdf.Landscape)},
+		LibreOfficeAvailable: pptxConverter.HasLibreOffice(),
+	}
+
+	names := make([]string, 0, len(pdf.PageSizes))
+	for name := range pdf.PageSizes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		size := pdf.PageSizes[name]
+		caps.PageSizes = append(caps.PageSizes, PageSizeCapability{Name: name, Width: size.Width, Height: size.Height})
+	}
+
+	data, _ := json.MarshalIndent(caps, "", "  ")
+	fmt.Println(string(data))
+}
+
+// RequirementStatus reports whether one external program is installed
+type RequirementStatus struct {
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
+	Path      string `json:"path,omitempty"`
+}
+
+// FormatDoctor lists the external programs converting one format needs
+type FormatDoctor struct {
+	Format       string              `json:"format"`
+	Requirements []RequirementStatus `json:"requirements"`
+	Ready        bool                `json:"ready"` // Every requirement is available
+}
+
+// DoctorReport is the -doctor JSON document
+type DoctorReport struct {
+	Formats []FormatDoctor `json:"formats"`
+	Ready   bool           `json:"ready"`
+}
+
+// runDoctor prints, as JSON, the external programs converting inputPath (every
+// supported format when it is empty) with opts needs and whether they are
+// installed. It returns whether they all are.
+func runDoctor(inputPath, formatFlag, libreOfficePath string, opts pdf.Options, jsonOutput bool) bool {
+	var formats []converter.FormatType
+	switch {
+	case formatFlag != "auto":
+		formats = []converter.FormatType{converter.FormatType(formatFlag)}
+	case inputPath != "":
+		formats = []converter.FormatType{converter.DetectFormat(inputPath)}
+	default:
+		for _, f := range supportedFormats {
+			formats = append(formats, converter.FormatType(f.Format))
+		}
+	}
+
+	report := DoctorReport{Ready: true}
+	for _, format := range formats {
+		requirements, err := converter.RequirementsOf(format, opts)
+		if err != nil {
+			printError(errors.NewWithFile(errors.ErrUnsupportedFormat, "Unsupported input format", inputPath), jsonOutput)
+			return false
+		}
+		entry := FormatDoctor{Format: string(format), Requirements: []RequirementStatus{}, Ready: true}
+		for _, name := range requirements {
+			path, ok := converter.FindRequirement(name, libreOfficePath)
+			if !ok {
+				path = ""
+			}
+			entry.Requirements = append(entry.Requirements, RequirementStatus{Name: name, Available: ok, Path: path})
+			entry.Ready = entry.Ready && ok
+		}
+		report.Formats = append(report.Formats, entry)
+		report.Ready = report.Ready && entry.Ready
+	}
+
+	data, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(data))
+	return report.Ready
+}
+
+// ProbeReport is the -probe JSON document
+type ProbeReport struct {
+	File     string `json:"file"`
+	Format   string `json:"format"`
+	Sections int    `json:"sections"` // Slides of a presentation, sheets of a workbook, 1 otherwise
+}
+
+// runProbe prints, as JSON, the format of inputPath and its number of slides or
+// sheets, read from the file's structure without converting it. It returns whether
+// the file could be read.
+func runProbe(inputPath string, jsonOutput bool) bool {
+	if inputPath == "" {
+		printError(errors.New(errors.ErrFileNotFound, "Input file is required"), jsonOutput)
+		return false
+	}
+	sections, err := converter.CountSections(inputPath)
+	if err != nil {
+		var convErr *errors.ConversionError
+		if !stderrors.As(err, &convErr) {
+			convErr = errors.Wrap(err, errors.ErrCorruptFile, "Cannot read file structure")
+		}
+		printError(convErr, jsonOutput)
+		return false
+	}
+
+	format := string(converter.DetectFormat(inputPath))
+	if format == string(converter.FormatAuto) {
+		format = strings.ToLower(strings.TrimPrefix(filepath.Ext(inputPath), ".")) // ODP
+	}
+	data, _ := json.MarshalIndent(ProbeReport{File: inputPath, Format: format, Sections: sections}, "", "  ")
+	fmt.Println(string(data))
+	return true
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/worker"
+)
+
+// singleJobID identifies a single-file conversion in progress events
+const singleJobID = "job-1"
+
+// console routes CLI output. Results go to stdout, while progress and verbose
+// diagnostics go to stderr or, when -log is given, to a log file.
+type console struct {
+	jsonOutput bool
+	verbose    bool
+	quiet      bool // Suppress all non-error output on stdout/stderr
+	showResult bool // Print the success result (always, unless -quiet without an explicit -json)
+
+	diag     io.Writer
+	diagFile *os.File
+
+	// Dedicated machine-readable progress channel (-progress-fd / -progress-file)
+	progress     io.Writer
+	progressFile *os.File
+	mu           sync.Mutex // Batch workers report progress concurrently
+}
+
+// progressEvent is one JSON line written to the progress channel
+type progressEvent struct {
+	Job     string `json:"job"`
+	Percent int    `json:"percent"`
+}
+
+// newConsole creates a console for the given output flags
+func newConsole(jsonOutput, jsonExplicit, verbose, quiet bool, logPath string) (*console, error) {
+	c := &console{
+		jsonOutput: jsonOutput,
+		verbose:    verbose,
+		quiet:      quiet,
+		showResult: !quiet || jsonExplicit,
+		diag:       os.Stderr,
+	}
+
+	if logPath != "" {
+		f, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		c.diagFile = f
+		c.diag = f
+	} else if quiet {
+		c.diag = io.Discard
+	}
+
+	return c, nil
+}
+
+// SetProgressChannel routes progress to a file descriptor (fd > 0) or file path
+// as one {"job", "percent"} JSON object per line
+func (c *console) SetProgressChannel(fd int, path string) error {
+	switch {
+	case fd > 0:
+		// NewFile accepts any fd number; Stat tells whether it is open
+		f := os.NewFile(uintptr(fd), "progress")
+		if _, err := f.Stat(); err != nil {
+			return fmt.Errorf("invalid progress file descriptor %d: %v", fd, err)
+		}
+		c.progressFile = f
+	case path != "":
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		c.progressFile = f
+	default:
+		return nil
+	}
+	c.progress = c.progressFile
+	return nil
+}
+
+// Close closes the log and progress files, if any
+func (c *console) Close() {
+	if c.diagFile != nil {
+		c.diagFile.Close()
+	}
+	if c.progressFile != nil {
+		c.progressFile.Close()
+	}
+}
+
+// JobProgress reports progress for a job. It is safe for concurrent use.
+func (c *console) JobProgress(jobID string, percent int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.progress != nil {
+		data, _ := json.Marshal(progressEvent{Job: jobID, Percent: percent})
+		fmt.Fprintf(c.progress, "%s\n", data)
+		return
+	}
+	c.writeProgress(percent)
+}
+
+// BatchProgress reports progress for a batch job. Without a dedicated progress
+// channel, batch progress is not reported to keep stderr readable.
+func (c *console) BatchProgress(jobID string, percent int) {
+	if c.progress != nil {
+		c.JobProgress(jobID, percent)
+	}
+}
+
+// BatchOverall reports aggregate batch progress as jobs finish
+func (c *console) BatchOverall(bp worker.BatchProgress) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, _ := json.Marshal(bp)
+	switch {
+	case c.progress != nil:
+		fmt.Fprintf(c.progress, "%s\n", data)
+	case c.jsonOutput:
+		// Keep progress off stdout so the result JSON stays parseable
+		fmt.Fprintf(c.diag, "%s\n", data)
+	case c.verbose:
+		if c.diagFile != nil {
+			fmt.Fprintf(c.diag, "Batch: %d/%d (%d%%)\n", bp.Completed, bp.Total, bp.Percent)
+		} else {
+			fmt.Fprintf(c.diag, "\rBatch: %d/%d (%d%%)", bp.Completed, bp.Total, bp.Percent)
+		}
+	}
+}
+
+// Progress reports conversion progress for a single-file conversion
+func (c *console) Progress(percent int) {
+	c.JobProgress(singleJobID, percent)
+}
+
+// writeProgress reports progress as JSON lines (JSON mode) or a percentage (verbose mode)
+func (c *console) writeProgress(percent int) {
+	if c.jsonOutput {
+		// Keep progress off stdout so the result JSON stays parseable
+		fmt.Fprintf(c.diag, "{\"progress\": %d}\n", percent)
+	} else if c.verbose {
+		if c.diagFile != nil {
+			fmt.Fprintf(c.diag, "Progress: %d%%\n", percent)
+		} else {
+			fmt.Fprintf(c.diag, "\rProgress: %d%%", percent)
+		}
+	}
+}
+
+// Verbosef writes a diagnostic message when verbose output is enabled
+func (c *console) Verbosef(format string, args ...interface{}) {
+	if c.verbose {
+		fmt.Fprintf(c.diag, format, args...)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// envFlags lists the flags whose default can come from a GOPDFCONV_* environment
+// variable, for deployments where e.g. the soffice path or bundled font is fixed
+var envFlags = []struct {
+	flag string
+	env  string
+}{
+	{"page-size", "GOPDFCONV_PAGE_SIZE"},
+	{"orientation", "GOPDFCONV_ORIENTATION"},
+	{"margin", "GOPDFCONV_MARGIN"},
+	{"font", "GOPDFCONV_FONT"},
+	{"libreoffice", "GOPDFCONV_LIBREOFFICE"},
+	{"temp-dir", "GOPDFCONV_TEMP_DIR"},
+}
+
+// applyEnvDefaults resolves the value of each flag in envFlags. Precedence is:
+//
+//  1. the flag given on the command line
+//  2. its GOPDFCONV_* environment variable
+//  3. the built-in default
+//
+// There is no config file; one would slot in between 1 and 2. Must run after flag.Parse.
+func applyEnvDefaults() error {
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	for _, e := range envFlags {
+		value, ok := os.LookupEnv(e.env)
+		if !ok || explicit[e.flag] {
+			continue
+		}
+		if err := flag.Set(e.flag, value); err != nil {
+			return fmt.Errorf("%s=%q: %v", e.env, value, err)
+		}
+	}
+	return nil
+}
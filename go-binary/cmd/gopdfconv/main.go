@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	stderrors "errors"
 	"flag"
 	"fmt"
 	"os"
@@ -9,6 +10,7 @@ import (
 	"runtime"
 	"strings"
 	"time"
+	_ "time/tzdata" // Embed the timezone database for -timezone in minimal containers
 
 	"github.com/nikunjkothiya/gopdfconv/internal/converter"
 	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
@@ -29,17 +31,27 @@ type Output struct {
 	Error       *errors.ConversionError `json:"error,omitempty"`
 	InputFile   string `json:"input_file,omitempty"`
 	OutputFile  string `json:"output_file,omitempty"`
+	OutputFiles []string `json:"output_files,omitempty"` // Parts written instead of OutputFile when the output was split
 	Format      string `json:"format,omitempty"`
 	ProcessTime int64  `json:"process_time_ms,omitempty"`
 	FileSize    int64  `json:"file_size_bytes,omitempty"`
 	PageCount   int    `json:"page_count,omitempty"`
+	Thumbnail   string `json:"thumbnail,omitempty"` // PNG of the first page, with -thumbnail
+	Sections    []pdf.Section `json:"sections,omitempty"` // Page range of each sheet/slide
+	Stats       *converter.Stats `json:"stats,omitempty"` // Rows, columns and sheets converted (native rendering only)
+	Quality     string `json:"quality,omitempty"` // Quality level of the written PDF, with -max-output-bytes
+	Warnings    []string `json:"warnings,omitempty"`
 }
 
 func main() {
 	// Define command-line flags
-	inputFile := flag.String("input", "", "Input file path (CSV, XLSX, PPTX)")
+	inputFile := flag.String("input", "", "Input file path (CSV, XLSX, ODS, PPTX)")
 	outputFile := flag.String("output", "", "Output PDF file path")
-	formatFlag := flag.String("format", "auto", "Force input format (csv|xlsx|pptx|auto)")
+	outputSuffix := flag.String("output-suffix", ".pdf", "Replaces the input's format extension in output names derived without -output (e.g. _converted.pdf); must end in .pdf")
+	appendTo := flag.String("append", "", "Append the converted pages to this PDF instead of writing -output (created if missing; not with -pdfa)")
+	noClobber := flag.Bool("no-clobber", false, "Fail with OUTPUT_EXISTS instead of replacing an existing output file")
+	overwrite := flag.Bool("overwrite", false, "Batch: let files whose output names collide overwrite each other instead of numbering them (name_2.pdf, ...)")
+	formatFlag := flag.String("format", "auto", "Force input format (csv|xlsx|xlsm|ods|pptx|png|jpeg|text|ndjson|auto)")
 	
 	// Page options
 	pageSize := flag.String("page-size", "A4", "Page size (A4|Letter|Legal|A3)")
@@ -48,51 +60,125 @@ func main() {
 	
 	// Content options
 	headerRow := flag.Bool("header", true, "Treat first row as header (CSV/Excel)")
+	headerRows := flag.Int("header-rows", 1, "Number of header rows; rows above the last are group headers whose labels span the blank cells to their right (CSV/Excel)")
+	autoHeader := flag.Bool("auto-header", false, "Decide from the data whether the first row is a header, overriding -header (CSV/Excel)")
+	tableAlign := flag.String("table-align", "left", "Table placement when narrower than the page: left, center, right")
+	perCellAlign := flag.Bool("per-cell-align", false, "Align each table cell by its own content instead of by its column's inferred type (CSV/Excel)")
+	textAlign := flag.String("text-align", "left", "Alignment of wrapped paragraphs in text and PPT output: left, justify")
+	dropEmptyRows := flag.Bool("drop-empty-rows", false, "Skip rows where every cell is blank (CSV/Excel)")
+	filter := flag.String("filter", "", "Keep only rows matching \"<column> <op> <value>\"; op is == != > < >= <= contains (e.g. \"col3 > 100\")")
+	maxMemory := flag.Int64("max-memory", 0, "Abort with MEMORY_LIMIT once this many bytes of cell data have been read (0 = unlimited)")
+	dedupe := flag.Bool("dedupe", false, "Skip exact duplicate data rows, keeping the first (CSV/Excel)")
+	noAutoWidth := flag.Bool("no-auto-width", false, "Use fixed equal-width columns instead of sizing to content (CSV/Excel)")
 	fontSize := flag.Float64("font-size", 10, "Base font size")
-	headerText := flag.String("header-text", "", "Global header text (center)")
-	footerText := flag.String("footer-text", "", "Global footer text (left)")
+	lineHeight := flag.Float64("line-height", 1.2, "Text line spacing as a multiple of the font size (text and PPT output)")
+	textColumns := flag.Int("text-columns", 1, "Flow text into this many newspaper-style columns per page (text and PPT output)")
+	paragraphSpacing := flag.Float64("paragraph-spacing", 6, "Extra points after each paragraph of slide text (PPT output)")
+	headerText := flag.String("header-text", "", "Global header text (center), supports {{page}} {{total}} {{section_page}} {{section_total}} {{date}} {{time}}")
+	footerText := flag.String("footer-text", "", "Global footer text (left), supports {{page}} {{total}} {{section_page}} {{section_total}} {{date}} {{time}}")
+	pageRange := flag.String("page-range", "", "Keep only these pages of the finished PDF, e.g. 1-10,15 or 3- (any format; not with -pdfa or -split-pages)")
+	splitPages := flag.Int("split-pages", 0, "Split the output into name_part1.pdf, name_part2.pdf, ... of at most N pages, which also bounds memory use (0=no split; native renderers only)")
+	thumbnail := flag.String("thumbnail", "", "Also render the first page of the output as a PNG at this path (needs pdftoppm or LibreOffice; not with -batch or -append)")
+	thumbnailWidth := flag.Int("thumbnail-width", 300, "Width of the -thumbnail PNG in pixels")
+	pageNumberStart := flag.Int("page-number-start", 1, "Number shown on the first page (to continue numbering from another document)")
+	headerFooterOverflow := flag.String("header-footer-overflow", "wrap", "Long header/footer text: wrap (up to 3 lines) or truncate")
+	dateFormat := flag.String("date-format", "", "Layout for {{date}} (iso|short|rfc1123|rfc3339 or a Go layout)")
+	timezone := flag.String("timezone", "", "IANA timezone for {{date}}/{{time}} (default: server local)")
+	locale := flag.String("locale", "", "Locale for booleans and number formatting (e.g. en_US, de_DE)")
+	numberLocale := flag.String("number-locale", "", "Locale the input's numbers are written in, for header detection and -filter (default -locale; ';'-delimited CSV defaults to comma decimals)")
 
 	// Advanced options
 	customFont := flag.String("font", "", "Path to custom TTF font")
+	requireFont := flag.Bool("require-font", false, "Fail with CONVERSION_FAILED instead of falling back to another font when -font can't be loaded")
 	watermarkText := flag.String("watermark-text", "", "Watermark text")
 	watermarkImage := flag.String("watermark-image", "", "Path to watermark image")
 	watermarkAlpha := flag.Float64("watermark-alpha", 0.2, "Watermark opacity (0.0-1.0)")
+	watermarkTile := flag.Bool("watermark-tile", false, "Repeat the watermark text over the whole page in a diagonal grid")
+	watermarkAngle := flag.Float64("watermark-angle", 45, "Watermark text rotation in degrees, counterclockwise (0 = horizontal)")
+	signatureImage := flag.String("signature-image", "", "Path to a signature image (PNG/JPEG) stamped as a visible signature block")
+	signatureText := flag.String("signature-text", "", "Signature block text, e.g. \"Signed by Jane Doe on {{date}}\"")
+	signaturePosition := flag.String("signature-position", "right", "Signature block position above the footer: right, left or center")
+	signaturePages := flag.String("signature-pages", "last", "Pages stamped with the signature block: last or all")
 
 	// Smart Layout
 	autoOrientation := flag.Bool("auto-orientation", true, "Automatically switch resolution if needed")
+	lineNumbers := flag.Bool("line-numbers", false, "Number each line of text/log inputs")
+	imageFit := flag.String("image-fit", "fit", "Image inputs: fit (whole image inside the margins) or fill (cover the page, cropping overflow)")
 	
 	// Styling options
 	headerColor := flag.String("header-color", "", "Header background color (hex)")
 	headerTextColor := flag.String("header-text-color", "", "Header text color (hex)")
 	rowColor := flag.String("row-color", "", "Alternating row color (hex)")
+	zebraColor := flag.String("zebra-color", "", "Shaded row color (hex, default -row-color or light gray)")
+	zebraInterval := flag.Int("zebra-interval", 2, "Shade every Nth data row (2 = alternate rows, 1 = all rows)")
 	rowTextColor := flag.String("row-text-color", "", "Row text color (hex)")
 	borderColor := flag.String("border-color", "", "Border color (hex)")
 	gridLines := flag.Bool("grid-lines", true, "Show table grid lines")
+	emptyDataMessage := flag.String("empty-data-message", "No data", "Message drawn for an empty CSV file, an empty sheet or a table whose rows were all filtered out (\"\" = none)")
+	tableCaption := flag.String("table-caption", "", "Bold caption drawn above the table")
+	legend := flag.String("legend", "", "JSON object of the codes used in the data and their descriptions, e.g. {\"A\":\"Active\",\"I\":\"Inactive\"}, listed in a table on a last page")
+	cellRange := flag.String("range", "", "Excel/ODS: convert only this block of each sheet, e.g. A1:F50, B:D or 3:10")
+	slides := flag.String("slides", "", "PowerPoint: convert only these slides, e.g. 2-5,8 or 10- (slide 10 to the end)")
+	rasterizeFallback := flag.Bool("rasterize-fallback", false, "PowerPoint, native rendering: draw slides with charts, tables, SmartArt or grouped shapes as images made by LibreOffice and pdftoppm (larger files, text not selectable)")
+	rasterDPI := flag.Int("raster-dpi", 150, "Resolution of -rasterize-fallback slide images")
+	jsonFields := flag.String("json-fields", "", "NDJSON: comma-separated keys to use as columns, in order (default: the first object's keys)")
+	transpose := flag.Bool("transpose", false, "Swap rows and columns, e.g. to show one record as a list of field/value rows (CSV/Excel/ODS/NDJSON)")
+	autolink := flag.Bool("autolink", false, "Make table cells whose text is an http(s) URL clickable links")
+	sheetTitles := flag.Bool("sheet-titles", false, "Excel: caption each sheet's table with the sheet name")
+	sheetTabColors := flag.Bool("sheet-tab-colors", false, "Excel: mark the first page of each sheet with a bar in its tab color")
+	respectFreezePanes := flag.Bool("respect-freeze-panes", false, "Excel: use the rows frozen at the top of each sheet (or its autofilter row) as the header, repeated on every page, and mark filtered columns")
+	conditionalFormatting := flag.Bool("conditional-formatting", false, "Excel: fill cells per their conditional formatting (value thresholds, top/bottom, average, color scales)")
+	comments := flag.String("comments", "off", "Excel cell comments: off, footnote (numbered notes at the bottom of the page) or annotation (PDF note icons on the cells)")
+	borderStyle := flag.String("border-style", "all", "Table lines: all, outer (frame only), horizontal (rules between rows) or none")
 	
 	// Row & Cell customization
 	rowHeight := flag.Float64("row-height", 0, "Custom row height in points (0=auto)")
 	headerHeight := flag.Float64("header-height", 0, "Custom header row height in points (0=auto)")
 	cellPadding := flag.Float64("cell-padding", 4, "Cell padding in points")
-	minColWidth := flag.Float64("min-col-width", 40, "Minimum column width in points")
-	maxColWidth := flag.Float64("max-col-width", 180, "Maximum column width in points")
+	minColWidth := flag.Float64("min-col-width", pdf.DefaultMinColumnWidth, "Minimum column width in points")
+	maxColWidth := flag.Float64("max-col-width", pdf.DefaultMaxColumnWidth, "Maximum column width in points (longer cells wrap)")
+	shrinkToFit := flag.Bool("shrink-to-fit", false, "Shrink the font of cells whose long words (URLs, hashes) don't fit the column instead of breaking them, and of slide text overflowing its box")
+	cellOverflow := flag.String("cell-overflow", "", "Cell text wider than its column: wrap (default), ellipsis, clip or shrink (same as -shrink-to-fit)")
+	breakLongWords := flag.Bool("break-long-words", true, "Break words wider than their column (long URLs, hashes) across lines in wrapped cells; false cuts them to one line ending in ...")
 	
 	// Font styling
 	headerFontSize := flag.Float64("header-font-size", 0, "Header font size (0=auto)")
 	headerFontBold := flag.Bool("header-bold", true, "Make header text bold")
+	rotateHeaders := flag.Bool("rotate-headers", false, "Draw header labels rotated 90° so columns can be as narrow as their data")
 	
 	// Batch processing
 	batchFiles := flag.String("batch", "", "Comma-separated list of input files")
 	outputDir := flag.String("output-dir", "", "Output directory for batch processing")
 	workers := flag.Int("workers", 0, "Number of parallel workers (0=auto)")
+	maxWorkers := flag.Int("max-workers", 0, "Upper bound for -workers (0=16, -1=no limit); LibreOffice jobs never run more than 4 at a time")
+	rate := flag.Float64("rate", 0, "Max batch jobs started per second across all workers (0=unlimited)")
+	failFast := flag.Bool("fail-fast", false, "Stop the batch after the first failed file; remaining files are reported as not attempted")
+	subprocess := flag.Bool("subprocess", false, "Run each batch file in its own gopdfconv process, so a crash fails only that file")
 	
 	// Other options
 	verbose := flag.Bool("verbose", false, "Enable verbose output")
 	jsonOutput := flag.Bool("json", true, "Output results as JSON")
+	quiet := flag.Bool("quiet", false, "Suppress all non-error output (the result JSON is kept only with an explicit -json)")
+	logFile := flag.String("log", "", "Write verbose/progress diagnostics to this file instead of stderr")
+	progressFD := flag.Int("progress-fd", 0, "Write {\"job\",\"percent\"} JSON progress lines to this file descriptor")
+	progressFile := flag.String("progress-file", "", "Write {\"job\",\"percent\"} JSON progress lines to this file")
 	version := flag.Bool("version", false, "Show version information")
+	capabilities := flag.Bool("capabilities", false, "Print supported formats, page sizes and orientations as JSON")
+	doctor := flag.Bool("doctor", false, "Check the external programs converting -input (or every format) needs are installed; print them as JSON and exit 1 if any is missing")
+	probe := flag.Bool("probe", false, "Print the format of -input and its number of slides or sheets as JSON, without converting it")
+	selfTest := flag.Bool("self-test", false, "Convert a generated CSV, XLSX and PPTX to check the installation; print timings and warnings as JSON and exit 1 if any conversion fails")
 	native := flag.Bool("native", false, "Force native Go conversion (skip LibreOffice)")
+	quality := flag.String("quality", "balanced", "Image quality: best, balanced, fast (images as they are), compact, small or minimum (downsampled to 150, 96 or 72 dpi JPEG)")
+	maxOutputBytes := flag.Int64("max-output-bytes", 0, "Convert again at lower -quality levels while the PDF is larger than this many bytes (0=no limit)")
+	pdfa := flag.Bool("pdfa", false, "Write archival PDF/A-1b (LibreOffice rendering only: PPTX/PPT/XLS with LibreOffice installed)")
 	libreOffice := flag.String("libreoffice", "", "Path to LibreOffice binary (for PPTX)")
+	tempDir := flag.String("temp-dir", "", "Directory for LibreOffice profiles and intermediate files (default: $TMPDIR or the OS temp dir)")
 	
 	flag.Parse()
+	if err := applyEnvDefaults(); err != nil {
+		printError(errors.NewWithDetails(errors.ErrInvalidOption, "Invalid environment variable", "", err.Error()), *jsonOutput)
+		os.Exit(1)
+	}
 	
 	// Handle version flag
 	if *version {
@@ -102,29 +188,120 @@ func main() {
 		os.Exit(0)
 	}
 	
+	// Handle capabilities flag (no input file required)
+	if *capabilities {
+		printCapabilities(*libreOffice)
+		os.Exit(0)
+	}
+	
+	// Handle probe flag (reads only the structure of -input)
+	if *probe {
+		if !runProbe(*inputFile, *jsonOutput) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	
+	// Set up output routing
+	jsonExplicit := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "json" {
+			jsonExplicit = true
+		}
+	})
+	out, err := newConsole(*jsonOutput, jsonExplicit, *verbose, *quiet, *logFile)
+	if err != nil {
+		printError(errors.Wrap(err, errors.ErrWriteFailed, "Failed to open log file"), *jsonOutput)
+		os.Exit(1)
+	}
+	defer out.Close()
+	if err := out.SetProgressChannel(*progressFD, *progressFile); err != nil {
+		printError(errors.Wrap(err, errors.ErrWriteFailed, "Failed to open progress channel"), *jsonOutput)
+		os.Exit(1)
+	}
+
 	// Build PDF options
 	opts := pdf.DefaultOptions()
 	opts.Margin = *margin
 	opts.FontSize = *fontSize
 	opts.HeaderRow = *headerRow
+	opts.HeaderRows = *headerRows
+	opts.AutoDetectHeader = *autoHeader
+	opts.AutoWidth = !*noAutoWidth
+	opts.DropEmptyRows = *dropEmptyRows
+	opts.Deduplicate = *dedupe
+	opts.Filter = *filter
+	opts.MaxMemoryBytes = *maxMemory
+	opts.Locale = *locale
+	opts.NumberLocale = *numberLocale
 	// Advanced options
 	opts.CustomFontPath = *customFont
+	opts.RequireFont = *requireFont
 	opts.WatermarkText = *watermarkText
 	opts.WatermarkImage = *watermarkImage
 	opts.WatermarkAlpha = *watermarkAlpha
+	opts.WatermarkTile = *watermarkTile
+	opts.WatermarkAngle = *watermarkAngle
+	opts.SignatureImage = *signatureImage
+	opts.SignatureText = *signatureText
+	opts.SignaturePosition = *signaturePosition
+	opts.SignaturePages = *signaturePages
+	opts.TempDir = *tempDir
+	opts.PDFA = *pdfa
+	opts.Quality = *quality
+	opts.MaxOutputBytes = *maxOutputBytes
+	if opts.TempDir != "" || os.Getenv("TMPDIR") != "" {
+		if err := converter.CheckTempDir(opts.TempDir); err != nil {
+			printError(errors.Wrap(err, errors.ErrInvalidOption, "Invalid temp directory"), *jsonOutput)
+			os.Exit(1)
+		}
+	}
 	
 	// Headers
 	opts.HeaderText = *headerText
 	opts.FooterText = *footerText
+	opts.HeaderFooterOverflow = *headerFooterOverflow
+	opts.PageNumberStart = *pageNumberStart
+	opts.MaxPagesPerFile = *splitPages
+	opts.PageRange = *pageRange
+	opts.Thumbnail = *thumbnail
+	opts.ThumbnailWidth = *thumbnailWidth
+	opts.DateFormat = *dateFormat
+	opts.Timezone = *timezone
 	opts.AutoOrientation = *autoOrientation
+	opts.ImageFit = *imageFit
+	opts.LineNumbers = *lineNumbers
 	
 	// Styling options
 	opts.HeaderColor = *headerColor
 	opts.HeaderTextColor = *headerTextColor
 	opts.RowColor = *rowColor
+	opts.ZebraColor = *zebraColor
+	opts.ZebraInterval = *zebraInterval
 	opts.RowTextColor = *rowTextColor
 	opts.BorderColor = *borderColor
 	opts.ShowGridLines = *gridLines
+	opts.BorderStyle = *borderStyle
+	opts.TableCaption = *tableCaption
+	opts.EmptyDataMessage = *emptyDataMessage
+	opts.CellRange = *cellRange
+	opts.SlideRange = *slides
+	opts.RasterizeFallback = *rasterizeFallback
+	opts.RasterDPI = *rasterDPI
+	opts.JSONFields = *jsonFields
+	opts.Transpose = *transpose
+	opts.AutolinkURLs = *autolink
+	opts.ShowSheetTitles = *sheetTitles
+	opts.SheetTabColors = *sheetTabColors
+	opts.RespectFreezePanes = *respectFreezePanes
+	opts.RenderConditionalFormatting = *conditionalFormatting
+	opts.RenderComments = *comments
+	opts.TableAlign = *tableAlign
+	opts.PerCellAlignment = *perCellAlign
+	opts.TextAlign = *textAlign
+	opts.LineHeight = *lineHeight
+	opts.ParagraphSpacing = *paragraphSpacing
+	opts.TextColumns = *textColumns
 	
 	// Row & Cell customization
 	opts.RowHeight = *rowHeight
@@ -132,23 +309,18 @@ func main() {
 	opts.CellPadding = *cellPadding
 	opts.MinColumnWidth = *minColWidth
 	opts.MaxColumnWidth = *maxColWidth
+	opts.ShrinkTextToFit = *shrinkToFit
+	opts.CellOverflow = *cellOverflow
+	opts.BreakLongWords = *breakLongWords
 	
 	// Font styling
 	opts.HeaderFontSize = *headerFontSize
 	opts.HeaderFontBold = *headerFontBold
+	opts.RotateHeaders = *rotateHeaders
 	
 	// Parse page size
-	switch strings.ToLower(*pageSize) {
-	case "a4":
-		opts.PageSize = pdf.PageA4
-	case "letter":
-		opts.PageSize = pdf.PageLetter
-	case "legal":
-		opts.PageSize = pdf.PageLegal
-	case "a3":
-		opts.PageSize = pdf.PageA3
-	case "tabloid":
-		opts.PageSize = pdf.PageTabloid
+	if size, ok := pdf.PageSizes[strings.ToLower(*pageSize)]; ok {
+		opts.PageSize = size
 	}
 	
 	// Parse orientation
@@ -158,10 +330,79 @@ func main() {
 		opts.Orientation = pdf.Portrait
 	}
 	
+	if *noClobber && *overwrite {
+		printError(errors.New(errors.ErrInvalidOption, "-no-clobber cannot be combined with -overwrite"), *jsonOutput)
+		os.Exit(1)
+	}
+	if *legend != "" {
+		if err := json.Unmarshal([]byte(*legend), &opts.Legend); err != nil {
+			printError(errors.NewWithDetails(errors.ErrInvalidOption, "-legend must be a JSON object of codes and descriptions", "", err.Error()), *jsonOutput)
+			os.Exit(1)
+		}
+	}
+	if !strings.HasSuffix(strings.ToLower(*outputSuffix), ".pdf") {
+		printError(errors.NewWithDetails(errors.ErrInvalidOption, "-output-suffix must end in .pdf", "", *outputSuffix), *jsonOutput)
+		os.Exit(1)
+	}
+	if *thumbnailWidth <= 0 {
+		printError(errors.NewWithDetails(errors.ErrInvalidOption, "-thumbnail-width must be a positive number of pixels", "", fmt.Sprint(*thumbnailWidth)), *jsonOutput)
+		os.Exit(1)
+	}
+	switch strings.ToLower(*quality) {
+	case pdf.QualityBest, pdf.QualityBalanced, pdf.QualityFast, pdf.QualityCompact, pdf.QualitySmall, pdf.QualityMinimum:
+	default:
+		printError(errors.NewWithDetails(errors.ErrInvalidOption, "-quality must be best, balanced, fast, compact, small or minimum", "", *quality), *jsonOutput)
+		os.Exit(1)
+	}
+	switch strings.ToLower(*signaturePosition) {
+	case pdf.SignatureRight, pdf.SignatureLeft, pdf.SignatureCenter:
+	default:
+		printError(errors.NewWithDetails(errors.ErrInvalidOption, "-signature-position must be right, left or center", "", *signaturePosition), *jsonOutput)
+		os.Exit(1)
+	}
+	switch strings.ToLower(*signaturePages) {
+	case pdf.SignatureLastPage, pdf.SignatureAllPages:
+	default:
+		printError(errors.NewWithDetails(errors.ErrInvalidOption, "-signature-pages must be last or all", "", *signaturePages), *jsonOutput)
+		os.Exit(1)
+	}
+	switch strings.ToLower(*comments) {
+	case pdf.CommentsOff, pdf.CommentsFootnote, pdf.CommentsAnnotation:
+	default:
+		printError(errors.NewWithDetails(errors.ErrInvalidOption, "-comments must be off, footnote or annotation", "", *comments), *jsonOutput)
+		os.Exit(1)
+	}
+	if *timezone != "" {
+		if _, err := time.LoadLocation(*timezone); err != nil {
+			printError(errors.NewWithDetails(errors.ErrInvalidOption, "-timezone must be an IANA timezone such as Europe/Berlin", "", err.Error()), *jsonOutput)
+			os.Exit(1)
+		}
+	}
+	
+	// Handle doctor flag (after the options, which may need more programs)
+	if *doctor {
+		if !runDoctor(*inputFile, *formatFlag, *libreOffice, opts, *jsonOutput) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	
+	// Handle self-test flag (after the options, which the samples are converted with)
+	if *selfTest {
+		if !runSelfTest(opts, *libreOffice, *native, *jsonOutput) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	
 	// Handle batch processing
 	if *batchFiles != "" {
+		if *thumbnail != "" {
+			printError(errors.New(errors.ErrInvalidOption, "-thumbnail cannot be combined with -batch"), *jsonOutput)
+			os.Exit(1)
+		}
 		files := strings.Split(*batchFiles, ",")
-		runBatchConversion(files, *outputDir, opts, *workers, *formatFlag, *libreOffice, *native, *jsonOutput, *verbose)
+		runBatchConversion(files, *outputDir, opts, *workers, *maxWorkers, *rate, *failFast, *subprocess, *noClobber, *overwrite, *outputSuffix, *formatFlag, *libreOffice, *native, out)
 		return
 	}
 	
@@ -172,28 +413,39 @@ func main() {
 		os.Exit(1)
 	}
 	
+	if *appendTo != "" {
+		if *splitPages > 0 {
+			printError(errors.New(errors.ErrInvalidOption, "-append cannot be combined with -split-pages"), *jsonOutput)
+			os.Exit(1)
+		}
+		if *thumbnail != "" {
+			printError(errors.New(errors.ErrInvalidOption, "-append cannot be combined with -thumbnail"), *jsonOutput)
+			os.Exit(1)
+		}
+		if *pdfa {
+			// The pages are rewritten into the existing PDF, which isn't PDF/A
+			printError(errors.New(errors.ErrInvalidOption, "-append cannot be combined with -pdfa"), *jsonOutput)
+			os.Exit(1)
+		}
+		*outputFile = *appendTo
+	}
+	
 	if *outputFile == "" {
-		// Auto-generate output filename
-		base := strings.TrimSuffix(*inputFile, filepath.Ext(*inputFile))
-		*outputFile = base + ".pdf"
+		*outputFile = outputName(*inputFile, *outputSuffix)
 	}
 	
 	// Run single conversion
-	runSingleConversion(*inputFile, *outputFile, opts, *formatFlag, *libreOffice, *native, *jsonOutput, *verbose)
+	runSingleConversion(*inputFile, *outputFile, *appendTo != "", *noClobber, opts, *formatFlag, *libreOffice, *native, out)
 }
 
-func runSingleConversion(inputPath, outputPath string, opts pdf.Options, formatFlag, libreOfficePath string, native, jsonOutput, verbose bool) {
+// runSingleConversion converts one file. With appendMode the pages are rendered to a
+// temporary file and then appended to outputPath, so noClobber doesn't apply.
+func runSingleConversion(inputPath, outputPath string, appendMode, noClobber bool, opts pdf.Options, formatFlag, libreOfficePath string, native bool, out *console) {
 	start := time.Now()
+	jsonOutput := out.jsonOutput
 	
 	// Progress callback
-	progressCallback := func(percent int) {
-		if jsonOutput {
-			// Print progress to stderr to avoid polluting stdout JSON
-			fmt.Fprintf(os.Stderr, "{\"progress\": %d}\n", percent)
-		} else if verbose {
-			fmt.Fprintf(os.Stderr, "\rProgress: %d%%", percent)
-		}
-	}
+	progressCallback := out.Progress
 	
 	// Detect format
 	var format converter.FormatType
@@ -203,120 +455,75 @@ func runSingleConversion(inputPath, outputPath string, opts pdf.Options, formatF
 		format = converter.FormatType(formatFlag)
 	}
 	
-	if verbose {
-		fmt.Fprintf(os.Stderr, "Converting %s to %s (format: %s)\n", inputPath, outputPath, format)
-	}
+	out.Verbosef("Converting %s to %s (format: %s)\n", inputPath, outputPath, format)
 	
-	var err error
+	var layout pdf.Layout // Stays empty when LibreOffice renders the PDF
+	var warnings []string
+	var stats *converter.Stats
+	var quality string
 	
-	switch format {
-	case converter.FormatCSV, converter.FormatTSV:
-		csvConverter := converter.NewCSVConverter()
-		csvConverter.SetProgressCallback(progressCallback)
-		err = csvConverter.Convert(inputPath, outputPath, opts)
-		
-	case converter.FormatXLSX, converter.FormatXLSM, converter.FormatXLS:
-		// For XLS (legacy format), convert to XLSX first using LibreOffice
-		if format == converter.FormatXLS {
-			pptxConverter := converter.NewPPTXConverter()
-			if libreOfficePath != "" {
-				pptxConverter.SetLibreOfficePath(libreOfficePath)
-			}
-			
-			if pptxConverter.HasLibreOffice() {
-				// Convert XLS to XLSX first, then process with native Excel converter
-				loConverter := converter.NewLibreOfficeConverter(pptxConverter.GetLibreOfficePath())
-				tempXlsx := inputPath + ".xlsx"
-				if convErr := loConverter.ConvertTo(inputPath, tempXlsx, "xlsx"); convErr == nil {
-					defer os.Remove(tempXlsx)
-					excelConverter := converter.NewExcelConverter()
-					excelConverter.SetProgressCallback(progressCallback)
-					err = excelConverter.Convert(tempXlsx, outputPath, opts)
-				} else {
-					// If XLSX conversion fails, try direct PDF conversion
-					err = loConverter.Convert(inputPath, outputPath)
-				}
-			} else {
-				// No LibreOffice - try native converter (may have limited support)
-				excelConverter := converter.NewExcelConverter()
-				excelConverter.SetProgressCallback(progressCallback)
-				err = excelConverter.Convert(inputPath, outputPath, opts)
-			}
-		} else {
-			// XLSX/XLSM - use native Excel converter directly
-			excelConverter := converter.NewExcelConverter()
-			excelConverter.SetProgressCallback(progressCallback)
-			err = excelConverter.Convert(inputPath, outputPath, opts)
-		}
-		
-	case converter.FormatPPTX:
-		pptxConverter := converter.NewPPTXConverter()
-		if libreOfficePath != "" {
-			pptxConverter.SetLibreOfficePath(libreOfficePath)
-		}
-		if native {
-			pptxConverter.SetForceNative(true)
+	renderPath := outputPath
+	if appendMode {
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+			printError(errors.Wrap(err, errors.ErrWriteFailed, "Failed to create output directory"), jsonOutput)
+			os.Exit(1)
 		}
-		err = pptxConverter.Convert(inputPath, outputPath, opts)
-		
-	case converter.FormatPPT:
-		// PPT (legacy format) handling
-		pptxConverter := converter.NewPPTXConverter()
-		if libreOfficePath != "" {
-			pptxConverter.SetLibreOfficePath(libreOfficePath)
+		tmp, err := os.CreateTemp(filepath.Dir(outputPath), ".gopdfconv-*.pdf")
+		if err != nil {
+			printError(errors.Wrap(err, errors.ErrWriteFailed, "Failed to create temp PDF"), jsonOutput)
+			os.Exit(1)
 		}
-		
-		if pptxConverter.HasLibreOffice() && !native {
-			// Try LibreOffice first for best results
-			loConverter := converter.NewLibreOfficeConverter(pptxConverter.GetLibreOfficePath())
-			err = loConverter.Convert(inputPath, outputPath)
-			if err != nil {
-				// If LibreOffice fails, try converting PPT to PPTX first, then to PDF
-				tempPptx := inputPath + ".pptx"
-				if convErr := loConverter.ConvertTo(inputPath, tempPptx, "pptx"); convErr == nil {
-					defer os.Remove(tempPptx)
-					pptxConverter.SetForceNative(true)
-					err = pptxConverter.Convert(tempPptx, outputPath, opts)
-				}
-			}
-		} else if pptxConverter.HasLibreOffice() && native {
-			// Native mode requested but we have LibreOffice - convert PPT to PPTX first
-			loConverter := converter.NewLibreOfficeConverter(pptxConverter.GetLibreOfficePath())
-			tempPptx := inputPath + ".pptx"
-			if convErr := loConverter.ConvertTo(inputPath, tempPptx, "pptx"); convErr == nil {
-				defer os.Remove(tempPptx)
-				pptxConverter.SetForceNative(true)
-				err = pptxConverter.Convert(tempPptx, outputPath, opts)
-			} else {
-				// Fall back to native PPT parser
-				pptConverter := converter.NewPPTConverter()
-				err = pptConverter.Convert(inputPath, outputPath, opts)
-			}
-		} else {
-			// No LibreOffice - use native PPT parser (text extraction only)
-			pptConverter := converter.NewPPTConverter()
-			err = pptConverter.Convert(inputPath, outputPath, opts)
+		tmp.Close()
+		renderPath = tmp.Name()
+		defer os.Remove(renderPath)
+	}
+	
+	conv, err := converter.Convert(inputPath, renderPath, format, opts, converter.RunConfig{
+		LibreOfficePath: libreOfficePath,
+		Native:          native,
+		OnProgress:      progressCallback,
+		NoClobber:       noClobber && !appendMode,
+	})
+	if conv != nil {
+		layout = converter.LayoutOf(conv)
+		warnings = converter.WarningsOf(conv)
+		stats = converter.StatsOf(conv)
+		quality = converter.QualityOf(conv)
+	}
+	if err == nil && appendMode {
+		if appendErr := pdf.AppendFile(outputPath, renderPath); appendErr != nil {
+			err = errors.NewWithDetails(errors.ErrWriteFailed, "Failed to append to PDF", outputPath, appendErr.Error())
 		}
-		
-	default:
-		err = errors.New(errors.ErrUnsupportedFormat, "Unsupported file format: "+string(format))
 	}
 	
 	processTime := time.Since(start).Milliseconds()
 	
 	if err != nil {
-		if convErr, ok := err.(*errors.ConversionError); ok {
+		var convErr *errors.ConversionError
+		if stderrors.As(err, &convErr) {
 			printError(convErr, jsonOutput)
 		} else {
 			printError(errors.Wrap(err, errors.ErrConversionFailed, "Conversion failed"), jsonOutput)
 		}
+		if appendMode {
+			os.Remove(renderPath) // os.Exit skips the deferred cleanup
+		}
+		out.Close()
 		os.Exit(1)
 	}
 	
-	// Get output file size
+	// Get output file size, over all parts of a split output
+	files := []string{outputPath}
+	var outputFiles []string
+	if len(layout.OutputFiles) > 1 {
+		files = layout.OutputFiles
+		outputFiles = layout.OutputFiles
+	}
 	var fileSize int64
-	if info, statErr := os.Stat(outputPath); statErr == nil {
-		fileSize = info.Size()
+	for _, path := range files {
+		if info, statErr := os.Stat(path); statErr == nil {
+			fileSize += info.Size()
+		}
 	}
 	
 	// Output success
@@ -325,23 +532,50 @@ func runSingleConversion(inputPath, outputPath string, opts pdf.Options, formatF
 		Message:     "Conversion completed successfully",
 		InputFile:   inputPath,
 		OutputFile:  outputPath,
+		OutputFiles: outputFiles,
 		Format:      string(format),
 		ProcessTime: processTime,
 		FileSize:    fileSize,
+		PageCount:   layout.PageCount,
+		Thumbnail:   opts.Thumbnail,
+		Sections:    layout.Sections,
+		Stats:       stats,
+		Quality:     quality,
+		Warnings:    warnings,
 	}
 	
+	if !out.showResult {
+		return
+	}
 	if jsonOutput {
 		data, _ := json.MarshalIndent(output, "", "  ")
 		fmt.Println(string(data))
 	} else {
-		fmt.Printf("✓ Converted %s to %s (%dms, %d bytes)\n", inputPath, outputPath, processTime, fileSize)
+		if len(outputFiles) > 0 {
+			fmt.Printf("✓ Converted %s to %d files: %s (%dms, %d bytes)\n", inputPath, len(outputFiles), strings.Join(outputFiles, ", "), processTime, fileSize)
+		} else {
+			fmt.Printf("✓ Converted %s to %s (%dms, %d bytes)\n", inputPath, outputPath, processTime, fileSize)
+		}
 	}
 }
 
-func runBatchConversion(files []string, outputDir string, opts pdf.Options, numWorkers int, formatFlag, libreOfficePath string, native, jsonOutput, verbose bool) {
+// runBatchConversion converts files with the worker pool. Files whose output names
+// collide (e.g. a/report.csv and b/report.xlsx with -output-dir) get numbered names
+// unless overwrite is set, so one doesn't silently replace another.
+func runBatchConversion(files []string, outputDir string, opts pdf.Options, numWorkers, maxWorkers int, rate float64, failFast, subprocess, noClobber, overwrite bool, outputSuffix, formatFlag, libreOfficePath string, native bool, out *console) {
+	jsonOutput := out.jsonOutput
 	if numWorkers <= 0 {
 		numWorkers = runtime.NumCPU()
 	}
+
+	var child *worker.Subprocess
+	if subprocess {
+		var err error
+		if child, err = newSubprocess(); err != nil {
+			printError(errors.Wrap(err, errors.ErrConversionFailed, "Failed to locate the gopdfconv binary"), jsonOutput)
+			os.Exit(1)
+		}
+	}
 	
 	// Create output directory if specified
 	if outputDir != "" {
@@ -353,6 +587,7 @@ func runBatchConversion(files []string, outputDir string, opts pdf.Options, numW
 	
 	// Build jobs
 	var jobs []worker.Job
+	usedOutputs := make(map[string]bool)
 	for i, inputPath := range files {
 		inputPath = strings.TrimSpace(inputPath)
 		if inputPath == "" {
@@ -360,13 +595,12 @@ func runBatchConversion(files []string, outputDir string, opts pdf.Options, numW
 		}
 		
 		// Determine output path
-		var outputPath string
+		outputPath := outputName(inputPath, outputSuffix)
 		if outputDir != "" {
-			base := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
-			outputPath = filepath.Join(outputDir, base+".pdf")
-		} else {
-			base := strings.TrimSuffix(inputPath, filepath.Ext(inputPath))
-			outputPath = base + ".pdf"
+			outputPath = filepath.Join(outputDir, filepath.Base(outputPath))
+		}
+		if !overwrite {
+			outputPath = uniqueOutputPath(outputPath, usedOutputs)
 		}
 		
 		// Detect format
@@ -391,28 +625,73 @@ func runBatchConversion(files []string, outputDir string, opts pdf.Options, numW
 		os.Exit(1)
 	}
 	
-	if verbose {
-		fmt.Fprintf(os.Stderr, "Processing %d files with %d workers\n", len(jobs), numWorkers)
-	}
+	out.Verbosef("Processing %d files with %d workers\n", len(jobs), numWorkers)
 	
 	// Run batch conversion
-	result := worker.RunBatch(jobs, numWorkers, libreOfficePath, native)
-	
-	if jsonOutput {
-		fmt.Println(result.ToJSON())
-	} else {
-		fmt.Printf("Batch conversion complete:\n")
-		fmt.Printf("  Total: %d files\n", result.TotalJobs)
-		fmt.Printf("  Success: %d\n", result.Successful)
-		fmt.Printf("  Failed: %d\n", result.Failed)
-		fmt.Printf("  Time: %dms\n", result.TotalTime.Milliseconds())
+	result := worker.RunBatch(jobs, worker.BatchOptions{
+		Workers:         numWorkers,
+		MaxWorkers:      maxWorkers,
+		LibreOfficePath: libreOfficePath,
+		Native:          native,
+		NoClobber:       noClobber,
+		RateLimit:       rate,
+		FailFast:        failFast,
+		Subprocess:      child,
+		OnProgress:      out.BatchProgress,
+		OnBatchProgress: out.BatchOverall,
+	})
+	
+	if out.showResult {
+		if jsonOutput {
+			fmt.Println(result.ToJSON())
+		} else {
+			fmt.Printf("Batch conversion complete:\n")
+			fmt.Printf("  Total: %d files\n", result.TotalJobs)
+			fmt.Printf("  Success: %d\n", result.Successful)
+			fmt.Printf("  Failed: %d\n", result.Failed)
+			fmt.Printf("  Time: %dms\n", result.TotalTime.Milliseconds())
+			for _, summary := range result.ErrorSummary {
+				fmt.Printf("  %s: %d file(s), e.g. %s\n", summary.Code, summary.Count, summary.Sample)
+			}
+			if len(result.NotAttempted) > 0 {
+				fmt.Printf("  Not attempted: %d (%s)\n", len(result.NotAttempted), strings.Join(result.NotAttempted, ", "))
+			}
+		}
 	}
 	
 	if result.Failed > 0 {
+		out.Close()
 		os.Exit(1)
 	}
 }
 
+// outputName derives the output path of an input converted without -output: its
+// format extension ("report.csv", "export.csv.gz") is replaced by suffix. Other
+// extensions are kept, so "archive.tar.gz" becomes "archive.tar.gz.pdf" rather
+// than "archive.tar.pdf".
+func outputName(inputPath, suffix string) string {
+	if converter.DetectFormat(inputPath) != converter.FormatAuto {
+		if strings.EqualFold(filepath.Ext(inputPath), ".gz") {
+			inputPath = strings.TrimSuffix(inputPath, filepath.Ext(inputPath))
+		}
+		inputPath = strings.TrimSuffix(inputPath, filepath.Ext(inputPath))
+	}
+	return inputPath + suffix
+}
+
+// uniqueOutputPath returns path, or if an earlier job of the batch already writes
+// to it, the first free numbered variant (report_2.pdf, report_3.pdf, ...). The
+// returned path is marked as used.
+func uniqueOutputPath(path string, used map[string]bool) string {
+	ext := filepath.Ext(path)
+	candidate := path
+	for n := 2; used[filepath.Clean(candidate)]; n++ {
+		candidate = fmt.Sprintf("%s_%d%s", strings.TrimSuffix(path, ext), n, ext)
+	}
+	used[filepath.Clean(candidate)] = true
+	return candidate
+}
+
 func printError(err *errors.ConversionError, jsonOutput bool) {
 	if jsonOutput {
 		output := Output{
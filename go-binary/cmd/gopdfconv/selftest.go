@@ -0,0 +1,278 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/converter"
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+// selfTestRows is the number of data rows in the generated CSV and XLSX samples,
+// enough for a table spanning two pages
+const selfTestRows = 60
+
+// SelfTestCase is the result of converting one generated sample
+type SelfTestCase struct {
+	Format      string                  `json:"format"`
+	Success     bool                    `json:"success"` // A readable PDF with at least one page was written
+	ProcessTime int64                   `json:"process_time_ms"`
+	FileSize    int64                   `json:"file_size_bytes,omitempty"`
+	PageCount   int                     `json:"page_count,omitempty"`
+	Warnings    []string                `json:"warnings,omitempty"`
+	Error       *errors.ConversionError `json:"error,omitempty"`
+}
+
+// SelfTestReport is the -self-test JSON document
+type SelfTestReport struct {
+	Version              string         `json:"version"`
+	TempDir              string         `json:"temp_dir"`       // Directory the samples were written to, then removed from
+	Font                 string         `json:"font,omitempty"` // Font file the samples were drawn with
+	LibreOfficeAvailable bool           `json:"libreoffice_available"`
+	Cases                []SelfTestCase `json:"cases"`
+	Warnings             []string       `json:"warnings,omitempty"` // Missing programs and fonts, which don't fail the test
+	TotalTime            int64          `json:"total_time_ms"`
+	Passed               bool           `json:"passed"` // Every case succeeded
+}
+
+// selfTestSamples are the inputs -self-test generates and converts, in order
+var selfTestSamples = []struct {
+	format converter.FormatType
+	name   string
+	write  func(path string) error
+}{
+	{converter.FormatCSV, "sample.csv", writeSampleCSV},
+	{converter.FormatXLSX, "sample.xlsx", writeSampleXLSX},
+	{converter.FormatPPTX, "sample.pptx", writeSamplePPTX},
+}
+
+// runSelfTest generates a CSV, an XLSX and a PPTX in a temporary directory,
+// converts each with opts and prints, as JSON, how long each took, whether it
+// produced a valid PDF and what the installation lacks (LibreOffice, system
+// fonts). It returns whether every conversion succeeded.
+func runSelfTest(opts pdf.Options, libreOfficePath string, native, jsonOutput bool) bool {
+	start := time.Now()
+	dir, err := os.MkdirTemp(opts.TempDir, "gopdfconv-self-test-*")
+	if err != nil {
+		printError(errors.Wrap(err, errors.ErrWriteFailed, "Failed to create the self-test directory"), jsonOutput)
+		return false
+	}
+	defer os.RemoveAll(dir)
+
+	report := SelfTestReport{Version: Version, TempDir: filepath.Dir(dir), Cases: []SelfTestCase{}, Passed: true}
+	if path, ok := converter.FindRequirement(converter.RequirementLibreOffice, libreOfficePath); ok {
+		report.LibreOfficeAvailable = true
+		if libreOfficePath == "" {
+			libreOfficePath = path
+		}
+	} else {
+		report.Warnings = append(report.Warnings, "LibreOffice is not installed: XLS and PPT files can't be fully converted, and PPTX slides are drawn natively")
+	}
+	for _, format := range []converter.FormatType{converter.FormatCSV, converter.FormatXLSX, converter.FormatPPTX} {
+		requirements, _ := converter.RequirementsOf(format, opts)
+		for _, name := range requirements {
+			if _, ok := converter.FindRequirement(name, libreOfficePath); !ok && name != converter.RequirementLibreOffice {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("%s is not installed: needed for %s with these options", name, format))
+			}
+		}
+	}
+
+	for _, sample := range selfTestSamples {
+		inputPath := filepath.Join(dir, sample.name)
+		outputPath := inputPath + ".pdf"
+		result := runSelfTestCase(sample.format, inputPath, outputPath, sample.write, opts, libreOfficePath, native)
+		report.Cases = append(report.Cases, result.SelfTestCase)
+		report.Passed = report.Passed && result.Success
+		if report.Font == "" && result.font != "" {
+			report.Font = result.font
+			if result.font == pdf.EmbeddedFontName {
+				report.Warnings = append(report.Warnings, "No system font was found: text is drawn with the embedded DejaVu Sans, which lacks CJK and other scripts")
+			}
+		}
+	}
+	report.TotalTime = time.Since(start).Milliseconds()
+
+	data, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(data))
+	return report.Passed
+}
+
+// selfTestResult is a SelfTestCase with the font its conversion reported
+type selfTestResult struct {
+	SelfTestCase
+	font string
+}
+
+// runSelfTestCase writes one sample with write and converts it to outputPath
+func runSelfTestCase(format converter.FormatType, inputPath, outputPath string, write func(string) error, opts pdf.Options, libreOfficePath string, native bool) selfTestResult {
+	result := selfTestResult{SelfTestCase: SelfTestCase{Format: string(format)}}
+	if err := write(inputPath); err != nil {
+		result.Error = errors.Wrap(err, errors.ErrWriteFailed, "Failed to write the sample")
+		return result
+	}
+
+	start := time.Now()
+	conv, err := converter.Convert(inputPath, outputPath, format, opts, converter.RunConfig{
+		LibreOfficePath: libreOfficePath,
+		Native:          native,
+	})
+	result.ProcessTime = time.Since(start).Milliseconds()
+	if conv != nil {
+		result.Warnings = converter.WarningsOf(conv)
+		if stats := converter.StatsOf(conv); stats != nil {
+			result.font = stats.Font
+		}
+	}
+	if err != nil {
+		var convErr *errors.ConversionError
+		if stderrors.As(err, &convErr) {
+			result.Error = convErr
+		} else {
+			result.Error = errors.Wrap(err, errors.ErrConversionFailed, "Conversion failed")
+		}
+		return result
+	}
+
+	if info, statErr := os.Stat(outputPath); statErr == nil {
+		result.FileSize = info.Size()
+	}
+	pages, err := pdf.PageCount(outputPath)
+	if err != nil || pages == 0 {
+		details := "The PDF has no pages"
+		if err != nil {
+			details = err.Error()
+		}
+		result.Error = errors.NewWithDetails(errors.ErrConversionFailed, "The output is not a valid PDF", outputPath, details)
+		return result
+	}
+	result.PageCount = pages
+	result.Success = true
+	return result
+}
+
+// sampleRow returns data row i of the CSV and XLSX samples: text with accents,
+// integers, decimals and dates
+func sampleRow(i int) []string {
+	cities := []string{"Zürich", "São Paulo", "Kraków", "Montréal", "Reykjavík"}
+	return []string{
+		fmt.Sprintf("INV-%04d", 1000+i),
+		cities[i%len(cities)],
+		fmt.Sprint(i * 7 % 100),
+		fmt.Sprintf("%.2f", float64(i)*123.45),
+		time.Date(2024, time.January, 1+i, 0, 0, 0, 0, time.UTC).Format("2006-01-02"),
+	}
+}
+
+// sampleHeader is the header row of the CSV and XLSX samples
+var sampleHeader = []string{"Invoice", "City", "Quantity", "Amount", "Date"}
+
+// writeSampleCSV writes the CSV sample
+func writeSampleCSV(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(file)
+	w.Write(sampleHeader)
+	for i := 1; i <= selfTestRows; i++ {
+		w.Write(sampleRow(i))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeSampleXLSX writes the XLSX sample: the CSV data with typed cells, a bold
+// header and a formula total, on two sheets
+func writeSampleXLSX(path string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	for _, sheet := range []string{"Sheet1", "Summary"} {
+		if sheet != "Sheet1" {
+			if _, err := f.NewSheet(sheet); err != nil {
+				return err
+			}
+		}
+		header := make([]interface{}, len(sampleHeader))
+		for i, title := range sampleHeader {
+			header[i] = title
+		}
+		f.SetSheetRow(sheet, "A1", &header)
+		f.SetRowStyle(sheet, 1, 1, bold)
+		rows := selfTestRows
+		if sheet == "Summary" {
+			rows = 5
+		}
+		for i := 1; i <= rows; i++ {
+			data := sampleRow(i)
+			date, _ := time.Parse("2006-01-02", data[4])
+			row := []interface{}{data[0], data[1], i * 7 % 100, float64(i) * 123.45, date}
+			cell, _ := excelize.CoordinatesToCellName(1, i+1)
+			f.SetSheetRow(sheet, cell, &row)
+		}
+		total, _ := excelize.CoordinatesToCellName(4, rows+2)
+		f.SetCellFormula(sheet, total, fmt.Sprintf("SUM(D2:D%d)", rows+1))
+	}
+	return f.SaveAs(path)
+}
+
+// samplePPTXParts are the parts of the PPTX sample, a title slide and a bullet
+// list slide, with only what PowerPoint files need to open
+var samplePPTXParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/ppt/presentation.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.presentation.main+xml"/><Override PartName="/ppt/slides/slide1.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slide+xml"/><Override PartName="/ppt/slides/slide2.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slide+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="ppt/presentation.xml"/></Relationships>`},
+	{"ppt/presentation.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:presentation xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"><p:sldIdLst><p:sldId id="256" r:id="rId2"/><p:sldId id="257" r:id="rId3"/></p:sldIdLst><p:sldSz cx="9144000" cy="6858000"/><p:notesSz cx="6858000" cy="9144000"/></p:presentation>`},
+	{"ppt/_rels/presentation.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide" Target="slides/slide1.xml"/><Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide" Target="slides/slide2.xml"/></Relationships>`},
+	{"ppt/slides/slide1.xml", sampleSlide(`<a:p><a:pPr algn="ctr"/><a:r><a:rPr sz="4000" b="1"/><a:t>gopdfconv self-test</a:t></a:r></a:p>`)},
+	{"ppt/slides/slide2.xml", sampleSlide(`<a:p><a:r><a:rPr sz="2800" b="1"/><a:t>Checks</a:t></a:r></a:p><a:p><a:pPr><a:buChar char="•"/></a:pPr><a:r><a:t>Fonts are found</a:t></a:r></a:p><a:p><a:pPr><a:buChar char="•"/></a:pPr><a:r><a:t>Slides are rendered</a:t></a:r></a:p><a:p><a:pPr><a:buChar char="•"/></a:pPr><a:r><a:t>The PDF can be written</a:t></a:r></a:p>`)},
+}
+
+// sampleSlide returns a slide with one text box holding the paragraphs
+func sampleSlide(paragraphs string) string {
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:sld xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"><p:cSld><p:spTree><p:nvGrpSpPr><p:cNvPr id="1" name=""/><p:cNvGrpSpPr/><p:nvPr/></p:nvGrpSpPr><p:grpSpPr/><p:sp><p:nvSpPr><p:cNvPr id="2" name="Text"/><p:cNvSpPr txBox="1"/><p:nvPr/></p:nvSpPr><p:spPr><a:xfrm><a:off x="914400" y="914400"/><a:ext cx="7315200" cy="4572000"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></p:spPr><p:txBody><a:bodyPr wrap="square"/><a:lstStyle/>` + paragraphs + `</p:txBody></p:sp></p:spTree></p:cSld></p:sld>`
+}
+
+// writeSamplePPTX writes the PPTX sample
+func writeSamplePPTX(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(file)
+	for _, part := range samplePPTXParts {
+		w, err := zw.Create(part.name)
+		if err == nil {
+			_, err = w.Write([]byte(part.content))
+		}
+		if err != nil {
+			zw.Close()
+			file.Close()
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
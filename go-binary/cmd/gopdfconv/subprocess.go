@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/worker"
+)
+
+// parentOnlyFlags are handled by the batch parent and never passed to -subprocess
+// children: batch control, per-job input/output and output routing (the child
+// always reports in JSON on stdout)
+var parentOnlyFlags = map[string]bool{
+	"input": true, "output": true, "append": true, "format": true,
+	"batch": true, "output-dir": true, "workers": true, "max-workers": true,
+	"rate": true, "fail-fast": true, "subprocess": true, "overwrite": true, "output-suffix": true,
+	"json": true, "quiet": true, "verbose": true, "log": true,
+	"progress-fd": true, "progress-file": true, "version": true, "capabilities": true, "doctor": true,
+	"self-test": true,
+}
+
+// newSubprocess returns the child command for -subprocess: this binary with every
+// conversion flag that was set, so each child converts with the parent's options
+func newSubprocess() (*worker.Subprocess, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	var args []string
+	flag.Visit(func(f *flag.Flag) {
+		if !parentOnlyFlags[f.Name] {
+			args = append(args, "-"+f.Name+"="+f.Value.String())
+		}
+	})
+	return &worker.Subprocess{Path: path, Args: args}, nil
+}
@@ -3,6 +3,7 @@ module github.com/nikunjkothiya/gopdfconv
 go 1.21
 
 require (
+	github.com/phpdave11/gofpdi v1.0.14-0.20211212211723-1f10f9844311
 	github.com/richardlehane/mscfb v1.0.4
 	github.com/signintech/gopdf v0.26.1
 	github.com/xuri/excelize/v2 v2.8.1
@@ -10,7 +11,6 @@ require (
 
 require (
 	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826 // indirect
-	github.com/phpdave11/gofpdi v1.0.14-0.20211212211723-1f10f9844311 // indirect
 	github.com/pkg/errors v0.8.1 // indirect
 	github.com/richardlehane/msoleps v1.0.3 // indirect
 	github.com/xuri/efp v0.0.0-20231025114914-d1ff6096ae53 // indirect
@@ -0,0 +1,178 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+)
+
+// cellRange is a rectangle of a sheet with 1-based inclusive bounds, from a
+// conditional formatting sqref or Options.CellRange. A nil *cellRange stands for
+// the whole sheet.
+type cellRange struct {
+	col1, row1, col2, row2 int
+}
+
+// parseCellRange parses Options.CellRange: one range such as "A1:F50", a single
+// cell, whole columns ("B:D") or whole rows ("3:10"). An empty string returns nil.
+func parseCellRange(s string) (*cellRange, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	ranges := parseSqref(s)
+	if len(ranges) != 1 || len(strings.Fields(s)) != 1 {
+		return nil, fmt.Errorf("%q is not a cell range like A1:F50", s)
+	}
+	return &ranges[0], nil
+}
+
+// cellRangeOption parses Options.CellRange, reporting a malformed range as INVALID_FORMAT
+func cellRangeOption(opts pdf.Options) (*cellRange, error) {
+	r, err := parseCellRange(opts.CellRange)
+	if err != nil {
+		return nil, errors.NewWithDetails(errors.ErrInvalidFormat, "Invalid cell range", "", err.Error())
+	}
+	return r, nil
+}
+
+// columns returns the part of a row inside the range
+func (r *cellRange) columns(row []string) []string {
+	if r == nil {
+		return row
+	}
+	if r.col1 > len(row) {
+		return nil
+	}
+	end := r.col2
+	if end > len(row) {
+		end = len(row)
+	}
+	return row[r.col1-1 : end]
+}
+
+// cutsColumns reports whether a row has text in columns outside the range
+func (r *cellRange) cutsColumns(row []string) bool {
+	for i, cell := range row {
+		if (i+1 < r.col1 || i+1 > r.col2) && strings.TrimSpace(cell) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// truncates reports whether an in-memory sheet has text outside the range
+func (r *cellRange) truncates(rows [][]string) bool {
+	if r == nil {
+		return false
+	}
+	for i, row := range rows {
+		if i+1 < r.row1 || i+1 > r.row2 {
+			if !isEmptyRow(row) {
+				return true
+			}
+		} else if r.cutsColumns(row) {
+			return true
+		}
+	}
+	return false
+}
+
+// rows returns the rows of an in-memory sheet inside the range
+func (r *cellRange) rows(rows [][]string) [][]string {
+	if r == nil {
+		return rows
+	}
+	if r.row1 > len(rows) {
+		return nil
+	}
+	end := r.row2
+	if end > len(rows) {
+		end = len(rows)
+	}
+	selected := make([][]string, 0, end-r.row1+1)
+	for _, row := range rows[r.row1-1 : end] {
+		selected = append(selected, r.columns(row))
+	}
+	return selected
+}
+
+// iterator returns rows limited to the range. Rows are counted from 1 as the
+// iterator yields them, so it must yield every sheet row, empty ones included.
+func (r *cellRange) iterator(rows pdf.RowIterator) pdf.RowIterator {
+	if r == nil {
+		return rows
+	}
+	return &rangeRowIterator{rows: rows, rng: r}
+}
+
+// rangeRowIterator is the RowIterator returned by cellRange.iterator
+type rangeRowIterator struct {
+	rows      pdf.RowIterator
+	rng       *cellRange
+	rowNum    int
+	truncated bool // Text outside the range was seen
+}
+
+func (it *rangeRowIterator) Next() bool {
+	for it.rowNum < it.rng.row2 && it.rows.Next() {
+		it.rowNum++
+		if it.rowNum >= it.rng.row1 {
+			return true
+		}
+		it.checkSkipped()
+	}
+	// Look past the last row of the range for text, until the first non-empty row
+	for it.rowNum == it.rng.row2 && !it.truncated && it.rows.Next() {
+		it.checkSkipped()
+	}
+	it.rowNum = it.rng.row2 + 1
+	return false
+}
+
+// checkSkipped notes whether the current row, outside the range, has text
+func (it *rangeRowIterator) checkSkipped() {
+	if row, err := it.rows.Columns(); err == nil && !isEmptyRow(row) {
+		it.truncated = true
+	}
+}
+
+func (it *rangeRowIterator) Columns() ([]string, error) {
+	row, err := it.rows.Columns()
+	if err == nil && !it.truncated && it.rng.cutsColumns(row) {
+		it.truncated = true
+	}
+	return it.rng.columns(row), err
+}
+
+// CellFills forwards the cell backgrounds inside the range, renumbered from its first column
+func (it *rangeRowIterator) CellFills() map[int]pdf.Color {
+	filler, ok := it.rows.(pdf.CellFiller)
+	if !ok {
+		return nil
+	}
+	fills := make(map[int]pdf.Color)
+	for col, fill := range filler.CellFills() {
+		if col+1 >= it.rng.col1 && col+1 <= it.rng.col2 {
+			fills[col+1-it.rng.col1] = fill
+		}
+	}
+	return fills
+}
+
+// CellComments forwards the cell comments inside the range, renumbered from its first column
+func (it *rangeRowIterator) CellComments() map[int]string {
+	commenter, ok := it.rows.(pdf.CellCommenter)
+	if !ok {
+		return nil
+	}
+	comments := make(map[int]string)
+	for col, text := range commenter.CellComments() {
+		if col+1 >= it.rng.col1 && col+1 <= it.rng.col2 {
+			comments[col+1-it.rng.col1] = text
+		}
+	}
+	return comments
+}
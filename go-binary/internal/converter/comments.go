@@ -0,0 +1,49 @@
+package converter
+
+import (
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// cellComments holds the comments (notes) of a sheet's cells: row number (1-based,
+// as in the sheet) -> column index (0-based, as in the row) -> text
+type cellComments map[int]map[int]string
+
+// loadCellComments returns the comments of a sheet, or nil if it has none. The
+// text of each is prefixed with its author unless it already starts with it, as
+// Excel does for notes.
+func loadCellComments(f *excelize.File, sheet string) (cellComments, error) {
+	list, err := f.GetComments(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments cellComments
+	for _, comment := range list {
+		col, row, err := excelize.CellNameToCoordinates(comment.Cell)
+		if err != nil {
+			continue
+		}
+		text := comment.Text
+		for _, run := range comment.Paragraph {
+			text += run.Text
+		}
+		text = strings.Join(strings.Fields(text), " ")
+		if text == "" {
+			continue
+		}
+		if comment.Author != "" && !strings.HasPrefix(text, comment.Author) {
+			text = comment.Author + ": " + text
+		}
+
+		if comments == nil {
+			comments = make(cellComments)
+		}
+		if comments[row] == nil {
+			comments[row] = make(map[int]string)
+		}
+		comments[row][col-1] = text
+	}
+	return comments, nil
+}
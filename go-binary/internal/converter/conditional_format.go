@@ -0,0 +1,420 @@
+package converter
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/xuri/excelize/v2"
+)
+
+// cellFills holds the conditional-formatting background of cells: row number
+// (1-based, as in the sheet) -> column index (0-based, as in the row) -> fill
+type cellFills map[int]map[int]pdf.Color
+
+// fillRule is one conditional formatting rule that results in a solid cell fill
+type fillRule struct {
+	ranges []cellRange
+	opts   excelize.ConditionalFormatOptions
+	fill   pdf.Color // Threshold, top/bottom and average rules
+
+	// Color scales
+	stops  []float64
+	colors []pdf.Color
+
+	cells  []ruleCell // Numeric cells of the ranges, collected by the sheet pass
+	values []float64  // Their values, sorted
+	mean   float64
+}
+
+type ruleCell struct {
+	row, col int
+	value    float64
+}
+
+// loadConditionalFills evaluates the conditional formatting of a sheet and returns
+// the resulting cell fills, or nil if there are none. Supported rules are cell value
+// thresholds, top/bottom N (%), above/below average and 2/3-color scales; formulas,
+// text and date rules, data bars and icon sets are ignored. Where several rules
+// apply to a cell, the first one in the sheet's rule order gives the fill.
+func loadConditionalFills(f *excelize.File, sheet string) (cellFills, error) {
+	formats, err := f.GetConditionalFormats(sheet)
+	if err != nil || len(formats) == 0 {
+		return nil, err
+	}
+
+	// GetConditionalFormats returns a map; sort the ranges so overlaps resolve the same way every time
+	refs := make([]string, 0, len(formats))
+	for ref := range formats {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	var rules []*fillRule
+	for _, ref := range refs {
+		ranges := parseSqref(ref)
+		if len(ranges) == 0 {
+			continue
+		}
+		for _, opts := range formats[ref] {
+			if rule := newFillRule(f, ranges, opts); rule != nil {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	if err := collectRuleCells(f, sheet, rules); err != nil {
+		return nil, err
+	}
+
+	fills := make(cellFills)
+	for _, rule := range rules {
+		rule.prepare()
+		for _, cell := range rule.cells {
+			color, ok := rule.evaluate(cell.value)
+			if !ok {
+				continue
+			}
+			row := fills[cell.row]
+			if row == nil {
+				row = make(map[int]pdf.Color)
+				fills[cell.row] = row
+			}
+			if _, taken := row[cell.col-1]; !taken {
+				row[cell.col-1] = color
+			}
+		}
+		rule.cells, rule.values = nil, nil
+	}
+	return fills, nil
+}
+
+// newFillRule returns the rule for opts, or nil if it isn't supported or has no fill
+func newFillRule(f *excelize.File, ranges []cellRange, opts excelize.ConditionalFormatOptions) *fillRule {
+	rule := &fillRule{ranges: ranges, opts: opts}
+
+	switch opts.Type {
+	case "2_color_scale", "3_color_scale":
+		colors := []string{opts.MinColor, opts.MaxColor}
+		if opts.Type == "3_color_scale" {
+			colors = []string{opts.MinColor, opts.MidColor, opts.MaxColor}
+		}
+		for _, hex := range colors {
+			hex = rgbHex(hex)
+			if hex == "" {
+				return nil
+			}
+			rule.colors = append(rule.colors, pdf.ParseHexColor(hex))
+		}
+		return rule
+
+	case "cell":
+		if _, ok := thresholdMatch(opts, 0); !ok {
+			return nil
+		}
+	case "top", "bottom", "average":
+	default:
+		return nil
+	}
+
+	style, err := f.GetConditionalStyle(opts.Format)
+	if err != nil || style.Fill.Type != "pattern" || style.Fill.Pattern != 1 || len(style.Fill.Color) == 0 {
+		return nil // Not a solid fill (e.g. a font-only rule)
+	}
+	hex := rgbHex(style.Fill.Color[0])
+	if hex == "" {
+		return nil
+	}
+	rule.fill = pdf.ParseHexColor(hex)
+	return rule
+}
+
+// collectRuleCells reads the raw values of the sheet once and gives every rule the
+// numeric cells in its ranges
+func collectRuleCells(f *excelize.File, sheet string, rules []*fillRule) error {
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	rowNum := 0
+	for rows.Next() {
+		rowNum++
+		cols, err := rows.Columns(excelize.Options{RawCellValue: true})
+		if err != nil {
+			continue
+		}
+		for i, raw := range cols {
+			value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+			if err != nil {
+				continue
+			}
+			for _, rule := range rules {
+				if rule.contains(i+1, rowNum) {
+					rule.cells = append(rule.cells, ruleCell{row: rowNum, col: i + 1, value: value})
+				}
+			}
+		}
+	}
+	return rows.Error()
+}
+
+func (r *fillRule) contains(col, row int) bool {
+	for _, cr := range r.ranges {
+		if col >= cr.col1 && col <= cr.col2 && row >= cr.row1 && row <= cr.row2 {
+			return true
+		}
+	}
+	return false
+}
+
+// prepare resolves what depends on all values of the ranges: the sorted values
+// for top/bottom, the mean for average, and the stops of a color scale
+func (r *fillRule) prepare() {
+	r.values = make([]float64, len(r.cells))
+	for i, cell := range r.cells {
+		r.values[i] = cell.value
+	}
+	sort.Float64s(r.values)
+	if len(r.values) > 0 {
+		sum := 0.0
+		for _, v := range r.values {
+			sum += v
+		}
+		r.mean = sum / float64(len(r.values))
+	}
+
+	if len(r.colors) == 0 || len(r.values) == 0 {
+		return
+	}
+	types := []string{r.opts.MinType, r.opts.MaxType}
+	values := []string{r.opts.MinValue, r.opts.MaxValue}
+	if len(r.colors) == 3 {
+		types = []string{r.opts.MinType, r.opts.MidType, r.opts.MaxType}
+		values = []string{r.opts.MinValue, r.opts.MidValue, r.opts.MaxValue}
+	}
+	r.stops = nil
+	for i := range types {
+		stop, ok := r.scaleStop(types[i], values[i])
+		if !ok {
+			r.colors = nil // A formula stop: leave the scale out
+			return
+		}
+		r.stops = append(r.stops, stop)
+	}
+}
+
+// scaleStop returns the value of a color scale stop
+func (r *fillRule) scaleStop(kind, value string) (float64, bool) {
+	lo, hi := r.values[0], r.values[len(r.values)-1]
+	n := 0.0
+	if value != "" {
+		var err error
+		if n, err = strconv.ParseFloat(value, 64); err != nil {
+			return 0, false
+		}
+	}
+
+	switch kind {
+	case "min":
+		return lo, true
+	case "max":
+		return hi, true
+	case "num":
+		return n, true
+	case "percent":
+		return lo + (hi-lo)*n/100, true
+	case "percentile":
+		return percentile(r.values, n), true
+	}
+	return 0, false
+}
+
+// percentile interpolates like Excel's PERCENTILE.INC; values must be sorted
+func percentile(values []float64, p float64) float64 {
+	pos := p / 100 * float64(len(values)-1)
+	i := int(math.Floor(pos))
+	if i < 0 {
+		return values[0]
+	}
+	if i >= len(values)-1 {
+		return values[len(values)-1]
+	}
+	return values[i] + (values[i+1]-values[i])*(pos-float64(i))
+}
+
+// evaluate returns the fill of a cell with the given value, if the rule applies
+func (r *fillRule) evaluate(value float64) (pdf.Color, bool) {
+	switch r.opts.Type {
+	case "2_color_scale", "3_color_scale":
+		if len(r.colors) == 0 {
+			return pdf.Color{}, false
+		}
+		return scaleColor(value, r.stops, r.colors), true
+
+	case "cell":
+		matched, _ := thresholdMatch(r.opts, value)
+		return r.fill, matched
+
+	case "top", "bottom":
+		rank, err := strconv.Atoi(r.opts.Value)
+		if err != nil || rank <= 0 || len(r.values) == 0 {
+			return pdf.Color{}, false
+		}
+		if r.opts.Percent {
+			rank = int(float64(len(r.values)) * float64(rank) / 100)
+			if rank < 1 {
+				rank = 1
+			}
+		}
+		if rank > len(r.values) {
+			rank = len(r.values)
+		}
+		if r.opts.Type == "top" {
+			return r.fill, value >= r.values[len(r.values)-rank]
+		}
+		return r.fill, value <= r.values[rank-1]
+
+	case "average":
+		if r.opts.AboveAverage {
+			return r.fill, value > r.mean
+		}
+		return r.fill, value < r.mean
+	}
+	return pdf.Color{}, false
+}
+
+// thresholdMatch compares value with a "cell" rule. ok is false when the rule
+// can't be evaluated, i.e. its operand is a formula or cell reference.
+func thresholdMatch(opts excelize.ConditionalFormatOptions, value float64) (matched, ok bool) {
+	if opts.Criteria == "between" || opts.Criteria == "not between" {
+		lo, err1 := strconv.ParseFloat(opts.MinValue, 64)
+		hi, err2 := strconv.ParseFloat(opts.MaxValue, 64)
+		if err1 != nil || err2 != nil {
+			return false, false
+		}
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		inside := value >= lo && value <= hi
+		return inside == (opts.Criteria == "between"), true
+	}
+
+	operand, err := strconv.ParseFloat(opts.Value, 64)
+	if err != nil {
+		return false, false
+	}
+	switch opts.Criteria {
+	case "equal to":
+		return value == operand, true
+	case "not equal to":
+		return value != operand, true
+	case "greater than":
+		return value > operand, true
+	case "less than":
+		return value < operand, true
+	case "greater than or equal to":
+		return value >= operand, true
+	case "less than or equal to":
+		return value <= operand, true
+	}
+	return false, false
+}
+
+// scaleColor interpolates the color of value between the stops of a color scale
+func scaleColor(value float64, stops []float64, colors []pdf.Color) pdf.Color {
+	if value <= stops[0] {
+		return colors[0]
+	}
+	for i := 1; i < len(stops); i++ {
+		if value > stops[i] {
+			continue
+		}
+		span := stops[i] - stops[i-1]
+		if span <= 0 {
+			return colors[i]
+		}
+		t := (value - stops[i-1]) / span
+		return blend(colors[i-1], colors[i], t)
+	}
+	return colors[len(colors)-1]
+}
+
+// blend mixes two colors, t = 0 giving a and t = 1 giving b
+func blend(a, b pdf.Color, t float64) pdf.Color {
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*t))
+	}
+	return pdf.Color{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B)}
+}
+
+// parseSqref parses a space-separated list of cell ranges such as "A1:B10 D4".
+// Whole columns ("C:C") and rows ("3:5") are accepted too.
+func parseSqref(sqref string) []cellRange {
+	var ranges []cellRange
+	for _, ref := range strings.Fields(sqref) {
+		first, last, found := strings.Cut(ref, ":")
+		if !found {
+			last = first
+		}
+		col1, row1, ok1 := rangeCorner(first, 1)
+		col2, row2, ok2 := rangeCorner(last, 0)
+		if !ok1 || !ok2 {
+			continue
+		}
+		if col1 > col2 {
+			col1, col2 = col2, col1
+		}
+		if row1 > row2 {
+			row1, row2 = row2, row1
+		}
+		ranges = append(ranges, cellRange{col1: col1, row1: row1, col2: col2, row2: row2})
+	}
+	return ranges
+}
+
+// rangeCorner parses one end of a range. A column or row alone extends to the
+// first (edge = 1) or last (edge = 0) row or column of the sheet.
+func rangeCorner(ref string, edge int) (col, row int, ok bool) {
+	ref = strings.ReplaceAll(ref, "$", "")
+	if col, row, err := excelize.CellNameToCoordinates(ref); err == nil {
+		return col, row, true
+	}
+
+	if col, err := excelize.ColumnNameToNumber(ref); err == nil {
+		if edge == 1 {
+			return col, 1, true
+		}
+		return col, excelize.TotalRows, true
+	}
+	if row, err := strconv.Atoi(ref); err == nil && row > 0 {
+		if edge == 1 {
+			return 1, row, true
+		}
+		return excelize.MaxColumns, row, true
+	}
+	return 0, 0, false
+}
+
+// rgbHex returns an Excel color ("#RRGGBB", "RRGGBB" or ARGB "AARRGGBB") as
+// 6-digit hex, or "" if it isn't one
+func rgbHex(color string) string {
+	color = strings.TrimPrefix(color, "#")
+	// ARGB values carry a leading alpha byte
+	if len(color) == 8 {
+		color = color[2:]
+	}
+	if len(color) != 6 {
+		return ""
+	}
+	if _, err := strconv.ParseUint(color, 16, 32); err != nil {
+		return ""
+	}
+	return color
+}
@@ -0,0 +1,180 @@
+package converter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+)
+
+var (
+	red   = pdf.Color{R: 255}
+	green = pdf.Color{G: 255}
+	white = pdf.Color{R: 255, G: 255, B: 255}
+)
+
+// sheetFills writes the values 1 to 10 to A1:A10, formats them with rules and
+// returns the fills loadConditionalFills finds, by row
+func sheetFills(t *testing.T, rules []excelize.ConditionalFormatOptions) map[int]pdf.Color {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	for row := 1; row <= 10; row++ {
+		cell, _ := excelize.CoordinatesToCellName(1, row)
+		f.SetCellValue("Sheet1", cell, row)
+	}
+	format, err := f.NewConditionalStyle(&excelize.Style{Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FF0000"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range rules {
+		rules[i].Format = format
+		if rules[i].Criteria == "" {
+			rules[i].Criteria = "=" // Required by SetConditionalFormat, ignored for these types
+		}
+	}
+	if err := f.SetConditionalFormat("Sheet1", "A1:A10", rules); err != nil {
+		t.Fatalf("SetConditionalFormat: %v", err)
+	}
+
+	fills, err := loadConditionalFills(f, "Sheet1")
+	if err != nil {
+		t.Fatalf("loadConditionalFills: %v", err)
+	}
+	byRow := make(map[int]pdf.Color)
+	for row, cols := range fills {
+		for col, color := range cols {
+			if col != 0 {
+				t.Errorf("fill in column %d, outside the formatted range", col)
+			}
+			byRow[row] = color
+		}
+	}
+	return byRow
+}
+
+func TestConditionalFillRules(t *testing.T) {
+	tests := []struct {
+		name string
+		rule excelize.ConditionalFormatOptions
+		rows []int // Rows filled red
+	}{
+		{"greater than", excelize.ConditionalFormatOptions{Type: "cell", Criteria: ">", Value: "7"}, []int{8, 9, 10}},
+		{"less than or equal to", excelize.ConditionalFormatOptions{Type: "cell", Criteria: "<=", Value: "2"}, []int{1, 2}},
+		{"equal to", excelize.ConditionalFormatOptions{Type: "cell", Criteria: "==", Value: "5"}, []int{5}},
+		{"between", excelize.ConditionalFormatOptions{Type: "cell", Criteria: "between", MinValue: "4", MaxValue: "6"}, []int{4, 5, 6}},
+		{"not between", excelize.ConditionalFormatOptions{Type: "cell", Criteria: "not between", MinValue: "2", MaxValue: "9"}, []int{1, 10}},
+		{"formula operand", excelize.ConditionalFormatOptions{Type: "cell", Criteria: ">", Value: "$B$1"}, nil},
+		{"top 3", excelize.ConditionalFormatOptions{Type: "top", Value: "3"}, []int{8, 9, 10}},
+		{"bottom 2", excelize.ConditionalFormatOptions{Type: "bottom", Value: "2"}, []int{1, 2}},
+		{"top 20%", excelize.ConditionalFormatOptions{Type: "top", Value: "20", Percent: true}, []int{9, 10}},
+		{"bottom 1% keeps one", excelize.ConditionalFormatOptions{Type: "bottom", Value: "1", Percent: true}, []int{1}},
+		{"above average", excelize.ConditionalFormatOptions{Type: "average", AboveAverage: true}, []int{6, 7, 8, 9, 10}},
+		{"below average", excelize.ConditionalFormatOptions{Type: "average"}, []int{1, 2, 3, 4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fills := sheetFills(t, []excelize.ConditionalFormatOptions{tt.rule})
+			want := make(map[int]pdf.Color)
+			for _, row := range tt.rows {
+				want[row] = red
+			}
+			if !reflect.DeepEqual(fills, want) {
+				t.Errorf("fills %v, want rows %v red", fills, tt.rows)
+			}
+		})
+	}
+}
+
+func TestConditionalColorScales(t *testing.T) {
+	tests := []struct {
+		name string
+		rule excelize.ConditionalFormatOptions
+		want map[int]pdf.Color
+	}{
+		{
+			"2-color min to max",
+			excelize.ConditionalFormatOptions{Type: "2_color_scale", MinType: "min", MaxType: "max", MinColor: "#FFFFFF", MaxColor: "#FF0000"},
+			map[int]pdf.Color{1: white, 10: red},
+		},
+		{
+			"2-color numbers",
+			excelize.ConditionalFormatOptions{Type: "2_color_scale", MinType: "num", MinValue: "3", MaxType: "num", MaxValue: "5", MinColor: "#FFFFFF", MaxColor: "#FF0000"},
+			map[int]pdf.Color{1: white, 3: white, 4: {R: 255, G: 128, B: 128}, 5: red, 10: red},
+		},
+		{
+			"3-color percentile midpoint",
+			excelize.ConditionalFormatOptions{Type: "3_color_scale", MinType: "min", MidType: "percentile", MidValue: "50", MaxType: "max", MinColor: "#FF0000", MidColor: "#FFFFFF", MaxColor: "#00FF00"},
+			map[int]pdf.Color{1: red, 10: green},
+		},
+		{
+			"3-color percent midpoint",
+			excelize.ConditionalFormatOptions{Type: "3_color_scale", MinType: "num", MinValue: "0", MidType: "percent", MidValue: "50", MaxType: "num", MaxValue: "10", MinColor: "#FF0000", MidColor: "#FFFFFF", MaxColor: "#00FF00"},
+			map[int]pdf.Color{10: green},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fills := sheetFills(t, []excelize.ConditionalFormatOptions{tt.rule})
+			if len(fills) != 10 {
+				t.Errorf("%d cells filled, want all 10", len(fills))
+			}
+			for row, want := range tt.want {
+				if fills[row] != want {
+					t.Errorf("row %d filled %v, want %v", row, fills[row], want)
+				}
+			}
+		})
+	}
+}
+
+// TestConditionalFirstRuleWins checks that where rules overlap, the first one in
+// the sheet's order gives the fill
+func TestConditionalFirstRuleWins(t *testing.T) {
+	fills := sheetFills(t, []excelize.ConditionalFormatOptions{
+		{Type: "cell", Criteria: ">", Value: "8"},
+		{Type: "2_color_scale", MinType: "min", MaxType: "max", MinColor: "#FFFFFF", MaxColor: "#00FF00"},
+	})
+	if fills[9] != red || fills[10] != red {
+		t.Errorf("rows 9 and 10 filled %v and %v, want the threshold rule's red", fills[9], fills[10])
+	}
+	if fills[1] != white {
+		t.Errorf("row 1 filled %v, want the color scale's white", fills[1])
+	}
+}
+
+func TestScaleColor(t *testing.T) {
+	black := pdf.Color{}
+	tests := []struct {
+		name   string
+		value  float64
+		stops  []float64
+		colors []pdf.Color
+		want   pdf.Color
+	}{
+		{"below the first stop", -5, []float64{0, 10}, []pdf.Color{black, white}, black},
+		{"above the last stop", 15, []float64{0, 10}, []pdf.Color{black, white}, white},
+		{"halfway", 5, []float64{0, 10}, []pdf.Color{black, white}, pdf.Color{R: 128, G: 128, B: 128}},
+		{"quarter way", 2.5, []float64{0, 10}, []pdf.Color{black, red}, pdf.Color{R: 64}},
+		{"second segment", 15, []float64{0, 10, 20}, []pdf.Color{red, white, green}, pdf.Color{R: 128, G: 255, B: 128}},
+		{"empty segment", 10, []float64{0, 10, 10}, []pdf.Color{red, white, green}, white},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scaleColor(tt.value, tt.stops, tt.colors); got != tt.want {
+				t.Errorf("scaleColor = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	for p, want := range map[float64]float64{0: 1, 50: 5.5, 100: 10, 25: 3.25} {
+		if got := percentile(values, p); got != want {
+			t.Errorf("percentile(%v) = %v, want %v", p, got, want)
+		}
+	}
+}
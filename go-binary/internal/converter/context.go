@@ -0,0 +1,33 @@
+package converter
+
+import (
+	"context"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+)
+
+// contextError returns the error a conversion of inputPath stops with once ctx is
+// done: TIMEOUT past its deadline, CONVERSION_FAILED when it was cancelled. It is
+// nil while ctx is live.
+func contextError(ctx context.Context, inputPath string) error {
+	switch ctx.Err() {
+	case nil:
+		return nil
+	case context.DeadlineExceeded:
+		return errors.NewWithFile(errors.ErrTimeout, "Conversion timed out", inputPath)
+	}
+	return errors.NewWithFile(errors.ErrConversionFailed, "Conversion was cancelled", inputPath)
+}
+
+// convertCtx runs c with ctx: through ConvertCtx when c is a ContextConverter,
+// otherwise through Convert, started only while ctx is live
+func convertCtx(ctx context.Context, c Converter, inputPath, outputPath string, opts pdf.Options) error {
+	if cc, ok := c.(ContextConverter); ok {
+		return cc.ConvertCtx(ctx, inputPath, outputPath, opts)
+	}
+	if err := contextError(ctx, inputPath); err != nil {
+		return err
+	}
+	return c.Convert(inputPath, outputPath, opts)
+}
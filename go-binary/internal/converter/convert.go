@@ -0,0 +1,214 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+)
+
+// RunConfig holds per-run settings that are not PDF layout options
+type RunConfig struct {
+	LibreOfficePath string    // Explicit LibreOffice binary (empty = auto-detect)
+	Native          bool      // Skip LibreOffice where a native renderer exists
+	OnProgress      func(int) // Progress callback for converters that support it
+	NoClobber       bool      // Fail with OUTPUT_EXISTS instead of replacing an existing output file
+}
+
+// Convert is the shared conversion entry point used by the CLI and the worker pool.
+// It picks the registered converter for format (detected from inputPath when
+// FormatAuto or empty), applies cfg (failing before any work if the output exists
+// and cfg.NoClobber is set) and runs the Options.PreProcess/PostProcess hooks.
+// Options.Thumbnail is rendered before PostProcess, from the finished output.
+//
+// Cleanup contract: if PreProcess returns a path other than inputPath, that file is
+// treated as a temporary substitute and removed once conversion finishes, whether it
+// succeeded or not. PostProcess runs only after a successful conversion and owns
+// whatever it does with the output (the output file itself is never removed here).
+//
+// The converter is returned so callers can read its Layout and Warnings.
+func Convert(inputPath, outputPath string, format FormatType, opts pdf.Options, cfg RunConfig) (Converter, error) {
+	return ConvertCtx(context.Background(), inputPath, outputPath, format, opts, cfg)
+}
+
+// ConvertCtx is Convert, failing once ctx is done: with TIMEOUT past its deadline,
+// otherwise CONVERSION_FAILED. Converters stop between rows, sheets or slides and
+// LibreOffice is killed; the output is not written.
+func ConvertCtx(ctx context.Context, inputPath, outputPath string, format FormatType, opts pdf.Options, cfg RunConfig) (Converter, error) {
+	// Detect from the original path; a substitute from PreProcess may lack an extension
+	format = ResolveFormat(format, inputPath)
+
+	conv, err := GetConverter(format)
+	if err != nil {
+		return nil, err
+	}
+	Configure(conv, cfg.LibreOfficePath, cfg.Native, cfg.OnProgress)
+	if opts.PDFA && !UsesLibreOffice(format, cfg.Native) {
+		return conv, pdfaUnsupported(inputPath)
+	}
+	pages, err := pageRangeOption(opts)
+	if err != nil {
+		return conv, err
+	}
+	if pages != nil && (opts.PDFA || opts.MaxPagesPerFile > 0) {
+		return conv, errors.NewWithDetails(errors.ErrInvalidOption, "Page range cannot be combined with PDF/A or split output", "",
+			"Trimming rewrites the PDF, which would drop PDF/A conformance, and a split output has no single page sequence")
+	}
+	var thumbnails *thumbnailer
+	if opts.Thumbnail != "" {
+		if thumbnails, err = newThumbnailer(cfg.LibreOfficePath, opts.TempDir); err != nil {
+			return conv, err
+		}
+	}
+	if cfg.NoClobber {
+		if _, err := os.Stat(outputPath); err == nil {
+			return conv, errors.NewWithDetails(errors.ErrOutputExists, "Output file already exists", outputPath,
+				"Remove it, choose another output path, or run without -no-clobber to replace it")
+		}
+	}
+
+	sourcePath := inputPath
+	if opts.PreProcess != nil {
+		sourcePath, err = opts.PreProcess(inputPath)
+		if err != nil {
+			return conv, errors.Wrap(err, errors.ErrConversionFailed, "Pre-process hook failed")
+		}
+		if sourcePath != inputPath {
+			defer os.Remove(sourcePath)
+		}
+	}
+
+	if conv, err = render(ctx, conv, sourcePath, outputPath, opts, pages); err != nil {
+		return conv, err
+	}
+	if layout := LayoutOf(conv); opts.PDFA && layout.PageCount > 0 {
+		// LibreOffice was missing and the file was rendered natively instead
+		os.Remove(outputPath)
+		for _, path := range layout.OutputFiles {
+			os.Remove(path)
+		}
+		return conv, pdfaUnsupported(inputPath)
+	}
+	if opts.MaxOutputBytes > 0 {
+		if conv, err = fitOutputSize(ctx, conv, format, sourcePath, outputPath, opts, cfg, pages); err != nil {
+			return conv, err
+		}
+	}
+
+	if thumbnails != nil {
+		// The first page of a split output is in its first part
+		firstPath := outputPath
+		if files := LayoutOf(conv).OutputFiles; len(files) > 0 {
+			firstPath = files[0]
+		}
+		if err := thumbnails.render(ctx, firstPath, opts.Thumbnail, opts.ThumbnailWidth); err != nil {
+			return conv, err
+		}
+	}
+
+	if opts.PostProcess != nil {
+		if err := opts.PostProcess(outputPath); err != nil {
+			return conv, errors.Wrap(err, errors.ErrConversionFailed, "Post-process hook failed")
+		}
+	}
+
+	return conv, nil
+}
+
+// render runs conv and completes its output: the font it reports and the pages
+// kept by pages, if not nil. A panic in the converter, such as gopdf choking on a
+// bad coordinate, fails the conversion with CONVERSION_FAILED instead of taking
+// down the process, and whatever output it left is removed.
+func render(ctx context.Context, conv Converter, sourcePath, outputPath string, opts pdf.Options, pages pageRange) (rendered Converter, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			os.Remove(outputPath)
+			rendered, err = conv, errors.NewWithDetails(errors.ErrConversionFailed, "Conversion failed unexpectedly", sourcePath, fmt.Sprint(r))
+		}
+	}()
+
+	if err := convertCtx(ctx, conv, sourcePath, outputPath, opts); err != nil {
+		return conv, err
+	}
+	layout := LayoutOf(conv)
+	if stats := StatsOf(conv); stats != nil {
+		stats.Font, stats.FontSubstituted = layout.Font, layout.FontSubstituted
+	}
+	if pages != nil {
+		trimmed, err := trimPages(conv, outputPath, pages)
+		if err != nil {
+			os.Remove(outputPath)
+		}
+		return trimmed, err
+	}
+	return conv, nil
+}
+
+// adjustedConverter reports what Convert changed about a converter's output
+type adjustedConverter struct {
+	Converter
+	layout   *pdf.Layout // Layout after trimPages (nil = the converter's own)
+	quality  string      // Options.Quality the output was written at, set with Options.MaxOutputBytes
+	warnings []string    // Added to the converter's own
+}
+
+func (c *adjustedConverter) Layout() pdf.Layout {
+	if c.layout != nil {
+		return *c.layout
+	}
+	return LayoutOf(c.Converter)
+}
+
+func (c *adjustedConverter) Stats() *Stats { return StatsOf(c.Converter) }
+
+func (c *adjustedConverter) Warnings() []string {
+	return append(WarningsOf(c.Converter), c.warnings...)
+}
+
+func (c *adjustedConverter) Quality() string {
+	if c.quality != "" {
+		return c.quality
+	}
+	return QualityOf(c.Converter)
+}
+
+// QualityOf returns the Options.Quality level a converter's output was written at
+// when Options.MaxOutputBytes was set, or ""
+func QualityOf(c Converter) string {
+	if a, ok := c.(*adjustedConverter); ok {
+		return a.Quality()
+	}
+	return ""
+}
+
+// pdfaUnsupported is the error for Options.PDFA with a file that would be rendered natively
+func pdfaUnsupported(inputPath string) error {
+	return errors.NewWithDetails(errors.ErrUnsupportedFormat, "PDF/A output is not supported by native rendering", inputPath,
+		"Only LibreOffice rendering can write PDF/A-1b: convert PPTX, PPT or XLS files with LibreOffice installed and without -native")
+}
+
+// CheckTempDir verifies that temporary files can be created in dir ("" = $TMPDIR or
+// the OS default), so a read-only or missing temp directory fails up front with a
+// clear error rather than midway through a LibreOffice conversion.
+func CheckTempDir(dir string) error {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return errors.NewWithDetails(errors.ErrInvalidOption, "Temp directory does not exist", dir, err.Error())
+	}
+	if !info.IsDir() {
+		return errors.NewWithFile(errors.ErrInvalidOption, "Temp directory is not a directory", dir)
+	}
+
+	file, err := os.CreateTemp(dir, ".gopdfconv-check-*")
+	if err != nil {
+		return errors.NewWithDetails(errors.ErrInvalidOption, "Temp directory is not writable", dir, err.Error())
+	}
+	file.Close()
+	os.Remove(file.Name())
+	return nil
+}
@@ -0,0 +1,150 @@
+package converter
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+)
+
+// testMeasurer returns the cell width function the CSV converter measures with
+func testMeasurer(t testing.TB) func(string) float64 {
+	t.Helper()
+	builder, err := pdf.NewBuilder(pdf.DefaultOptions())
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	return builder.TextMeasurer().Width
+}
+
+// wideSheet returns rows × cols cells of mixed text and numbers
+func wideSheet(rows, cols int) [][]string {
+	sheet := make([][]string, rows)
+	for i := range sheet {
+		sheet[i] = make([]string, cols)
+		for j := range sheet[i] {
+			if j%3 == 0 {
+				sheet[i][j] = fmt.Sprintf("item %d of column %d", i, j)
+			} else {
+				sheet[i][j] = fmt.Sprintf("%d.%02d", i*j, i%100)
+			}
+		}
+	}
+	return sheet
+}
+
+func TestMeasureColumnsRecoversPanic(t *testing.T) {
+	sheet := wideSheet(10, 4*minColumnsPerWorker)
+	widths, err := measureColumns(sheet, len(sheet[0]), func(cell string) float64 {
+		if cell == "item 5 of column 33" {
+			panic("bad glyph")
+		}
+		return float64(len(cell))
+	})
+	if err == nil {
+		t.Fatalf("got widths %v, want the panic as an error", widths)
+	}
+}
+
+func TestMeasureColumnsMatchesSerial(t *testing.T) {
+	sheet := wideSheet(200, 200)
+	width := testMeasurer(t)
+	got, err := measureColumns(sheet, 200, width)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for j := 0; j < 200; j++ {
+		var want float64
+		for _, row := range sheet {
+			want = max(want, width(row[j]))
+		}
+		if got[j] != want {
+			t.Fatalf("column %d: got width %v, want %v", j, got[j], want)
+		}
+	}
+}
+
+// BenchmarkMeasureColumns measures a 200-column × 1000-row sheet on one core and
+// on all of them
+func BenchmarkMeasureColumns(b *testing.B) {
+	sheet := wideSheet(1000, 200)
+	width := testMeasurer(b)
+	for _, bench := range []struct {
+		name  string
+		procs int
+	}{{"serial", 1}, {"parallel", runtime.NumCPU()}} {
+		b.Run(bench.name, func(b *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(bench.procs))
+			for i := 0; i < b.N; i++ {
+				if _, err := measureColumns(sheet, 200, width); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// numericSheet returns rows × cols cells of uniform numbers, some negative, as in
+// a numeric CSV export
+func numericSheet(rows, cols int) [][]string {
+	sheet := make([][]string, rows)
+	for i := range sheet {
+		sheet[i] = make([]string, cols)
+		for j := range sheet[i] {
+			value := float64((i*7919+j*104729)%1000000) / 100
+			if (i+j)%5 == 0 {
+				value = -value
+			}
+			sheet[i][j] = fmt.Sprintf("%.2f", value)
+		}
+	}
+	return sheet
+}
+
+// fullScanWidths measures every cell, as measureColumns did before the numeric
+// fast path
+func fullScanWidths(rows [][]string, numCols int, width func(string) float64) []float64 {
+	widths := make([]float64, numCols)
+	for _, row := range rows {
+		for j := 0; j < numCols && j < len(row); j++ {
+			widths[j] = max(widths[j], width(row[j]))
+		}
+	}
+	return widths
+}
+
+func TestNumericColumnWidthMatchesFullScan(t *testing.T) {
+	sheet := numericSheet(1000, 50)
+	width := testMeasurer(t)
+	want := fullScanWidths(sheet, 50, width)
+	for j := 0; j < 50; j++ {
+		got, ok := numericColumnWidth(sheet, j, width)
+		if !ok {
+			t.Fatalf("column %d: fast path not taken", j)
+		}
+		if got != want[j] {
+			t.Fatalf("column %d: got width %v, want %v", j, got, want[j])
+		}
+	}
+}
+
+// BenchmarkNumericColumns compares measuring every cell of a 50-column numeric
+// sheet with the numeric fast path of measureColumns, on one core
+func BenchmarkNumericColumns(b *testing.B) {
+	sheet := numericSheet(1000, 50)
+	width := testMeasurer(b)
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+	b.Run("full-scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			fullScanWidths(sheet, 50, width)
+		}
+	})
+	b.Run("fast-path", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := measureColumns(sheet, 50, width); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
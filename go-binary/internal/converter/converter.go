@@ -1,7 +1,13 @@
 package converter
 
 import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
 	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
 )
 
 // Converter is the interface for all file format converters
@@ -25,9 +31,25 @@ type Result struct {
 	Pages       int    `json:"pages"`
 	ProcessTime int64  `json:"process_time_ms"`
 	FileSize    int64  `json:"file_size_bytes"`
+	Stats       *Stats `json:"stats,omitempty"`
 	Error       string `json:"error,omitempty"`
 }
 
+// Stats describes what a native conversion read and drew, for logging and analytics
+type Stats struct {
+	Rows        int             `json:"rows"`                   // Table data rows drawn, or lines of text
+	Columns     int             `json:"columns"`                // Columns of the widest table
+	Sheets      int             `json:"sheets,omitempty"`       // Sheets or slides converted
+	SkippedRows int             `json:"skipped_rows,omitempty"` // Rows dropped as empty, duplicate, filtered out or unreadable
+	Truncated   bool            `json:"truncated,omitempty"`    // Data was left out by the cell range (Options.CellRange)
+	Orientation pdf.Orientation `json:"orientation,omitempty"`  // Orientation of the pages, or "mixed"
+	Font        string          `json:"font,omitempty"`         // Font file the text was drawn with, set by Convert from the Layout
+	FontSubstituted bool        `json:"font_substituted,omitempty"` // The custom font (Options.CustomFontPath) couldn't be loaded and Font replaced it
+	Delimiter   string          `json:"delimiter,omitempty"`    // Field delimiter detected in CSV/TSV input
+	Encoding    string          `json:"encoding,omitempty"`     // CSV/TSV text encoding: "utf-8", "utf-8-bom" or "non-utf-8" (sampled cells aren't valid UTF-8)
+	AutoOrientation bool        `json:"auto_orientation,omitempty"` // Options.AutoOrientation changed the orientation of some pages
+}
+
 // BatchResult represents the result of a batch conversion
 type BatchResult struct {
 	TotalFiles     int      `json:"total_files"`
@@ -46,12 +68,17 @@ const (
 	FormatXLSX  FormatType = "xlsx"
 	FormatXLSM  FormatType = "xlsm"
 	FormatXLS   FormatType = "xls"
+	FormatODS   FormatType = "ods"
 	FormatPPTX  FormatType = "pptx"
 	FormatPPT   FormatType = "ppt"
+	FormatPNG   FormatType = "png"
+	FormatJPEG  FormatType = "jpeg"
+	FormatText  FormatType = "text"
+	FormatNDJSON FormatType = "ndjson"
 	FormatAuto  FormatType = "auto"
 )
 
-// DetectFormat determines the format from file extension
+// DetectFormat determines the format from file extension (and content, for .txt)
 func DetectFormat(filename string) FormatType {
 	ext := getExtension(filename)
 	switch ext {
@@ -65,15 +92,220 @@ func DetectFormat(filename string) FormatType {
 		return FormatXLSM
 	case ".xls":
 		return FormatXLS
+	case ".ods":
+		return FormatODS
 	case ".pptx":
 		return FormatPPTX
 	case ".ppt":
 		return FormatPPT
+	case ".png":
+		return FormatPNG
+	case ".jpg", ".jpeg":
+		return FormatJPEG
+	case ".txt":
+		// Delimited data is common in .txt files too, so look at the content
+		return detectTextFormat(filename)
+	case ".log":
+		return FormatText
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON
+	case ".gz":
+		// Compressed CSV/TSV exports; the CSV converter decompresses them
+		switch getExtension(filename[:len(filename)-len(ext)]) {
+		case ".csv":
+			return FormatCSV
+		case ".tsv":
+			return FormatTSV
+		}
+		return FormatAuto
 	default:
 		return FormatAuto
 	}
 }
 
+// ResolveFormat returns format, or the format detected from inputPath when it is
+// FormatAuto or empty
+func ResolveFormat(format FormatType, inputPath string) FormatType {
+	if format == FormatAuto || format == "" {
+		return DetectFormat(inputPath)
+	}
+	return format
+}
+
+// equalColumnWidths divides the content width equally among columns.
+// Used when Options.AutoWidth is false for predictable, content-independent layouts.
+func equalColumnWidths(numCols int, contentWidth float64) []float64 {
+	widths := make([]float64, numCols)
+	for i := range widths {
+		widths[i] = contentWidth / float64(numCols)
+	}
+	return widths
+}
+
+// minColumnsPerWorker keeps narrow tables from being split across goroutines for
+// no gain
+const minColumnsPerWorker = 16
+
+// measureColumns returns the largest width(cell) in each of numCols columns of rows.
+// Wide tables are split into column ranges measured in parallel; each goroutine owns
+// its columns, so the results need no locking. width must be safe for concurrent use.
+// Columns of uniform numbers are measured by their widest candidates only (see
+// numericColumnWidth). A panic in width, e.g. gopdf failing on a glyph, is
+// recovered in the goroutine that measured and returned as the error.
+func measureColumns(rows [][]string, numCols int, width func(string) float64) (widths []float64, err error) {
+	widths = make([]float64, numCols)
+	tabular := tabularDigits(width)
+
+	workers := runtime.GOMAXPROCS(0)
+	if limit := numCols / minColumnsPerWorker; workers > limit {
+		workers = limit
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	chunk := (numCols + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	var panicOnce sync.Once
+	for lo := 0; lo < numCols; lo += chunk {
+		hi := lo + chunk
+		if hi > numCols {
+			hi = numCols
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					panicOnce.Do(func() { err = fmt.Errorf("measuring columns %d-%d: %v", lo+1, hi, r) })
+				}
+			}()
+			measured := make([]bool, hi-lo)
+			if tabular {
+				for j := lo; j < hi; j++ {
+					widths[j], measured[j-lo] = numericColumnWidth(rows, j, width)
+				}
+			}
+			for _, row := range rows {
+				for j := lo; j < hi && j < len(row); j++ {
+					if measured[j-lo] {
+						continue
+					}
+					if w := width(row[j]); w > widths[j] {
+						widths[j] = w
+					}
+				}
+			}
+		}(lo, hi)
+	}
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return widths, nil
+}
+
+// measureFailed is the error for a measureColumns failure while converting
+// inputPath, as render reports a panic in the converter itself
+func measureFailed(inputPath string, err error) error {
+	return errors.NewWithDetails(errors.ErrConversionFailed, "Conversion failed unexpectedly", inputPath, err.Error())
+}
+
+// tabularDigits reports whether all ten digits are equally wide, as in most fonts
+func tabularDigits(width func(string) float64) bool {
+	zero := width("0")
+	for d := '1'; d <= '9'; d++ {
+		if width(string(d)) != zero {
+			return false
+		}
+	}
+	return true
+}
+
+// numericColumnWidth is the fast path of measureColumns for column col. When every
+// cell is a plain number of one shape, an optional minus sign, digits and, if any
+// cell has a decimal point, the same number of decimals in all of them, a cell's
+// width depends only on its sign and length. With tabular digits it then measures
+// just the longest cell with and without a sign, and returns exactly the width
+// measuring every cell would. It returns false for any other column.
+func numericColumnWidth(rows [][]string, col int, width func(string) float64) (float64, bool) {
+	decimals := -1
+	var longest, longestNegative string
+	for _, row := range rows {
+		if col >= len(row) {
+			continue
+		}
+		cell := row[col]
+		if cell == "" {
+			continue // Narrower than any number
+		}
+		d, negative, ok := numberShape(cell)
+		if !ok || (decimals >= 0 && d != decimals) {
+			return 0, false
+		}
+		decimals = d
+		if negative {
+			if len(cell) > len(longestNegative) {
+				longestNegative = cell
+			}
+		} else if len(cell) > len(longest) {
+			longest = cell
+		}
+	}
+	if decimals < 0 {
+		return 0, false // No cells
+	}
+
+	var w float64
+	for _, cell := range []string{longest, longestNegative} {
+		if cell != "" {
+			if cw := width(cell); cw > w {
+				w = cw
+			}
+		}
+	}
+	return w, true
+}
+
+// numberShape returns the number of decimals of s and whether it has a minus sign,
+// if s is -?[0-9]+(\.[0-9]+)?
+func numberShape(s string) (decimals int, negative, ok bool) {
+	if strings.HasPrefix(s, "-") {
+		s, negative = s[1:], true
+	}
+	intPart, frac, hasPoint := strings.Cut(s, ".")
+	if !allDigits(intPart) || (hasPoint && !allDigits(frac)) {
+		return 0, false, false
+	}
+	return len(frac), negative, true
+}
+
+// allDigits reports whether s is a non-empty run of ASCII digits
+func allDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// widthSampleStart returns the first sampled row that counts toward column widths.
+// Group header labels span several columns and rotated header labels run along
+// their column, so neither widens a column.
+func widthSampleStart(opts pdf.Options) int {
+	if !opts.HeaderRow {
+		return 0
+	}
+	if opts.RotateHeaders {
+		return opts.HeaderRowCount()
+	}
+	return opts.HeaderRowCount() - 1
+}
+
 // getExtension returns the lowercase file extension
 func getExtension(filename string) string {
 	for i := len(filename) - 1; i >= 0; i-- {
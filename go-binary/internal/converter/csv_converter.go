@@ -2,20 +2,34 @@ package converter
 
 import (
 	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/csv"
 	"io"
+	"math"
 	"os"
+	"path/filepath"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
 	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
 )
 
+func init() {
+	Register(FormatCSV, func() Converter { return NewCSVConverter() })
+	Register(FormatTSV, func() Converter { return NewCSVConverter() })
+}
+
 // CSVConverter handles CSV to PDF conversion with streaming support
 type CSVConverter struct {
 	opts          pdf.Options
 	maxSampleRows int // Number of rows to sample for column width calculation
 	onProgress    func(int)
+	layout        pdf.Layout
+	warnings      []string
+	stats         *Stats
 }
 
 // NewCSVConverter creates a new CSV converter
@@ -31,15 +45,33 @@ func (c *CSVConverter) SetProgressCallback(callback func(int)) {
 	c.onProgress = callback
 }
 
+// Layout returns the page structure of the last PDF written by Convert
+func (c *CSVConverter) Layout() pdf.Layout {
+	return c.layout
+}
+
+// Warnings returns non-fatal notes from the last Convert (e.g. dropped rows)
+func (c *CSVConverter) Warnings() []string {
+	return c.warnings
+}
+
+// Stats returns the statistics of the last Convert
+func (c *CSVConverter) Stats() *Stats {
+	return c.stats
+}
+
 // SupportedExtensions returns extensions handled by this converter
 func (c *CSVConverter) SupportedExtensions() []string {
-	return []string{".csv", ".tsv", ".txt"}
+	return []string{".csv", ".tsv", ".txt", ".csv.gz", ".tsv.gz"}
 }
 
 // Validate checks if the input file is a valid CSV
 func (c *CSVConverter) Validate(inputPath string) error {
-	file, err := os.Open(inputPath)
+	file, err := openCSVFile(inputPath)
 	if err != nil {
+		if _, statErr := os.Stat(inputPath); statErr == nil {
+			return errors.NewWithDetails(errors.ErrCorruptFile, "Compressed CSV file is damaged", inputPath, err.Error())
+		}
 		return errors.NewWithFile(errors.ErrFileNotFound, "Cannot open file", inputPath)
 	}
 	defer file.Close()
@@ -56,6 +88,9 @@ func (c *CSVConverter) Validate(inputPath string) error {
 			break
 		}
 		if err != nil {
+			if readFailed(err) && hasSignature(inputPath, gzipSignature) {
+				return errors.NewWithDetails(errors.ErrCorruptFile, "Compressed CSV file is damaged", inputPath, err.Error())
+			}
 			return errors.NewWithDetails(errors.ErrInvalidFormat, "Invalid CSV format", inputPath, err.Error())
 		}
 	}
@@ -65,36 +100,35 @@ func (c *CSVConverter) Validate(inputPath string) error {
 
 // Convert performs the CSV to PDF conversion with memory-efficient streaming
 func (c *CSVConverter) Convert(inputPath, outputPath string, opts pdf.Options) error {
+	return c.ConvertCtx(context.Background(), inputPath, outputPath, opts)
+}
+
+// ConvertCtx is Convert, stopping between rows once ctx is done
+func (c *CSVConverter) ConvertCtx(ctx context.Context, inputPath, outputPath string, opts pdf.Options) error {
 	// Validate input
 	if err := c.Validate(inputPath); err != nil {
 		return err
 	}
 
-	// Open file for reading
-	file, err := os.Open(inputPath)
+	// Open file for reading, decompressing .gz exports
+	file, err := openCSVFile(inputPath)
 	if err != nil {
 		return errors.NewWithFile(errors.ErrFileNotFound, "Cannot open input file", inputPath)
 	}
-	defer file.Close()
+	defer func() { file.Close() }()
 
 	// Create buffered reader for efficient streaming
 	bufferedReader := bufio.NewReaderSize(file, 64*1024) // 64KB buffer
 	
 	// Skip UTF-8 BOM if present
-	bom := make([]byte, 3)
-	n, err := bufferedReader.Read(bom)
-	if err != nil && err != io.EOF {
+	hasBOM, err := skipBOM(bufferedReader)
+	if err != nil {
 		return errors.NewWithFile(errors.ErrConversionFailed, "Failed to read file", inputPath)
 	}
-	// Check for UTF-8 BOM (0xEF, 0xBB, 0xBF)
-	if n < 3 || bom[0] != 0xEF || bom[1] != 0xBB || bom[2] != 0xBF {
-		// No BOM, reset the reader by seeking to start
-		file.Seek(0, 0)
-		bufferedReader = bufio.NewReaderSize(file, 64*1024)
-	}
 	
 	// Detect delimiter
 	delimiter := c.detectDelimiter(inputPath)
+	opts = semicolonNumberLocale(opts, delimiter)
 	
 	reader := csv.NewReader(bufferedReader)
 	reader.FieldsPerRecord = -1
@@ -102,30 +136,49 @@ func (c *CSVConverter) Convert(inputPath, outputPath string, opts pdf.Options) e
 	reader.TrimLeadingSpace = true
 	reader.Comma = delimiter
 
-	// First pass: sample rows for column width calculation (memory efficient)
-	var sampleRecords [][]string
-	for i := 0; i < c.maxSampleRows; i++ {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
+	// First pass: sample rows for column width calculation (memory efficient).
+	// A transposed table needs every record, so the whole file is read instead.
+	var sampleRecords, transposed [][]string
+	if opts.Transpose {
+		opts = transposedOptions(opts)
+		transposed = transposeRows(readSample(&csvRowIterator{reader: reader}, math.MaxInt))
+		sampleRecords = transposed
+		if len(sampleRecords) > c.maxSampleRows {
+			sampleRecords = sampleRecords[:c.maxSampleRows]
 		}
-		if err != nil {
-			continue
+	} else {
+		for i := 0; i < c.maxSampleRows; i++ {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				continue
+			}
+			sampleRecords = append(sampleRecords, record)
 		}
-		sampleRecords = append(sampleRecords, record)
 	}
 
 	if len(sampleRecords) == 0 {
-		return errors.NewWithFile(errors.ErrInvalidFormat, "CSV file is empty", inputPath)
+		if opts.EmptyDataMessage == "" {
+			return errors.NewWithFile(errors.ErrInvalidFormat, "CSV file is empty", inputPath)
+		}
+		return c.convertEmpty(inputPath, outputPath, opts)
+	}
+
+	if opts.AutoDetectHeader {
+		opts.HeaderRow = detectHeaderRow(sampleRecords, opts)
 	}
 
 	// Calculate optimal column widths from sample
-	colWidths, shouldSwitchToLandscape := c.calculateColumnWidths(sampleRecords, opts)
+	colWidths, shouldSwitchToLandscape, err := c.calculateColumnWidths(sampleRecords, opts)
+	if err != nil {
+		return measureFailed(inputPath, err)
+	}
 	
-	// Apply auto-orientation if needed
+	// Apply auto-orientation if needed; colWidths already fit the landscape page
 	if shouldSwitchToLandscape {
 		opts.Orientation = pdf.Landscape
-		colWidths, _ = c.calculateColumnWidths(sampleRecords, opts)
 	}
 
 	// Prepare headers
@@ -134,17 +187,15 @@ func (c *CSVConverter) Convert(inputPath, outputPath string, opts pdf.Options) e
 		headers = sampleRecords[0]
 	}
 
-	// Reset file for second pass
-	file.Seek(0, 0)
+	// Reopen the file for the second pass; a compressed stream can't seek
+	file.Close()
+	if file, err = openCSVFile(inputPath); err != nil {
+		return errors.NewWithFile(errors.ErrFileNotFound, "Cannot open input file", inputPath)
+	}
 	bufferedReader = bufio.NewReaderSize(file, 64*1024)
 	
 	// Skip BOM again if present
-	bom = make([]byte, 3)
-	n, _ = bufferedReader.Read(bom)
-	if n < 3 || bom[0] != 0xEF || bom[1] != 0xBB || bom[2] != 0xBF {
-		file.Seek(0, 0)
-		bufferedReader = bufio.NewReaderSize(file, 64*1024)
-	}
+	skipBOM(bufferedReader)
 	
 	reader = csv.NewReader(bufferedReader)
 	reader.FieldsPerRecord = -1
@@ -157,27 +208,82 @@ func (c *CSVConverter) Convert(inputPath, outputPath string, opts pdf.Options) e
 	if err != nil {
 		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create PDF builder")
 	}
+	defer builder.Close()
 	
 	if c.onProgress != nil {
 		builder.SetProgressCallback(c.onProgress)
 	}
+	builder.SetColumnTypes(sampleColumnTypes(sampleRecords, opts))
 
 	// Add first page
+	builder.BeginSection(filepath.Base(inputPath))
 	builder.AddPage()
+	if opts.TableCaption != "" {
+		builder.AddCaption(opts.TableCaption)
+	}
 
 	// Create CSV row iterator adapter
-	csvIterator := &csvRowIterator{reader: reader}
+	var records pdf.RowIterator = &csvRowIterator{reader: reader}
+	if opts.Transpose {
+		records = &sliceRowIterator{rows: transposed}
+	}
+	csvIterator, err := newRowFilter(records, opts, sampleRecords[0])
+	if err != nil {
+		return errors.Wrap(err, errors.ErrInvalidOption, "Invalid row filter")
+	}
+	csvIterator.columns = len(colWidths)
+	csvIterator.ctx = ctx
 
 	// Draw table with streaming
 	if err := builder.DrawTableStreaming(headers, csvIterator, colWidths, opts.HeaderRow); err != nil {
 		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to draw table")
 	}
+	if err := csvIterator.Err(); err != nil {
+		return err
+	}
+	if rows, ok := records.(*csvRowIterator); ok && readFailed(rows.err) {
+		// E.g. a truncated .csv.gz; the table would silently end early
+		return errors.NewWithDetails(errors.ErrCorruptFile, "CSV file is damaged", inputPath, rows.err.Error())
+	}
+	if csvIterator.DataRows() == 0 {
+		builder.AddEmptyMessage(opts.EmptyDataMessage)
+	}
+	c.warnings = csvIterator.Warnings("")
+	stats := &Stats{Delimiter: string(delimiter), Encoding: csvEncoding(hasBOM, sampleRecords), AutoOrientation: shouldSwitchToLandscape}
+	stats.addTable(csvIterator, len(colWidths))
 
 	// Save the PDF
 	if err := builder.Save(outputPath); err != nil {
 		return errors.Wrap(err, errors.ErrWriteFailed, "Failed to save PDF")
 	}
+	c.layout = builder.Layout()
+	stats.Orientation = c.layout.Orientation
+	c.stats = stats
+
+	return nil
+}
+
+// convertEmpty writes a CSV file without records as a page with Options.EmptyDataMessage
+func (c *CSVConverter) convertEmpty(inputPath, outputPath string, opts pdf.Options) error {
+	builder, err := pdf.NewBuilder(opts)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create PDF builder")
+	}
+	defer builder.Close()
 
+	builder.BeginSection(filepath.Base(inputPath))
+	builder.AddPage()
+	if opts.TableCaption != "" {
+		builder.AddCaption(opts.TableCaption)
+	}
+	builder.AddEmptyMessage(opts.EmptyDataMessage)
+
+	if err := builder.Save(outputPath); err != nil {
+		return errors.Wrap(err, errors.ErrWriteFailed, "Failed to save PDF")
+	}
+	c.layout = builder.Layout()
+	c.stats = &Stats{Orientation: c.layout.Orientation}
+	c.warnings = []string{"CSV file has no records"}
 	return nil
 }
 
@@ -202,7 +308,7 @@ func (c *csvRowIterator) Columns() ([]string, error) {
 
 // detectDelimiter attempts to detect the CSV delimiter
 func (c *CSVConverter) detectDelimiter(filePath string) rune {
-	file, err := os.Open(filePath)
+	file, err := openCSVFile(filePath)
 	if err != nil {
 		return ','
 	}
@@ -241,11 +347,98 @@ func (c *CSVConverter) detectDelimiter(filePath string) rune {
 	return ','
 }
 
-// calculateColumnWidths calculates optimal column widths based on content
-// Returns widths and a boolean indicating if orientation should switch to Landscape
-func (c *CSVConverter) calculateColumnWidths(records [][]string, opts pdf.Options) ([]float64, bool) {
+// gzipFile decompresses a gzip-compressed file as it is read
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (f *gzipFile) Close() error {
+	f.Reader.Close()
+	return f.file.Close()
+}
+
+// openCSVFile opens a CSV file for reading. A gzip-compressed file, such as an
+// "export.csv.gz", is recognized by its signature and decompressed on the fly.
+func openCSVFile(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !hasSignature(path, gzipSignature) {
+		return file, nil
+	}
+	zr, err := gzip.NewReader(bufio.NewReaderSize(file, 64*1024))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &gzipFile{Reader: zr, file: file}, nil
+}
+
+// readFailed reports whether reading a CSV file stopped on err for a reason other
+// than its end or malformed CSV, such as a truncated compressed stream
+func readFailed(err error) bool {
+	if err == nil || err == io.EOF {
+		return false
+	}
+	_, isParseError := err.(*csv.ParseError)
+	return !isParseError
+}
+
+// skipBOM skips a UTF-8 byte order mark at the start of r and reports whether there was one
+func skipBOM(r *bufio.Reader) (bool, error) {
+	bom, err := r.Peek(3)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	if !bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		return false, nil
+	}
+	r.Discard(3)
+	return true, nil
+}
+
+// csvEncoding names the text encoding of a CSV file for Stats.Encoding: "utf-8-bom"
+// when it starts with a byte order mark, "non-utf-8" when sampled cells aren't
+// valid UTF-8 (e.g. a Latin-1 or Windows-1252 export, whose accented letters won't
+// render correctly), and "utf-8" otherwise
+func csvEncoding(hasBOM bool, sample [][]string) string {
+	if hasBOM {
+		return "utf-8-bom"
+	}
+	for _, row := range sample {
+		for _, cell := range row {
+			if !utf8.ValidString(cell) {
+				return "non-utf-8"
+			}
+		}
+	}
+	return "utf-8"
+}
+
+// semicolonNumberLocale reads the numbers of a ";"-delimited file with comma
+// decimals, as written by spreadsheets in locales whose decimal mark is the comma
+// (which is why they don't delimit with it), unless NumberLocale is set or Locale
+// already uses comma decimals
+func semicolonNumberLocale(opts pdf.Options, delimiter rune) pdf.Options {
+	if delimiter != ';' || opts.NumberLocale != "" {
+		return opts
+	}
+	if decimal, _ := pdf.NumberSeparators(opts.Locale); decimal != "," {
+		opts.NumberLocale = "de"
+	}
+	return opts
+}
+
+// calculateColumnWidths calculates optimal column widths based on content. It fails
+// only when measuring the cells panicked (see measureColumns).
+// Returns widths and a boolean indicating if orientation should switch to Landscape,
+// in which case the widths are already fitted to the landscape page (the cells are
+// measured only once)
+func (c *CSVConverter) calculateColumnWidths(records [][]string, opts pdf.Options) ([]float64, bool, error) {
 	if len(records) == 0 {
-		return nil, false
+		return nil, false, nil
 	}
 
 	// Find the maximum number of columns
@@ -257,11 +450,15 @@ func (c *CSVConverter) calculateColumnWidths(records [][]string, opts pdf.Option
 	}
 
 	if maxCols == 0 {
-		return nil, false
+		return nil, false, nil
+	}
+
+	// Fixed equal-width columns
+	if !opts.AutoWidth {
+		return equalColumnWidths(maxCols, opts.ContentWidth()), false, nil
 	}
 
 	// Calculate max width for each column using accurate font measurement
-	colMaxWidths := make([]float64, maxCols)
 	sampleSize := c.maxSampleRows
 	if len(records) < sampleSize {
 		sampleSize = len(records)
@@ -272,35 +469,32 @@ func (c *CSVConverter) calculateColumnWidths(records [][]string, opts pdf.Option
 	if err != nil {
 		// Fallback to estimation if font loading fails
 		// We ignore auto-orientation in fallback for simplicity
-		return c.calculateColumnWidthsFallback(records, opts), false
+		return c.calculateColumnWidthsFallback(records, opts), false, nil
 	}
 
-	for i := 0; i < sampleSize; i++ {
-		row := records[i]
-		for j, cell := range row {
-			// Accurate measurement + padding (left+right)
-			width := builder.MeasureTextWidth(cell) + 6.0 // 3.0 padding per side
-			if width > colMaxWidths[j] {
-				colMaxWidths[j] = width
-			}
-		}
+	var sample [][]string
+	if start := widthSampleStart(opts); start < sampleSize {
+		sample = records[start:sampleSize]
+	}
+	measurer := builder.TextMeasurer()
+	colMaxWidths, err := measureColumns(sample, maxCols, func(cell string) float64 {
+		// Accurate measurement + padding (left+right)
+		return measurer.Width(cell) + 6.0 // 3.0 padding per side
+	})
+	if err != nil {
+		return nil, false, err
 	}
 
 	// Use custom min/max from options, or defaults
-	minColWidth := opts.MinColumnWidth
-	maxColWidth := opts.MaxColumnWidth
-	if minColWidth <= 0 {
-		minColWidth = 40.0
-	}
-	if maxColWidth <= 0 {
-		maxColWidth = 250.0 // Increased max width for better readability
-	}
+	minColWidth, maxColWidth := opts.ColumnWidthLimits()
 
 	for i := range colMaxWidths {
 		if colMaxWidths[i] < minColWidth {
 			colMaxWidths[i] = minColWidth
 		}
-		// Soft cap: allow going over if page permits, but clamp for initial calculation
+		if colMaxWidths[i] > maxColWidth {
+			colMaxWidths[i] = maxColWidth
+		}
 	}
 	
 	// Check if we should switch to Landscape
@@ -321,11 +515,14 @@ func (c *CSVConverter) calculateColumnWidths(records [][]string, opts pdf.Option
 		}
 	}
 
-	return c.optimizeWidthsForPage(colMaxWidths, opts.ContentWidth()), shouldSwitch
+	if shouldSwitch {
+		opts.Orientation = pdf.Landscape
+	}
+	return c.optimizeWidthsForPage(colMaxWidths, opts.ContentWidth(), opts.ScaledColumnFloor()), shouldSwitch, nil
 }
 
 // optimizeWidthsForPage fits column widths to the page using weighted compression
-func (c *CSVConverter) optimizeWidthsForPage(widths []float64, availableWidth float64) []float64 {
+func (c *CSVConverter) optimizeWidthsForPage(widths []float64, availableWidth, floor float64) []float64 {
 	totalWidth := 0.0
 	for _, w := range widths {
 		totalWidth += w
@@ -382,8 +579,8 @@ func (c *CSVConverter) optimizeWidthsForPage(widths []float64, availableWidth fl
 	
 	for i := range newWidths {
 		newWidths[i] *= scale
-		if newWidths[i] < 35 {
-			newWidths[i] = 35
+		if newWidths[i] < floor {
+			newWidths[i] = floor
 		}
 	}
 	
@@ -423,7 +620,7 @@ func (c *CSVConverter) calculateColumnWidthsFallback(records [][]string, opts pd
 		sampleSize = len(records)
 	}
 	
-	for i := 0; i < sampleSize; i++ {
+	for i := widthSampleStart(opts); i < sampleSize; i++ {
 		row := records[i]
 		for j, cell := range row {
 			width := float64(len(cell)) * 6 // Estimate
@@ -432,9 +629,17 @@ func (c *CSVConverter) calculateColumnWidthsFallback(records [][]string, opts pd
 			}
 		}
 	}
+
+	// Columns that are blank in the whole sample still get the minimum width
+	minColWidth, _ := opts.ColumnWidthLimits()
+	for i := range colMaxWidths {
+		if colMaxWidths[i] < minColWidth {
+			colMaxWidths[i] = minColWidth
+		}
+	}
 	
 	// Apply standard scaling
-	return c.optimizeWidthsForPage(colMaxWidths, opts.ContentWidth())
+	return c.optimizeWidthsForPage(colMaxWidths, opts.ContentWidth(), opts.ScaledColumnFloor())
 }
 
 // StreamingCSVConverter provides memory-efficient conversion for large files
@@ -453,15 +658,11 @@ func NewStreamingCSVConverter(chunkSize int) *StreamingCSVConverter {
 
 // ConvertStreaming performs memory-efficient streaming conversion
 func (c *StreamingCSVConverter) ConvertStreaming(inputPath, outputPath string, opts pdf.Options) error {
-	file, err := os.Open(inputPath)
+	file, err := openCSVFile(inputPath)
 	if err != nil {
 		return errors.NewWithFile(errors.ErrFileNotFound, "Cannot open input file", inputPath)
 	}
-	defer file.Close()
-
-	// Get file size for progress tracking
-	stat, _ := file.Stat()
-	_ = stat.Size() // For future progress reporting
+	defer func() { file.Close() }()
 
 	// Create buffered reader
 	reader := csv.NewReader(bufio.NewReaderSize(file, 64*1024))
@@ -486,16 +687,20 @@ func (c *StreamingCSVConverter) ConvertStreaming(inputPath, outputPath string, o
 		return errors.NewWithFile(errors.ErrInvalidFormat, "CSV file is empty", inputPath)
 	}
 
-	colWidths, shouldSwitchToLandscape := c.calculateColumnWidths(sampleRows, opts)
+	colWidths, shouldSwitchToLandscape, err := c.calculateColumnWidths(sampleRows, opts)
+	if err != nil {
+		return measureFailed(inputPath, err)
+	}
 	
 	if shouldSwitchToLandscape {
 		opts.Orientation = pdf.Landscape
-		// Re-calc for landscape
-		colWidths, _ = c.calculateColumnWidths(sampleRows, opts)
 	}
 
-	// Reset file for second pass
-	file.Seek(0, 0)
+	// Reopen the file for the second pass; a compressed stream can't seek
+	file.Close()
+	if file, err = openCSVFile(inputPath); err != nil {
+		return errors.NewWithFile(errors.ErrFileNotFound, "Cannot open input file", inputPath)
+	}
 	reader = csv.NewReader(bufio.NewReaderSize(file, 64*1024))
 	reader.FieldsPerRecord = -1
 	reader.LazyQuotes = true
@@ -506,6 +711,7 @@ func (c *StreamingCSVConverter) ConvertStreaming(inputPath, outputPath string, o
 	if err != nil {
 		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create PDF builder")
 	}
+	defer builder.Close()
 	
 	if c.onProgress != nil {
 		builder.SetProgressCallback(c.onProgress)
@@ -0,0 +1,182 @@
+package converter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+)
+
+// streamingRows is the number of data rows that fill about 5000 A4 pages with
+// the default options (21 rows a page)
+const streamingRows = 105000
+
+// writeLargeCSV writes a CSV of rows data rows to dir and returns its path
+func writeLargeCSV(tb testing.TB, dir string, rows int) string {
+	tb.Helper()
+	path := filepath.Join(dir, "large.csv")
+	f, err := os.Create(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, "id,name,amount,date")
+	for i := 1; i <= rows; i++ {
+		fmt.Fprintf(w, "%d,Customer %d,%d.%02d,2024-%02d-%02d\n", i, i%997, i*7%100000, i%100, i%12+1, i%28+1)
+	}
+	if err := w.Flush(); err != nil {
+		tb.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		tb.Fatal(err)
+	}
+	return path
+}
+
+// peakHeap samples the heap every few milliseconds until stop is called, which
+// returns the largest HeapAlloc seen in MB
+func peakHeap() (stop func() float64) {
+	var (
+		peak uint64
+		wg   sync.WaitGroup
+	)
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var stats runtime.MemStats
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			runtime.ReadMemStats(&stats)
+			peak = max(peak, stats.HeapAlloc)
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() float64 {
+		close(done)
+		wg.Wait()
+		return float64(peak) / (1 << 20)
+	}
+}
+
+// BenchmarkCSVStreaming converts a 5000-page CSV through the streaming table
+// path, as one file and split into 500-page parts. Rows are never all in memory,
+// but the PDF writer keeps a file's pages until it is written: peak-heap-MB
+// follows the part size (Options.MaxPagesPerFile), not the input size.
+func BenchmarkCSVStreaming(b *testing.B) {
+	input := writeLargeCSV(b, b.TempDir(), streamingRows)
+	for _, tc := range []struct {
+		name     string
+		maxPages int
+	}{
+		{"single-file", 0},
+		{"split-500", 500},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			opts := pdf.DefaultOptions()
+			opts.MaxPagesPerFile = tc.maxPages
+			output := filepath.Join(b.TempDir(), "large.pdf")
+			b.ReportAllocs()
+			runtime.GC()
+			stop := peakHeap()
+			for i := 0; i < b.N; i++ {
+				if err := NewCSVConverter().Convert(input, output, opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(stop(), "peak-heap-MB")
+		})
+	}
+}
+
+// europeanNumbersCSV is the ";"-delimited fixture with comma decimals and dot
+// thousands separators, shared with the Laravel feature tests
+const europeanNumbersCSV = "../../../tests/fixtures/european_numbers.csv"
+
+func TestSemicolonNumberLocale(t *testing.T) {
+	withLocale := func(locale, numberLocale string) pdf.Options {
+		opts := pdf.DefaultOptions()
+		opts.Locale, opts.NumberLocale = locale, numberLocale
+		return opts
+	}
+	tests := []struct {
+		name      string
+		opts      pdf.Options
+		delimiter rune
+		want      string
+	}{
+		{"semicolon", withLocale("", ""), ';', "de"},
+		{"semicolon under en_US", withLocale("en_US", ""), ';', "de"},
+		{"semicolon under a comma-decimal locale", withLocale("fr_FR", ""), ';', ""},
+		{"semicolon with NumberLocale", withLocale("", "en_US"), ';', "en_US"},
+		{"comma", withLocale("", ""), ',', ""},
+	}
+	for _, tt := range tests {
+		if got := semicolonNumberLocale(tt.opts, tt.delimiter).NumberLocale; got != tt.want {
+			t.Errorf("%s: NumberLocale %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestEuropeanNumbersFixture converts the European fixture: its numbers are read
+// with comma decimals, so a filter on the dot-grouped quantities matches them as
+// thousands, and the sampled quantity and price columns are typed as numbers
+func TestEuropeanNumbersFixture(t *testing.T) {
+	c := NewCSVConverter()
+	if delimiter := c.detectDelimiter(europeanNumbersCSV); delimiter != ';' {
+		t.Fatalf("detected delimiter %q, want ';'", delimiter)
+	}
+
+	opts := pdf.DefaultOptions()
+	opts.Filter = "Menge > 1000" // 1.250 and 2.400
+	if err := c.Convert(europeanNumbersCSV, filepath.Join(t.TempDir(), "european.pdf"), opts); err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	if stats := c.Stats(); stats.Rows != 2 || stats.SkippedRows != 6 || stats.Delimiter != ";" {
+		t.Errorf("stats %+v, want 2 rows kept and 6 filtered out of a ';'-delimited file", stats)
+	}
+
+	// Under an explicit dot-decimal NumberLocale 1.250 is 1.25 and nothing matches
+	opts.NumberLocale = "en_US"
+	if err := c.Convert(europeanNumbersCSV, filepath.Join(t.TempDir(), "european.pdf"), opts); err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	if stats := c.Stats(); stats.Rows != 0 {
+		t.Errorf("%d rows kept under en_US numbers, want none", stats.Rows)
+	}
+
+	file, err := os.Open(europeanNumbersCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	reader.Comma = ';'
+	sample, err := reader.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts = pdf.DefaultOptions()
+	opts.AutoDetectHeader = true
+	opts = semicolonNumberLocale(opts, ';')
+	if !detectHeaderRow(sample, opts) {
+		t.Error("the header row wasn't detected against the comma-decimal numbers below it")
+	}
+	want := []pdf.ColumnType{pdf.ColumnText, pdf.ColumnNumber, pdf.ColumnNumber, pdf.ColumnNumber}
+	if got := sampleColumnTypes(sample, opts); !reflect.DeepEqual(got, want) {
+		t.Errorf("column types %v, want text then three number columns", got)
+	}
+}
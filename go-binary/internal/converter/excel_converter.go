@@ -1,33 +1,175 @@
 package converter
 
 import (
+	"context"
+	"encoding/csv"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
 	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
 	"github.com/xuri/excelize/v2"
 )
 
+func init() {
+	Register(FormatXLSX, func() Converter { return NewExcelConverter() })
+	Register(FormatXLSM, func() Converter { return NewExcelConverter() })
+}
+
 // ExcelConverter handles Excel (XLSX/XLS) to PDF conversion
 type ExcelConverter struct {
 	opts    pdf.Options
 	onProgress func(int)
+	layout  pdf.Layout
+	warnings []string
+	stats   *Stats
 }
 
 // excelRowIterator adapts excelize.Rows to pdf.RowIterator interface
 type excelRowIterator struct {
-	rows *excelize.Rows
+	rows   *excelize.Rows
+	file   *excelize.File
+	sheet  string
+	locale string
+	rowNum int
+	fills  cellFills // Conditional formatting (Options.RenderConditionalFormatting)
+	comments cellComments // Options.RenderComments
+	text   *textColumns // Set while sampling, to find numbers stored as text
 }
 
 func (e *excelRowIterator) Next() bool {
-	return e.rows.Next()
+	if e.rows.Next() {
+		e.rowNum++
+		return true
+	}
+	return false
 }
 
 func (e *excelRowIterator) Columns() ([]string, error) {
-	return e.rows.Columns()
+	row, err := e.rows.Columns()
+	if err != nil {
+		return row, err
+	}
+	row = normalizeCellValues(e.file, e.sheet, e.rowNum, row, e.locale)
+	if e.text != nil {
+		e.text.note(e.file, e.sheet, e.rowNum, row)
+	}
+	return row, nil
+}
+
+func (e *excelRowIterator) CellFills() map[int]pdf.Color {
+	return e.fills[e.rowNum]
+}
+
+func (e *excelRowIterator) CellComments() map[int]string {
+	return e.comments[e.rowNum]
+}
+
+// normalizeCellValues renders boolean cells with the locale's TRUE/FALSE labels
+// and keeps error cells (#DIV/0!, #N/A, ...) verbatim. Only values that could be
+// affected are classified, since GetCellType is a lookup per cell.
+func normalizeCellValues(f *excelize.File, sheet string, rowNum int, row []string, locale string) []string {
+	trueLabel, falseLabel := pdf.BooleanLabels(locale)
+	for i, value := range row {
+		upper := strings.ToUpper(value)
+		isBoolCandidate := (upper == "TRUE" && trueLabel != "TRUE") || (upper == "FALSE" && falseLabel != "FALSE")
+		if !isBoolCandidate && !strings.HasPrefix(value, "#") {
+			continue
+		}
+		cellName, err := excelize.CoordinatesToCellName(i+1, rowNum)
+		if err != nil {
+			continue
+		}
+		cellType, err := f.GetCellType(sheet, cellName)
+		if err != nil {
+			continue
+		}
+		switch cellType {
+		case excelize.CellTypeBool:
+			if upper == "TRUE" {
+				row[i] = trueLabel
+			} else if upper == "FALSE" {
+				row[i] = falseLabel
+			}
+		case excelize.CellTypeError:
+			// Error strings are passed through as stored in the workbook
+			if raw, err := f.GetCellValue(sheet, cellName, excelize.Options{RawCellValue: true}); err == nil && raw != "" {
+				row[i] = raw
+			}
+		}
+	}
+	return row
+}
+
+// textColumns counts, per sheet column (0-based), the numeric-looking values of
+// the sampled rows and how many of them the workbook keeps as text: typed with a
+// leading apostrophe, into cells formatted as Text, or written as strings by the
+// exporting program. Like normalizeCellValues, it looks up only those values.
+type textColumns struct {
+	numbers map[int]int
+	texts   map[int]int
+	styles  map[int]bool // Style ID -> number format is Text ("@")
+}
+
+func newTextColumns() *textColumns {
+	return &textColumns{numbers: make(map[int]int), texts: make(map[int]int), styles: make(map[int]bool)}
+}
+
+// note counts the numeric-looking values of sheet row rowNum
+func (t *textColumns) note(f *excelize.File, sheet string, rowNum int, row []string) {
+	for i, value := range row {
+		if !pdf.IsNumberText(value) {
+			continue
+		}
+		cellName, err := excelize.CoordinatesToCellName(i+1, rowNum)
+		if err != nil {
+			continue
+		}
+		t.numbers[i]++
+		cellType, err := f.GetCellType(sheet, cellName)
+		if err != nil {
+			continue
+		}
+		switch cellType {
+		case excelize.CellTypeSharedString, excelize.CellTypeInlineString, excelize.CellTypeFormula:
+			// Formula cells of this type hold a string result
+			t.texts[i]++
+			continue
+		}
+		if styleID, err := f.GetCellStyle(sheet, cellName); err == nil && styleID != 0 && t.textFormat(f, styleID) {
+			t.texts[i]++
+		}
+	}
+}
+
+// textFormat reports whether a cell style's number format is Text
+func (t *textColumns) textFormat(f *excelize.File, styleID int) bool {
+	isText, ok := t.styles[styleID]
+	if !ok {
+		style, err := f.GetStyle(styleID)
+		isText = err == nil && (style.NumFmt == 49 || (style.CustomNumFmt != nil && *style.CustomNumFmt == "@"))
+		t.styles[styleID] = isText
+	}
+	return isText
+}
+
+// apply turns the columns whose numbers are mostly kept as text into text columns.
+// types are indexed from the first column of rng.
+func (t *textColumns) apply(types []pdf.ColumnType, rng *cellRange) []pdf.ColumnType {
+	offset := 0
+	if rng != nil {
+		offset = rng.col1 - 1
+	}
+	for col, n := range t.numbers {
+		if i := col - offset; i >= 0 && i < len(types) && t.texts[col]*2 >= n {
+			types[i] = pdf.ColumnText
+		}
+	}
+	return types
 }
 
 // NewExcelConverter creates a new Excel converter
@@ -42,6 +184,21 @@ func (c *ExcelConverter) SetProgressCallback(callback func(int)) {
 	c.onProgress = callback
 }
 
+// Layout returns the page structure of the last PDF written by Convert
+func (c *ExcelConverter) Layout() pdf.Layout {
+	return c.layout
+}
+
+// Warnings returns non-fatal notes from the last Convert (e.g. dropped rows)
+func (c *ExcelConverter) Warnings() []string {
+	return c.warnings
+}
+
+// Stats returns the statistics of the last Convert
+func (c *ExcelConverter) Stats() *Stats {
+	return c.stats
+}
+
 // SupportedExtensions returns extensions handled by this converter
 func (c *ExcelConverter) SupportedExtensions() []string {
 	return []string{".xlsx", ".xls", ".xlsm"}
@@ -57,6 +214,10 @@ func (c *ExcelConverter) Validate(inputPath string) error {
 	// Try to open as Excel file
 	f, err := excelize.OpenFile(inputPath)
 	if err != nil {
+		// A ZIP that fails to open as a workbook is a damaged XLSX, not a different file type
+		if hasSignature(inputPath, zipSignature) {
+			return errors.NewWithDetails(errors.ErrCorruptFile, "Excel file is damaged", inputPath, err.Error())
+		}
 		return errors.NewWithDetails(errors.ErrInvalidFormat, "Invalid Excel format", inputPath, err.Error())
 	}
 	defer f.Close()
@@ -72,6 +233,11 @@ func (c *ExcelConverter) Validate(inputPath string) error {
 
 // Convert performs the Excel to PDF conversion using streaming for large files
 func (c *ExcelConverter) Convert(inputPath, outputPath string, opts pdf.Options) error {
+	return c.ConvertCtx(context.Background(), inputPath, outputPath, opts)
+}
+
+// ConvertCtx is Convert, stopping between sheets and rows once ctx is done
+func (c *ExcelConverter) ConvertCtx(ctx context.Context, inputPath, outputPath string, opts pdf.Options) error {
 	// Validate input
 	if err := c.Validate(inputPath); err != nil {
 		return err
@@ -87,11 +253,24 @@ func (c *ExcelConverter) Convert(inputPath, outputPath string, opts pdf.Options)
 	}
 	defer f.Close()
 
+	rng, err := cellRangeOption(opts)
+	if err != nil {
+		return err
+	}
+
+	// Transposing needs whole sheets
+	if opts.Transpose {
+		return c.convertSheetRows(ctx, f, readSheets(f, f.GetSheetList(), opts.Locale), outputPath, opts)
+	}
+
 	// Create PDF builder
 	builder, err := pdf.NewBuilder(opts)
 	if err != nil {
 		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create PDF builder")
 	}
+	defer builder.Close()
+	c.warnings = macroWarnings(f)
+	stats := &Stats{}
 	
 	if c.onProgress != nil {
 		builder.SetProgressCallback(c.onProgress)
@@ -101,41 +280,44 @@ func (c *ExcelConverter) Convert(inputPath, outputPath string, opts pdf.Options)
 	sheets := f.GetSheetList()
 
 	for _, sheetName := range sheets {
-		// Add new page for each sheet
-		builder.AddPage()
-
-		// Add sheet name as title
-		builder.NewLine(10)
+		if err := contextError(ctx, inputPath); err != nil {
+			return err
+		}
+		builder.BeginSection(sheetName)
 
-		// Use streaming reader for large files to avoid memory issues
+		// Use streaming reader for large files to avoid memory issues.
+		// First pass: sample rows for orientation and column widths (memory efficient)
+		var sampleRows [][]string
+		text := newTextColumns()
 		streamRows, err := f.Rows(sheetName)
+		if err == nil {
+			sampleRows = readSample(rng.iterator(&excelRowIterator{rows: streamRows, file: f, sheet: sheetName, locale: opts.Locale, text: text}), 100)
+			streamRows.Close()
+		}
+
+		// Add a new page for each sheet, in the sheet's own orientation
+		view := readSheetView(f, sheetName, opts)
+		sheetOpts := c.sheetOptions(sampleRows, view.options(opts, rng))
+		if sheetOpts.Orientation != opts.Orientation {
+			stats.AutoOrientation = true
+		}
+		startSheet(builder, f, sheetName, sheetOpts)
+		stats.Sheets++
 		if err != nil {
 			continue // Skip sheet on error
 		}
-		
-		// First pass: sample rows for column width calculation (memory efficient)
-		var sampleRows [][]string
-		rowCount := 0
-		for streamRows.Next() && rowCount < 100 {
-			row, err := streamRows.Columns()
-			if err != nil {
-				continue
-			}
-			sampleRows = append(sampleRows, row)
-			rowCount++
-		}
-		streamRows.Close()
 
 		if len(sampleRows) == 0 {
-			continue // Skip empty sheets
+			builder.AddEmptyMessage(opts.EmptyDataMessage)
+			continue
 		}
 
 		// Calculate column widths from sample
-		colWidths := c.calculateColumnWidths(sampleRows, opts)
+		colWidths := c.calculateColumnWidths(sampleRows, sheetOpts)
 
 		// Prepare headers
 		var headers []string
-		if opts.HeaderRow && len(sampleRows) > 0 {
+		if sheetOpts.HeaderRow && len(sampleRows) > 0 {
 			headers = sampleRows[0]
 		}
 
@@ -146,22 +328,120 @@ func (c *ExcelConverter) Convert(inputPath, outputPath string, opts pdf.Options)
 		}
 
 		// Draw table with streaming using adapter
-		rowIterator := &excelRowIterator{rows: streamRows}
-		if err := builder.DrawTableStreaming(headers, rowIterator, colWidths, opts.HeaderRow); err != nil {
+		rowIterator, err := newRowFilter(rng.iterator(&excelRowIterator{rows: streamRows, file: f, sheet: sheetName, locale: opts.Locale, fills: c.conditionalFills(f, sheetName, opts), comments: c.cellComments(f, sheetName, opts)}), sheetOpts, sampleRows[0])
+		if err != nil {
+			streamRows.Close()
+			return errors.NewWithDetails(errors.ErrInvalidOption, "Invalid row filter", sheetName, err.Error())
+		}
+		rowIterator.columns = len(colWidths)
+		rowIterator.ctx = ctx
+		builder.SetColumnTypes(text.apply(sampleColumnTypes(sampleRows, sheetOpts), rng))
+		builder.SetFilterColumns(view.filterColumns(sheetOpts, rng))
+		if err := builder.DrawTableStreaming(headers, rowIterator, colWidths, sheetOpts.HeaderRow); err != nil {
 			streamRows.Close()
 			return errors.Wrap(err, errors.ErrConversionFailed, "Failed to draw table")
 		}
 		streamRows.Close()
+		if err := rowIterator.Err(); err != nil {
+			return err
+		}
+		if rowIterator.DataRows() == 0 {
+			builder.AddEmptyMessage(opts.EmptyDataMessage)
+		}
+		c.warnings = append(c.warnings, rowIterator.Warnings(sheetName)...)
+		stats.addTable(rowIterator, len(colWidths))
 	}
 
 	// Save the PDF
 	if err := builder.Save(outputPath); err != nil {
 		return errors.Wrap(err, errors.ErrWriteFailed, "Failed to save PDF")
 	}
+	c.layout = builder.Layout()
+	stats.Orientation = c.layout.Orientation
+	c.stats = stats
 
 	return nil
 }
 
+// macroWarnings notes that a workbook's macros, a VBA project as in .xlsm files
+// or Excel 4.0 macro sheets, are neither run nor kept: only the values stored in
+// the cells are converted. f may be nil, for sheets that don't come from excelize.
+func macroWarnings(f *excelize.File) []string {
+	if f == nil {
+		return nil
+	}
+	macros := false
+	f.Pkg.Range(func(key, _ interface{}) bool {
+		name, _ := key.(string)
+		macros = name == "xl/vbaProject.bin" || strings.HasPrefix(name, "xl/macrosheets/")
+		return !macros
+	})
+	if !macros {
+		return nil
+	}
+	return []string{"Workbook contains macros; they were ignored, and cells show their last saved values"}
+}
+
+// conditionalFills returns the conditional-formatting cell fills of a sheet when
+// opts.RenderConditionalFormatting is set. A sheet whose rules can't be read is
+// drawn without them, with a warning.
+func (c *ExcelConverter) conditionalFills(f *excelize.File, sheet string, opts pdf.Options) cellFills {
+	if !opts.RenderConditionalFormatting {
+		return nil
+	}
+	fills, err := loadConditionalFills(f, sheet)
+	if err != nil {
+		c.warnings = append(c.warnings, fmt.Sprintf("%s: conditional formatting skipped: %v", sheet, err))
+		return nil
+	}
+	return fills
+}
+
+// cellComments returns the comments of a sheet's cells when opts.RenderComments
+// is on. A sheet whose comments can't be read is drawn without them, with a warning.
+func (c *ExcelConverter) cellComments(f *excelize.File, sheet string, opts pdf.Options) cellComments {
+	if opts.CommentMode() == pdf.CommentsOff {
+		return nil
+	}
+	comments, err := loadCellComments(f, sheet)
+	if err != nil {
+		c.warnings = append(c.warnings, fmt.Sprintf("%s: comments skipped: %v", sheet, err))
+		return nil
+	}
+	return comments
+}
+
+// sheetCaption returns the caption drawn above a sheet's table: the sheet name with
+// opts.ShowSheetTitles, otherwise opts.TableCaption
+func sheetCaption(sheetName string, opts pdf.Options) string {
+	if opts.ShowSheetTitles {
+		return sheetName
+	}
+	return opts.TableCaption
+}
+
+// sheetTabColor returns the tab color of a sheet as 6-digit hex, or "" if it has none
+func sheetTabColor(f *excelize.File, sheet string) string {
+	props, err := f.GetSheetProps(sheet)
+	if err != nil || props.TabColorRGB == nil {
+		return ""
+	}
+
+	indexed := 0
+	if props.TabColorIndexed != nil {
+		indexed = *props.TabColorIndexed
+	}
+	if *props.TabColorRGB == "" && props.TabColorTheme == nil && indexed == 0 {
+		return "" // An empty <tabColor/>
+	}
+
+	color := f.GetBaseColor(*props.TabColorRGB, indexed, props.TabColorTheme)
+	if props.TabColorTint != nil && *props.TabColorTint != 0 && len(color) == 6 {
+		color = excelize.ThemeColor(color, *props.TabColorTint)
+	}
+	return rgbHex(color)
+}
+
 // ConvertWithOptions allows specific sheet selection and other options
 func (c *ExcelConverter) ConvertWithOptions(inputPath, outputPath string, opts pdf.Options, sheetNames []string) error {
 	// Validate input
@@ -179,66 +459,74 @@ func (c *ExcelConverter) ConvertWithOptions(inputPath, outputPath string, opts p
 	}
 	defer f.Close()
 
+	rng, err := cellRangeOption(opts)
+	if err != nil {
+		return err
+	}
+
+	// If no sheets specified, use all sheets
+	if len(sheetNames) == 0 {
+		sheetNames = f.GetSheetList()
+	}
+
+	// Transposing needs whole sheets
+	if opts.Transpose {
+		return c.convertSheetRows(context.Background(), f, readSheets(f, sheetNames, opts.Locale), outputPath, opts)
+	}
+
 	// Create PDF builder
 	builder, err := pdf.NewBuilder(opts)
 	if err != nil {
 		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create PDF builder")
 	}
+	defer builder.Close()
+	c.warnings = macroWarnings(f)
+	stats := &Stats{}
 	
 	if c.onProgress != nil {
 		builder.SetProgressCallback(c.onProgress)
 	}
 
-	// If no sheets specified, use all sheets
-	if len(sheetNames) == 0 {
-		sheetNames = f.GetSheetList()
-	}
-
-	for sheetIdx, sheetName := range sheetNames {
+	for _, sheetName := range sheetNames {
 		// Verify sheet exists
 		sheetIndex, err := f.GetSheetIndex(sheetName)
 		if err != nil || sheetIndex < 0 {
 			continue // Skip non-existent sheets
 		}
+		builder.BeginSection(sheetName)
 
-		// Add new page for each sheet (except first)
-		if sheetIdx > 0 {
-			builder.AddPage()
-		} else {
-			builder.AddPage()
+		// Use streaming reader - sample first for orientation and column widths
+		var sampleRows [][]string
+		text := newTextColumns()
+		streamRows, err := f.Rows(sheetName)
+		if err == nil {
+			sampleRows = readSample(rng.iterator(&excelRowIterator{rows: streamRows, file: f, sheet: sheetName, locale: opts.Locale, text: text}), 100)
+			streamRows.Close()
 		}
 
-		builder.NewLine(10)
-
-		// Use streaming reader - sample first for column widths
-		streamRows, err := f.Rows(sheetName)
+		// Add a new page for each sheet, in the sheet's own orientation
+		view := readSheetView(f, sheetName, opts)
+		sheetOpts := c.sheetOptions(sampleRows, view.options(opts, rng))
+		if sheetOpts.Orientation != opts.Orientation {
+			stats.AutoOrientation = true
+		}
+		startSheet(builder, f, sheetName, sheetOpts)
+		stats.Sheets++
 		if err != nil {
 			continue
 		}
-		
-		var sampleRows [][]string
-		rowCount := 0
-		for streamRows.Next() && rowCount < 100 {
-			row, err := streamRows.Columns()
-			if err != nil {
-				continue
-			}
-			sampleRows = append(sampleRows, row)
-			rowCount++
-		}
-		streamRows.Close()
 
 		if len(sampleRows) == 0 {
-			builder.AddText("(Empty sheet)", pdf.DefaultStyle())
+			builder.AddEmptyMessage(opts.EmptyDataMessage)
 			continue
 		}
 
 		// Calculate column widths
-		colWidths := c.calculateColumnWidths(sampleRows, opts)
+		colWidths := c.calculateColumnWidths(sampleRows, sheetOpts)
 
 		// Prepare headers
 		var headers []string
-		if opts.HeaderRow && len(sampleRows) > 0 {
+		if sheetOpts.HeaderRow && len(sampleRows) > 0 {
 			headers = sampleRows[0]
 		}
 
@@ -249,54 +537,257 @@ func (c *ExcelConverter) ConvertWithOptions(inputPath, outputPath string, opts p
 		}
 
 		// Use adapter for streaming
-		rowIterator := &excelRowIterator{rows: streamRows}
-		if err := builder.DrawTableStreaming(headers, rowIterator, colWidths, opts.HeaderRow); err != nil {
+		rowIterator, err := newRowFilter(rng.iterator(&excelRowIterator{rows: streamRows, file: f, sheet: sheetName, locale: opts.Locale, fills: c.conditionalFills(f, sheetName, opts), comments: c.cellComments(f, sheetName, opts)}), sheetOpts, sampleRows[0])
+		if err != nil {
+			streamRows.Close()
+			return errors.NewWithDetails(errors.ErrInvalidOption, "Invalid row filter", sheetName, err.Error())
+		}
+		rowIterator.columns = len(colWidths)
+		builder.SetColumnTypes(text.apply(sampleColumnTypes(sampleRows, sheetOpts), rng))
+		builder.SetFilterColumns(view.filterColumns(sheetOpts, rng))
+		if err := builder.DrawTableStreaming(headers, rowIterator, colWidths, sheetOpts.HeaderRow); err != nil {
 			streamRows.Close()
 			return errors.Wrap(err, errors.ErrConversionFailed, "Failed to draw table")
 		}
 		streamRows.Close()
+		if err := rowIterator.Err(); err != nil {
+			return err
+		}
+		if rowIterator.DataRows() == 0 {
+			builder.AddEmptyMessage(opts.EmptyDataMessage)
+		}
+		c.warnings = append(c.warnings, rowIterator.Warnings(sheetName)...)
+		stats.addTable(rowIterator, len(colWidths))
 	}
 
 	// Save the PDF
 	if err := builder.Save(outputPath); err != nil {
 		return errors.Wrap(err, errors.ErrWriteFailed, "Failed to save PDF")
 	}
+	c.layout = builder.Layout()
+	stats.Orientation = c.layout.Orientation
+	c.stats = stats
 
 	return nil
 }
 
-// ConvertSheetToCSV exports a specific sheet to CSV, then converts to PDF
-func (c *ExcelConverter) ConvertSheetToCSV(inputPath, outputPath, sheetName string) (string, error) {
+// sheetRows is a sheet read by a reader other than excelize (ODS): its rows read
+// into memory, or streamed from the file by open
+type sheetRows struct {
+	name string
+	rows [][]string
+	open func() (rowStream, error) // Set instead of rows; called once per pass over the rows
+}
+
+// rowStream is a RowIterator reading a sheet from its file
+type rowStream interface {
+	pdf.RowIterator
+	Err() error // Why the rows ended early, if they did
+	Close() error
+}
+
+// sampleStream reads the first n rows of a streamed sheet in cell range rng
+func sampleStream(sheet sheetRows, rng *cellRange, n int) ([][]string, error) {
+	stream, err := sheet.open()
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+	sample := readSample(rng.iterator(stream), n)
+	return sample, stream.Err()
+}
+
+// readSheets reads the named sheets of a workbook into memory, skipping sheets
+// that don't exist or can't be read
+func readSheets(f *excelize.File, names []string, locale string) []sheetRows {
+	var sheets []sheetRows
+	for _, name := range names {
+		if index, err := f.GetSheetIndex(name); err != nil || index < 0 {
+			continue
+		}
+		streamRows, err := f.Rows(name)
+		if err != nil {
+			continue
+		}
+		rows := readSample(&excelRowIterator{rows: streamRows, file: f, sheet: name, locale: locale}, math.MaxInt)
+		streamRows.Close()
+		sheets = append(sheets, sheetRows{name: name, rows: rows})
+	}
+	return sheets
+}
+
+// convertSheetRows draws sheets read by a reader other than excelize, or read into
+// memory, the same way Convert draws workbook sheets: one section per sheet, sized
+// from the first 100 rows. Streamed sheets are read twice, for the sample and for
+// drawing. f is the workbook they were read from, or nil (ODS). With
+// Options.Transpose each sheet's rows and columns are swapped first, so the
+// sheets must be in memory. It stops once ctx is done.
+func (c *ExcelConverter) convertSheetRows(ctx context.Context, f *excelize.File, sheets []sheetRows, outputPath string, opts pdf.Options) error {
+	rng, err := cellRangeOption(opts)
+	if err != nil {
+		return err
+	}
+	if opts.Transpose {
+		opts = transposedOptions(opts)
+	}
+
+	builder, err := pdf.NewBuilder(opts)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create PDF builder")
+	}
+	defer builder.Close()
+	c.warnings = macroWarnings(f)
+	stats := &Stats{}
+
+	if c.onProgress != nil {
+		builder.SetProgressCallback(c.onProgress)
+	}
+
+	for _, sheet := range sheets {
+		if err := contextError(ctx, ""); err != nil {
+			return err
+		}
+		builder.BeginSection(sheet.name)
+		var rows, sampleRows [][]string
+		if sheet.open != nil {
+			if sampleRows, err = sampleStream(sheet, rng, 100); err != nil {
+				return err
+			}
+		} else {
+			rows = rng.rows(sheet.rows)
+			if opts.Transpose {
+				rows = transposeRows(rows)
+			}
+			sampleRows = rows
+			if len(sampleRows) > 100 {
+				sampleRows = sampleRows[:100]
+			}
+		}
+
+		// Header detection and orientation are per sheet, as sheets can differ
+		view := readSheetView(f, sheet.name, opts)
+		sheetOpts := c.sheetOptions(sampleRows, view.options(opts, rng))
+		if sheetOpts.Orientation != opts.Orientation {
+			stats.AutoOrientation = true
+		}
+		startSheet(builder, f, sheet.name, sheetOpts)
+		stats.Sheets++
+		if rng.truncates(sheet.rows) {
+			stats.Truncated = true
+		}
+
+		if len(sampleRows) == 0 {
+			builder.AddEmptyMessage(opts.EmptyDataMessage)
+			continue
+		}
+
+		colWidths := c.calculateColumnWidths(sampleRows, sheetOpts)
+
+		var headers []string
+		if sheetOpts.HeaderRow {
+			headers = sampleRows[0]
+		}
+
+		var source pdf.RowIterator = &sliceRowIterator{rows: rows}
+		var stream rowStream
+		if sheet.open != nil {
+			if stream, err = sheet.open(); err != nil {
+				return err
+			}
+			source = rng.iterator(stream)
+		}
+		closeStream := func() error {
+			if stream == nil {
+				return nil
+			}
+			stream.Close()
+			return stream.Err()
+		}
+
+		rowIterator, err := newRowFilter(source, sheetOpts, sampleRows[0])
+		if err != nil {
+			closeStream()
+			return errors.NewWithDetails(errors.ErrInvalidOption, "Invalid row filter", sheet.name, err.Error())
+		}
+		rowIterator.columns = len(colWidths)
+		rowIterator.ctx = ctx
+		builder.SetColumnTypes(sampleColumnTypes(sampleRows, sheetOpts))
+		builder.SetFilterColumns(view.filterColumns(sheetOpts, rng))
+		if err := builder.DrawTableStreaming(headers, rowIterator, colWidths, sheetOpts.HeaderRow); err != nil {
+			closeStream()
+			return errors.Wrap(err, errors.ErrConversionFailed, "Failed to draw table")
+		}
+		if err := closeStream(); err != nil {
+			return err
+		}
+		if err := rowIterator.Err(); err != nil {
+			return err
+		}
+		if rowIterator.DataRows() == 0 {
+			builder.AddEmptyMessage(opts.EmptyDataMessage)
+		}
+		c.warnings = append(c.warnings, rowIterator.Warnings(sheet.name)...)
+		stats.addTable(rowIterator, len(colWidths))
+	}
+
+	if err := builder.Save(outputPath); err != nil {
+		return errors.Wrap(err, errors.ErrWriteFailed, "Failed to save PDF")
+	}
+	c.layout = builder.Layout()
+	stats.Orientation = c.layout.Orientation
+	c.stats = stats
+
+	return nil
+}
+
+// ConvertSheetToCSV exports a sheet as RFC 4180 CSV (fields quoted as needed, CRLF
+// line endings) to a file created in tempDir ("" = $TMPDIR or the OS default). It
+// returns the file's path and the delimiter used, which is ',' when delimiter is 0.
+// The caller removes the file when done; on error no file is left behind.
+func (c *ExcelConverter) ConvertSheetToCSV(inputPath, tempDir, sheetName string, delimiter rune) (string, rune, error) {
+	if delimiter == 0 {
+		delimiter = ','
+	}
+	if delimiter == '"' || delimiter == '\r' || delimiter == '\n' || !utf8.ValidRune(delimiter) || delimiter == utf8.RuneError {
+		return "", 0, errors.NewWithDetails(errors.ErrInvalidOption, "Invalid CSV delimiter", inputPath, fmt.Sprintf("%q", delimiter))
+	}
+
 	f, err := excelize.OpenFile(inputPath)
 	if err != nil {
-		return "", errors.NewWithDetails(errors.ErrConversionFailed, "Failed to open Excel file", inputPath, err.Error())
+		return "", 0, errors.NewWithDetails(errors.ErrConversionFailed, "Failed to open Excel file", inputPath, err.Error())
 	}
 	defer f.Close()
 
 	// Get rows from specified sheet
 	rows, err := f.GetRows(sheetName)
 	if err != nil {
-		return "", errors.NewWithDetails(errors.ErrConversionFailed, "Failed to read sheet", sheetName, err.Error())
+		return "", 0, errors.NewWithDetails(errors.ErrConversionFailed, "Failed to read sheet", sheetName, err.Error())
 	}
 
 	// Create temp CSV file
-	csvPath := filepath.Join(filepath.Dir(outputPath), fmt.Sprintf("%s_%s.csv", 
-		strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath)), 
-		sheetName))
-
-	csvFile, err := os.Create(csvPath)
+	base := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+	csvFile, err := os.CreateTemp(tempDir, fmt.Sprintf("%s_%s-*.csv", base, sheetName))
 	if err != nil {
-		return "", errors.Wrap(err, errors.ErrWriteFailed, "Failed to create temp CSV")
+		return "", 0, errors.Wrap(err, errors.ErrWriteFailed, "Failed to create temp CSV")
 	}
-	defer csvFile.Close()
+	csvPath := csvFile.Name()
 
 	// Write rows to CSV
-	for _, row := range rows {
-		line := strings.Join(row, ",")
-		csvFile.WriteString(line + "\n")
+	writer := csv.NewWriter(csvFile)
+	writer.Comma = delimiter
+	writer.UseCRLF = true
+	writer.WriteAll(rows) // Flushes; the error is reported by writer.Error
+	if err := writer.Error(); err != nil {
+		csvFile.Close()
+		os.Remove(csvPath)
+		return "", 0, errors.Wrap(err, errors.ErrWriteFailed, "Failed to write temp CSV")
+	}
+	if err := csvFile.Close(); err != nil {
+		os.Remove(csvPath)
+		return "", 0, errors.Wrap(err, errors.ErrWriteFailed, "Failed to write temp CSV")
 	}
 
-	return csvPath, nil
+	return csvPath, delimiter, nil
 }
 
 // calculateColumnWidths calculates optimal column widths based on content
@@ -317,7 +808,37 @@ func (c *ExcelConverter) calculateColumnWidths(rows [][]string, opts pdf.Options
 		return nil
 	}
 
-	// Calculate max width for each column
+	// Fixed equal-width columns
+	if !opts.AutoWidth {
+		return equalColumnWidths(maxCols, opts.ContentWidth())
+	}
+	colMaxWidths := c.naturalColumnWidths(rows, maxCols, opts)
+
+	// Scale to fit page width
+	totalWidth := 0.0
+	for _, w := range colMaxWidths {
+		totalWidth += w
+	}
+
+	contentWidth := opts.ContentWidth()
+	if totalWidth > contentWidth {
+		scale := contentWidth / totalWidth
+		floor := opts.ScaledColumnFloor()
+		for i := range colMaxWidths {
+			colMaxWidths[i] *= scale
+			// Ensure minimum readable width
+			if colMaxWidths[i] < floor {
+				colMaxWidths[i] = floor
+			}
+		}
+	}
+
+	return colMaxWidths
+}
+
+// naturalColumnWidths estimates the width of each of maxCols columns from the first
+// 100 rows, within the configured min/max, before fitting them to the page
+func (c *ExcelConverter) naturalColumnWidths(rows [][]string, maxCols int, opts pdf.Options) []float64 {
 	colMaxWidths := make([]float64, maxCols)
 
 	// Sample first 100 rows for width calculation
@@ -326,7 +847,7 @@ func (c *ExcelConverter) calculateColumnWidths(rows [][]string, opts pdf.Options
 		sampleSize = len(rows)
 	}
 
-	for i := 0; i < sampleSize; i++ {
+	for i := widthSampleStart(opts); i < sampleSize; i++ {
 		row := rows[i]
 		for j, cell := range row {
 			if j >= maxCols {
@@ -341,14 +862,7 @@ func (c *ExcelConverter) calculateColumnWidths(rows [][]string, opts pdf.Options
 	}
 
 	// Use custom min/max from options, or defaults
-	minColWidth := opts.MinColumnWidth
-	maxColWidth := opts.MaxColumnWidth
-	if minColWidth <= 0 {
-		minColWidth = 40.0
-	}
-	if maxColWidth <= 0 {
-		maxColWidth = 180.0
-	}
+	minColWidth, maxColWidth := opts.ColumnWidthLimits()
 
 	for i := range colMaxWidths {
 		if colMaxWidths[i] < minColWidth {
@@ -359,25 +873,63 @@ func (c *ExcelConverter) calculateColumnWidths(rows [][]string, opts pdf.Options
 		}
 	}
 
-	// Scale to fit page width
+	return colMaxWidths
+}
+
+// sheetOrientation returns the page orientation of a sheet. With AutoOrientation,
+// a sheet whose columns are too wide for a portrait page but fit a landscape one
+// (with the same 20% allowance for squeezing as CSV) gets landscape pages.
+func (c *ExcelConverter) sheetOrientation(sample [][]string, opts pdf.Options) pdf.Orientation {
+	if !opts.AutoOrientation || !opts.AutoWidth || opts.Orientation != pdf.Portrait {
+		return opts.Orientation
+	}
+	maxCols := 0
+	for _, row := range sample {
+		if len(row) > maxCols {
+			maxCols = len(row)
+		}
+	}
+
 	totalWidth := 0.0
-	for _, w := range colMaxWidths {
+	for _, w := range c.naturalColumnWidths(sample, maxCols, opts) {
 		totalWidth += w
 	}
+	landscape := opts
+	landscape.Orientation = pdf.Landscape
+	if totalWidth > opts.ContentWidth() && totalWidth <= landscape.ContentWidth()*1.2 {
+		return pdf.Landscape
+	}
+	return opts.Orientation
+}
 
-	contentWidth := opts.ContentWidth()
-	if totalWidth > contentWidth {
-		scale := contentWidth / totalWidth
-		for i := range colMaxWidths {
-			colMaxWidths[i] *= scale
-			// Ensure minimum readable width (at least 35 points = ~5-6 chars)
-			if colMaxWidths[i] < 35 {
-				colMaxWidths[i] = 35
-			}
-		}
+// sheetOptions returns opts adjusted to one sheet from its sample rows, as sheets
+// can differ: whether it has a header row (AutoDetectHeader) and its orientation
+func (c *ExcelConverter) sheetOptions(sample [][]string, opts pdf.Options) pdf.Options {
+	sheetOpts := opts
+	if len(sample) == 0 {
+		return sheetOpts
+	}
+	if opts.AutoDetectHeader {
+		sheetOpts.HeaderRow = detectHeaderRow(sample, opts)
 	}
+	sheetOpts.Orientation = c.sheetOrientation(sample, sheetOpts)
+	return sheetOpts
+}
 
-	return colMaxWidths
+// startSheet starts a sheet's first page in its orientation, with the tab color bar
+// (f is nil for sheets not read by excelize) and the caption
+func startSheet(builder *pdf.Builder, f *excelize.File, sheetName string, opts pdf.Options) {
+	builder.AddPageWithOrientation(opts.Orientation)
+
+	if opts.SheetTabColors && f != nil {
+		if color := sheetTabColor(f, sheetName); color != "" {
+			builder.DrawSectionBar(pdf.ParseHexColor(color))
+		}
+	}
+	builder.NewLine(10)
+	if caption := sheetCaption(sheetName, opts); caption != "" {
+		builder.AddCaption(caption)
+	}
 }
 
 // GetSheetList returns all sheet names in an Excel file
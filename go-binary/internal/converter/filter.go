@@ -0,0 +1,126 @@
+package converter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+)
+
+// filterOperators lists supported operators. An expression is split at the
+// earliest operator, the longest one where several start there, so ">=" is not
+// mistaken for ">" and "note contains >5" compares with contains.
+var filterOperators = []string{"==", "!=", ">=", "<=", ">", "<", " contains "}
+
+// filterExpr is a parsed row filter such as "col3 > 100" or "status == active"
+type filterExpr struct {
+	column   string // Header name or colN (1-based)
+	operator string
+	value    string
+	index    int    // Resolved column index
+	locale   string // Number format of the cells and the value (Options.InputNumberLocale)
+}
+
+// parseFilterExpr parses a filter expression of the form "<column> <op> <value>".
+// An empty expression returns nil (no filtering).
+func parseFilterExpr(expr string) (*filterExpr, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, nil
+	}
+
+	// The column name takes at least the first character
+	lower := strings.ToLower(expr)
+	pos, op := -1, ""
+	for _, candidate := range filterOperators {
+		i := strings.Index(lower[1:], candidate)
+		if i < 0 {
+			continue
+		}
+		i++
+		if pos < 0 || i < pos || (i == pos && len(candidate) > len(op)) {
+			pos, op = i, candidate
+		}
+	}
+	if pos > 0 {
+		return &filterExpr{
+			column:   strings.TrimSpace(expr[:pos]),
+			operator: strings.TrimSpace(op),
+			value:    unquote(strings.TrimSpace(expr[pos+len(op):])),
+			index:    -1,
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid filter %q: expected \"<column> <op> <value>\" with op one of == != > < >= <= contains", expr)
+}
+
+// resolve binds the filter column to an index using the header row (by name)
+// or a colN reference (1-based)
+func (f *filterExpr) resolve(headers []string) error {
+	for i, h := range headers {
+		if strings.EqualFold(strings.TrimSpace(h), f.column) {
+			f.index = i
+			return nil
+		}
+	}
+	lower := strings.ToLower(f.column)
+	if strings.HasPrefix(lower, "col") {
+		if n, err := strconv.Atoi(lower[3:]); err == nil && n > 0 {
+			f.index = n - 1
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown filter column %q", f.column)
+}
+
+// Match reports whether row satisfies the filter. Comparisons are numeric when
+// both sides parse as numbers in the filter's locale, otherwise case-sensitive
+// string comparisons.
+func (f *filterExpr) Match(row []string) bool {
+	cell := ""
+	if f.index < len(row) {
+		cell = strings.TrimSpace(row[f.index])
+	}
+
+	if f.operator == "contains" {
+		return strings.Contains(strings.ToLower(cell), strings.ToLower(f.value))
+	}
+
+	var cmp int
+	a, aok := pdf.ParseNumber(cell, f.locale)
+	b, bok := pdf.ParseNumber(f.value, f.locale)
+	if aok && bok {
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(cell, f.value)
+	}
+
+	switch f.operator {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// unquote strips one pair of matching single or double quotes
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
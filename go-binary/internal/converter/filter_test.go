@@ -0,0 +1,43 @@
+package converter
+
+import "testing"
+
+func TestParseFilterExpr(t *testing.T) {
+	tests := []struct {
+		expr                    string
+		column, operator, value string
+	}{
+		{"col3 > 100", "col3", ">", "100"},
+		{"amount>=5", "amount", ">=", "5"},
+		{"amount <= 5", "amount", "<=", "5"},
+		{"status == active", "status", "==", "active"},
+		{"status != 'on hold'", "status", "!=", "on hold"},
+		{"note contains >5", "note", "contains", ">5"},
+		{"note CONTAINS a == b", "note", "contains", "a == b"},
+		{"a<b == c", "a", "<", "b == c"},
+		{"score > <=1", "score", ">", "<=1"},
+		{`name == "x"`, "name", "==", "x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := parseFilterExpr(tt.expr)
+			if err != nil {
+				t.Fatalf("parseFilterExpr: %v", err)
+			}
+			if f.column != tt.column || f.operator != tt.operator || f.value != tt.value {
+				t.Errorf("parsed %q %q %q, want %q %q %q", f.column, f.operator, f.value, tt.column, tt.operator, tt.value)
+			}
+		})
+	}
+}
+
+func TestParseFilterExprInvalid(t *testing.T) {
+	if f, err := parseFilterExpr("  "); f != nil || err != nil {
+		t.Errorf("empty filter = %v, %v, want no filter", f, err)
+	}
+	for _, expr := range []string{"amount", "> 5", "== x", "contains x"} {
+		if _, err := parseFilterExpr(expr); err == nil {
+			t.Errorf("parseFilterExpr(%q) succeeded, want an error", expr)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package converter
+
+import (
+	"strings"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/xuri/excelize/v2"
+)
+
+// maxFrozenHeaderRows caps the frozen rows repeated as table headers, so a sheet
+// frozen far down doesn't fill every page with its top
+const maxFrozenHeaderRows = 5
+
+// sheetView is what Options.RespectFreezePanes reads from a sheet: the rows frozen
+// at its top and the range of its autofilter
+type sheetView struct {
+	frozenRows int
+	filter     *cellRange // nil = no autofilter
+}
+
+// readSheetView reads the frozen rows and autofilter of a sheet. f may be nil, for
+// sheets that don't come from excelize, which have neither.
+func readSheetView(f *excelize.File, sheet string, opts pdf.Options) sheetView {
+	var view sheetView
+	if f == nil || !opts.RespectFreezePanes {
+		return view
+	}
+	if panes, err := f.GetPanes(sheet); err == nil && panes.Freeze {
+		view.frozenRows = panes.YSplit
+	}
+	for _, name := range f.GetDefinedName() {
+		if name.Name != "_xlnm._FilterDatabase" || name.Scope != sheet {
+			continue
+		}
+		// E.g. "'Q3 Orders'!$A$1:$F$120"
+		ref := name.RefersTo[strings.LastIndex(name.RefersTo, "!")+1:]
+		if ranges := parseSqref(ref); len(ranges) == 1 {
+			view.filter = &ranges[0]
+		}
+	}
+	return view
+}
+
+// options returns opts for a sheet with its table starting at the top of rng (nil
+// = the whole sheet). The frozen rows still in the table become its header rows,
+// repeated on every page; otherwise an autofilter starting on the table's first
+// row makes that row the header. Either replaces AutoDetectHeader.
+func (v sheetView) options(opts pdf.Options, rng *cellRange) pdf.Options {
+	if opts.Transpose {
+		return opts // The table has no header row
+	}
+	first := 1
+	if rng != nil {
+		first = rng.row1
+	}
+	if frozen := v.frozenRows - (first - 1); frozen > 0 {
+		opts.HeaderRow, opts.HeaderRows, opts.AutoDetectHeader = true, min(frozen, maxFrozenHeaderRows), false
+	} else if v.filter != nil && v.filter.row1 == first {
+		opts.HeaderRow, opts.HeaderRows, opts.AutoDetectHeader = true, 1, false
+	}
+	return opts
+}
+
+// filterColumns returns the table columns of the autofilter, for
+// Builder.SetFilterColumns, when its first row is the table's last header row.
+// opts are the sheet's options and rng the range the table is cut to.
+func (v sheetView) filterColumns(opts pdf.Options, rng *cellRange) []bool {
+	if v.filter == nil || !opts.HeaderRow || opts.Transpose {
+		return nil
+	}
+	firstRow, firstCol := 1, 1
+	if rng != nil {
+		firstRow, firstCol = rng.row1, rng.col1
+	}
+	if v.filter.row1 != firstRow+headerRowCount(opts)-1 {
+		return nil
+	}
+	var columns []bool
+	for col := v.filter.col1; col <= v.filter.col2; col++ {
+		i := col - firstCol
+		if i < 0 || (rng != nil && col > rng.col2) {
+			continue
+		}
+		for len(columns) <= i {
+			columns = append(columns, false)
+		}
+		columns[i] = true
+	}
+	return columns
+}
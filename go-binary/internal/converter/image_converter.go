@@ -0,0 +1,145 @@
+package converter
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+)
+
+func init() {
+	Register(FormatPNG, func() Converter { return NewImageConverter() })
+	Register(FormatJPEG, func() Converter { return NewImageConverter() })
+}
+
+// Image file signatures, used to report broken images as CORRUPT_FILE
+var (
+	pngSignature  = []byte("\x89PNG\r\n\x1a\n")
+	jpegSignature = []byte{0xFF, 0xD8, 0xFF}
+)
+
+// ImageConverter places PNG/JPEG images on PDF pages, one image per page
+type ImageConverter struct {
+	onProgress func(int)
+	layout     pdf.Layout
+	stats      *Stats
+}
+
+// NewImageConverter creates a new image converter
+func NewImageConverter() *ImageConverter {
+	return &ImageConverter{}
+}
+
+// SetProgressCallback sets the callback for progress reporting
+func (c *ImageConverter) SetProgressCallback(callback func(int)) {
+	c.onProgress = callback
+}
+
+// Layout returns the page structure of the last PDF written by Convert
+func (c *ImageConverter) Layout() pdf.Layout {
+	return c.layout
+}
+
+// Stats returns the statistics of the last Convert, or nil if it didn't render natively
+func (c *ImageConverter) Stats() *Stats {
+	return c.stats
+}
+
+// SupportedExtensions returns extensions handled by this converter
+func (c *ImageConverter) SupportedExtensions() []string {
+	return []string{".png", ".jpg", ".jpeg"}
+}
+
+// Validate checks if the input file is a readable PNG or JPEG image
+func (c *ImageConverter) Validate(inputPath string) error {
+	if err := validateExists(inputPath); err != nil {
+		return err
+	}
+	if _, _, err := pdf.ImageSize(inputPath); err != nil {
+		if hasSignature(inputPath, pngSignature) || hasSignature(inputPath, jpegSignature) {
+			return errors.NewWithDetails(errors.ErrCorruptFile, "Image file is damaged", inputPath, err.Error())
+		}
+		return errors.NewWithDetails(errors.ErrInvalidFormat, "Invalid image format", inputPath, err.Error())
+	}
+	return nil
+}
+
+// Convert writes a single image to a one-page PDF
+func (c *ImageConverter) Convert(inputPath, outputPath string, opts pdf.Options) error {
+	return c.ConvertCtx(context.Background(), inputPath, outputPath, opts)
+}
+
+// ConvertCtx is Convert, failing without output once ctx is done
+func (c *ImageConverter) ConvertCtx(ctx context.Context, inputPath, outputPath string, opts pdf.Options) error {
+	return c.convertImages(ctx, []string{inputPath}, outputPath, opts)
+}
+
+// ConvertImages writes each image to its own page, scaled into the content area
+// according to opts.ImageFit. With opts.AutoOrientation each page is portrait or
+// landscape to match its image; otherwise all pages use opts.Orientation.
+func (c *ImageConverter) ConvertImages(inputPaths []string, outputPath string, opts pdf.Options) error {
+	return c.convertImages(context.Background(), inputPaths, outputPath, opts)
+}
+
+// convertImages is ConvertImages, stopping between images once ctx is done
+func (c *ImageConverter) convertImages(ctx context.Context, inputPaths []string, outputPath string, opts pdf.Options) error {
+	if len(inputPaths) == 0 {
+		return errors.New(errors.ErrInvalidFormat, "No images to convert")
+	}
+	for _, path := range inputPaths {
+		if err := c.Validate(path); err != nil {
+			return err
+		}
+	}
+
+	builder, err := pdf.NewBuilder(opts)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create PDF builder")
+	}
+	defer builder.Close()
+
+	stats := &Stats{}
+	for i, path := range inputPaths {
+		if err := contextError(ctx, path); err != nil {
+			return err
+		}
+		orientation := opts.Orientation
+		if opts.AutoOrientation {
+			// Sizes were checked by Validate
+			w, h, _ := pdf.ImageSize(path)
+			orientation = pdf.Portrait
+			if w > h {
+				orientation = pdf.Landscape
+			}
+			if orientation != opts.Orientation {
+				stats.AutoOrientation = true
+			}
+		}
+
+		builder.BeginSection(filepath.Base(path))
+		builder.AddPageWithOrientation(orientation)
+		x, y, w, h := builder.ContentArea()
+		if err := builder.AddImageFitted(path, x, y, w, h, opts.ImageFit); err != nil {
+			// Validate only reads the image header, so truncated pixel data shows up here
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return errors.NewWithDetails(errors.ErrCorruptFile, "Image file is damaged", path, err.Error())
+			}
+			return errors.NewWithDetails(errors.ErrConversionFailed, "Failed to draw image", path, err.Error())
+		}
+
+		if c.onProgress != nil {
+			c.onProgress((i + 1) * 100 / len(inputPaths))
+		}
+	}
+
+	if err := builder.Save(outputPath); err != nil {
+		return errors.Wrap(err, errors.ErrWriteFailed, "Failed to save PDF")
+	}
+	c.layout = builder.Layout()
+	stats.Orientation = c.layout.Orientation
+	c.stats = stats
+
+	return nil
+}
@@ -0,0 +1,257 @@
+package converter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+)
+
+func init() {
+	Register(FormatXLS, func() Converter { return NewXLSConverter() })
+	Register(FormatPPT, func() Converter { return NewLegacyPPTConverter() })
+}
+
+// findLibreOffice returns the LibreOffice binary to use, preferring an explicit path
+func findLibreOffice(path string) (string, bool) {
+	pptxConverter := NewPPTXConverter()
+	if path != "" {
+		pptxConverter.SetLibreOfficePath(path)
+	}
+	return pptxConverter.GetLibreOfficePath(), pptxConverter.HasLibreOffice()
+}
+
+// validateExists checks that the input file exists
+func validateExists(inputPath string) error {
+	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
+		return errors.NewWithFile(errors.ErrFileNotFound, "File not found", inputPath)
+	}
+	return nil
+}
+
+// XLSConverter converts legacy .xls workbooks. With LibreOffice the file is converted
+// to XLSX first and rendered natively; without it the native Excel reader is tried.
+type XLSConverter struct {
+	libreOfficePath string
+	onProgress      func(int)
+	layout          pdf.Layout
+	warnings        []string
+	stats           *Stats
+}
+
+// NewXLSConverter creates a new XLS converter
+func NewXLSConverter() *XLSConverter {
+	return &XLSConverter{}
+}
+
+// SetLibreOfficePath sets the LibreOffice binary to use
+func (c *XLSConverter) SetLibreOfficePath(path string) {
+	c.libreOfficePath = path
+}
+
+// SetProgressCallback sets the callback for progress reporting
+func (c *XLSConverter) SetProgressCallback(callback func(int)) {
+	c.onProgress = callback
+}
+
+// Layout returns the page structure of the last PDF written by Convert
+func (c *XLSConverter) Layout() pdf.Layout {
+	return c.layout
+}
+
+// Warnings returns non-fatal notes from the last Convert (e.g. dropped rows)
+func (c *XLSConverter) Warnings() []string {
+	return c.warnings
+}
+
+// Stats returns the statistics of the last Convert, or nil if it didn't render natively
+func (c *XLSConverter) Stats() *Stats {
+	return c.stats
+}
+
+// Requirements lists LibreOffice, without which only the native Excel reader is tried
+func (c *XLSConverter) Requirements() []string {
+	return []string{RequirementLibreOffice}
+}
+
+// SupportedExtensions returns extensions handled by this converter
+func (c *XLSConverter) SupportedExtensions() []string {
+	return []string{".xls"}
+}
+
+// Validate checks the input file exists; the format is checked by the underlying converter
+func (c *XLSConverter) Validate(inputPath string) error {
+	return validateExists(inputPath)
+}
+
+// Convert performs the XLS to PDF conversion
+func (c *XLSConverter) Convert(inputPath, outputPath string, opts pdf.Options) error {
+	return c.ConvertCtx(context.Background(), inputPath, outputPath, opts)
+}
+
+// ConvertCtx is Convert, stopping between sheets and rows and killing LibreOffice
+// once ctx is done
+func (c *XLSConverter) ConvertCtx(ctx context.Context, inputPath, outputPath string, opts pdf.Options) error {
+	if err := c.Validate(inputPath); err != nil {
+		return err
+	}
+
+	excelConverter := NewExcelConverter()
+	excelConverter.SetProgressCallback(c.onProgress)
+
+	loPath, hasLibreOffice := findLibreOffice(c.libreOfficePath)
+	if !hasLibreOffice {
+		// No LibreOffice - try native converter (may have limited support)
+		err := excelConverter.ConvertCtx(ctx, inputPath, outputPath, opts)
+		c.layout, c.warnings, c.stats = excelConverter.Layout(), excelConverter.Warnings(), excelConverter.Stats()
+		return err
+	}
+
+	loConverter := NewLibreOfficeConverter(loPath, opts.TempDir)
+	if opts.PDFA {
+		// Only LibreOffice's own rendering can be PDF/A
+		loConverter.SetPDFA(true)
+		loConverter.SetImageQuality(opts.ImageQuality())
+		return loConverter.ConvertCtx(ctx, inputPath, outputPath)
+	}
+
+	// Convert XLS to XLSX first, then process with native Excel converter
+	tempDir, err := os.MkdirTemp(opts.TempDir, "gopdfconv-xls-*")
+	if err != nil {
+		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create temp directory")
+	}
+	defer os.RemoveAll(tempDir)
+	tempXlsx := filepath.Join(tempDir, "input.xlsx")
+	if err := loConverter.ConvertToCtx(ctx, inputPath, tempXlsx, "xlsx"); err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		// If XLSX conversion fails, try direct PDF conversion
+		return loConverter.ConvertCtx(ctx, inputPath, outputPath)
+	}
+
+	err = excelConverter.ConvertCtx(ctx, tempXlsx, outputPath, opts)
+	c.layout, c.warnings, c.stats = excelConverter.Layout(), excelConverter.Warnings(), excelConverter.Stats()
+	return err
+}
+
+// LegacyPPTConverter converts legacy .ppt presentations, preferring LibreOffice for
+// fidelity and falling back to PPTX rendering or native text extraction.
+type LegacyPPTConverter struct {
+	libreOfficePath string
+	forceNative     bool
+	layout          pdf.Layout
+	warnings        []string
+	stats           *Stats
+}
+
+// NewLegacyPPTConverter creates a new legacy PPT converter
+func NewLegacyPPTConverter() *LegacyPPTConverter {
+	return &LegacyPPTConverter{}
+}
+
+// SetLibreOfficePath sets the LibreOffice binary to use
+func (c *LegacyPPTConverter) SetLibreOfficePath(path string) {
+	c.libreOfficePath = path
+}
+
+// SetForceNative renders natively instead of letting LibreOffice produce the PDF
+func (c *LegacyPPTConverter) SetForceNative(force bool) {
+	c.forceNative = force
+}
+
+// Layout returns the page structure of the last PDF written by Convert
+func (c *LegacyPPTConverter) Layout() pdf.Layout {
+	return c.layout
+}
+
+// Stats returns the statistics of the last Convert, or nil if it didn't render natively
+func (c *LegacyPPTConverter) Stats() *Stats {
+	return c.stats
+}
+
+// Warnings returns non-fatal notes from the last Convert (e.g. slides drawn as images)
+func (c *LegacyPPTConverter) Warnings() []string {
+	return c.warnings
+}
+
+// Requirements lists LibreOffice, without which only slide text is extracted
+func (c *LegacyPPTConverter) Requirements() []string {
+	return []string{RequirementLibreOffice}
+}
+
+// SupportedExtensions returns extensions handled by this converter
+func (c *LegacyPPTConverter) SupportedExtensions() []string {
+	return []string{".ppt"}
+}
+
+// Validate checks the input file exists; the format is checked by the underlying converter
+func (c *LegacyPPTConverter) Validate(inputPath string) error {
+	return validateExists(inputPath)
+}
+
+// Convert performs the PPT to PDF conversion
+func (c *LegacyPPTConverter) Convert(inputPath, outputPath string, opts pdf.Options) error {
+	return c.ConvertCtx(context.Background(), inputPath, outputPath, opts)
+}
+
+// ConvertCtx is Convert, stopping between slides and killing LibreOffice once ctx is done
+func (c *LegacyPPTConverter) ConvertCtx(ctx context.Context, inputPath, outputPath string, opts pdf.Options) error {
+	if err := c.Validate(inputPath); err != nil {
+		return err
+	}
+	rng, err := slideRangeOption(opts)
+	if err != nil {
+		return err
+	}
+	c.warnings = nil
+
+	loPath, hasLibreOffice := findLibreOffice(c.libreOfficePath)
+	if !hasLibreOffice {
+		// No LibreOffice - use native PPT parser (text extraction only)
+		return c.convertNative(ctx, inputPath, outputPath, opts)
+	}
+
+	loConverter := NewLibreOfficeConverter(loPath, opts.TempDir)
+	loConverter.SetPDFA(opts.PDFA)
+	loConverter.SetImageQuality(opts.ImageQuality())
+	loConverter.SetPageRange(rng.String())
+	if !c.forceNative {
+		// Try LibreOffice first for best results
+		if err := loConverter.ConvertCtx(ctx, inputPath, outputPath); err == nil || opts.PDFA || ctx.Err() != nil {
+			return err
+		}
+	}
+
+	// Convert PPT to PPTX first, then render natively
+	tempDir, err := os.MkdirTemp(opts.TempDir, "gopdfconv-ppt-*")
+	if err != nil {
+		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create temp directory")
+	}
+	defer os.RemoveAll(tempDir)
+	tempPptx := filepath.Join(tempDir, "input.pptx")
+	if err := loConverter.ConvertToCtx(ctx, inputPath, tempPptx, "pptx"); err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		// Fall back to native PPT parser
+		return c.convertNative(ctx, inputPath, outputPath, opts)
+	}
+
+	pptxConverter := NewPPTXConverter()
+	pptxConverter.SetLibreOfficePath(loPath)
+	pptxConverter.SetForceNative(true)
+	err = pptxConverter.ConvertCtx(ctx, tempPptx, outputPath, opts)
+	c.layout, c.warnings, c.stats = pptxConverter.Layout(), pptxConverter.Warnings(), pptxConverter.Stats()
+	return err
+}
+
+// convertNative extracts slide text with the native PPT parser
+func (c *LegacyPPTConverter) convertNative(ctx context.Context, inputPath, outputPath string, opts pdf.Options) error {
+	pptConverter := NewPPTConverter()
+	err := pptConverter.ConvertCtx(ctx, inputPath, outputPath, opts)
+	c.layout, c.stats = pptConverter.Layout(), pptConverter.Stats()
+	return err
+}
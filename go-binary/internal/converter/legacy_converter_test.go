@@ -0,0 +1,68 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+)
+
+// fakeLibreOffice writes a shell script standing in for soffice: every
+// conversion writes a file that is not a valid document to its --outdir
+func fakeLibreOffice(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake LibreOffice is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "soffice")
+	script := `#!/bin/sh
+while [ $# -gt 0 ]; do
+	if [ "$1" = --outdir ]; then out=$2; fi
+	shift
+done
+echo "not a document" > "$out/converted"
+`
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// dirNames returns the names of the entries in dir
+func dirNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestXLSIntermediateRemovedOnError(t *testing.T) {
+	inputDir, tempDir := t.TempDir(), t.TempDir()
+	input := filepath.Join(inputDir, "report.xls")
+	if err := os.WriteFile(input, []byte("legacy workbook"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewXLSConverter()
+	c.SetLibreOfficePath(fakeLibreOffice(t))
+	opts := pdf.DefaultOptions()
+	opts.TempDir = tempDir
+	if err := c.Convert(input, filepath.Join(t.TempDir(), "report.pdf"), opts); err == nil {
+		t.Fatal("converting an XLS whose intermediate XLSX is damaged succeeded")
+	}
+
+	if names := dirNames(t, tempDir); len(names) != 0 {
+		t.Errorf("temp directory still holds %q", names)
+	}
+	if names := dirNames(t, inputDir); len(names) != 1 {
+		t.Errorf("input directory holds %q, want only the input", names)
+	}
+}
@@ -1,27 +1,60 @@
 package converter
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
 	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
 )
 
 // LibreOfficeConverter handles conversion using LibreOffice
 type LibreOfficeConverter struct {
 	libreOfficePath string
+	tempDir         string // Parent of the per-run profile/output directories ("" = OS default)
+	pdfa            bool   // Export PDF/A-1b (Options.PDFA)
+	pageRange       string // Export only these pages, e.g. "2-5,8" (Options.SlideRange; "" = all)
+	imageQuality    pdf.ImageQuality // Downsample and recompress images (Options.Quality)
+	hiddenSlides    bool   // Export hidden slides too, so pages match deck positions
 }
 
-// NewLibreOfficeConverter creates a new LibreOffice converter
-func NewLibreOfficeConverter(path string) *LibreOfficeConverter {
+// NewLibreOfficeConverter creates a new LibreOffice converter. Its temporary
+// profile and output directories are created in tempDir ("" = $TMPDIR or the OS default).
+func NewLibreOfficeConverter(path, tempDir string) *LibreOfficeConverter {
 	return &LibreOfficeConverter{
 		libreOfficePath: path,
+		tempDir:         tempDir,
 	}
 }
 
+// SetPDFA makes Convert export PDF/A-1b
+func (c *LibreOfficeConverter) SetPDFA(enabled bool) {
+	c.pdfa = enabled
+}
+
+// SetImageQuality makes Convert export images at most q.DPI pixels per inch, as
+// JPEG of quality q.JPEGQuality. A zero DPI keeps LibreOffice's defaults.
+func (c *LibreOfficeConverter) SetImageQuality(q pdf.ImageQuality) {
+	c.imageQuality = q
+}
+
+// SetPageRange makes Convert export only the given pages, e.g. "2-5,8"
+func (c *LibreOfficeConverter) SetPageRange(pages string) {
+	c.pageRange = pages
+}
+
+// SetExportHiddenSlides makes Convert export the slides hidden in a presentation,
+// which it otherwise leaves out
+func (c *LibreOfficeConverter) SetExportHiddenSlides(enabled bool) {
+	c.hiddenSlides = enabled
+}
+
 // pathToFileURL converts a file path to a file:// URL (handles Windows paths)
 func pathToFileURL(path string) string {
 	// Convert backslashes to forward slashes
@@ -39,6 +72,11 @@ func pathToFileURL(path string) string {
 
 // Convert performs the conversion using LibreOffice
 func (c *LibreOfficeConverter) Convert(inputPath, outputPath string) error {
+	return c.ConvertCtx(context.Background(), inputPath, outputPath)
+}
+
+// ConvertCtx is Convert, killing LibreOffice once ctx is done
+func (c *LibreOfficeConverter) ConvertCtx(ctx context.Context, inputPath, outputPath string) error {
 	// Check if file exists
 	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
 		return errors.NewWithFile(errors.ErrFileNotFound, "File not found", inputPath)
@@ -51,7 +89,7 @@ func (c *LibreOfficeConverter) Convert(inputPath, outputPath string) error {
 	}
 
 	// Create a temp directory for LibreOffice output and profile
-	tempDir, err := os.MkdirTemp("", "gopdfconv-*")
+	tempDir, err := os.MkdirTemp(c.tempDir, "gopdfconv-*")
 	if err != nil {
 		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create temp directory")
 	}
@@ -80,23 +118,37 @@ func (c *LibreOfficeConverter) Convert(inputPath, outputPath string) error {
 	} else if ext == ".docx" || ext == ".doc" || ext == ".odt" {
 		convertFilter = "pdf:writer_pdf_Export"
 	}
+	var filterOptions []string
+	if c.pdfa {
+		filterOptions = append(filterOptions, `"SelectPdfVersion":{"type":"long","value":"1"}`)
+	}
+	if c.pageRange != "" {
+		filterOptions = append(filterOptions, `"PageRange":{"type":"string","value":"`+c.pageRange+`"}`)
+	}
+	if c.hiddenSlides {
+		filterOptions = append(filterOptions, `"ExportHiddenSlides":{"type":"boolean","value":"true"}`)
+	}
+	if q := c.imageQuality; q.DPI > 0 {
+		filterOptions = append(filterOptions,
+			`"ReduceImageResolution":{"type":"boolean","value":"true"}`,
+			fmt.Sprintf(`"MaxImageResolution":{"type":"long","value":"%d"}`, q.DPI),
+			`"UseLosslessCompression":{"type":"boolean","value":"false"}`,
+			fmt.Sprintf(`"Quality":{"type":"long","value":"%d"}`, q.JPEGQuality))
+	}
+	if len(filterOptions) > 0 {
+		// Filter options in JSON syntax need LibreOffice 7.4 or later
+		if convertFilter == "pdf" {
+			convertFilter = "pdf:writer_pdf_Export"
+		}
+		convertFilter += ":{" + strings.Join(filterOptions, ",") + "}"
+	}
 
 	// Run LibreOffice conversion with a fresh temporary user profile
-	cmd := exec.Command(c.libreOfficePath,
-		"-env:UserInstallation="+userInstallURL,
-		"--headless",
-		"--invisible",
-		"--nologo",
-		"--nofirststartwizard",
-		"--convert-to", convertFilter,
-		"--outdir", tempDir,
-		absInputPath,
-	)
-
-	// Set environment to avoid GUI issues
-	cmd.Env = append(os.Environ(), "HOME="+tempDir)
-
+	cmd := c.command(ctx, userInstallURL, tempDir, convertFilter, absInputPath)
 	output, err := cmd.CombinedOutput()
+	if err := contextError(ctx, inputPath); err != nil {
+		return err
+	}
 	if err != nil {
 		return errors.NewWithDetails(errors.ErrConversionFailed, "LibreOffice conversion failed", inputPath, string(output))
 	}
@@ -132,7 +184,12 @@ func (c *LibreOfficeConverter) Convert(inputPath, outputPath string) error {
 
 // ConvertTo converts a file to a specific format using LibreOffice
 func (c *LibreOfficeConverter) ConvertTo(inputPath, outputPath, format string) error {
-	tempDir, err := os.MkdirTemp("", "gopdfconv-lo-*")
+	return c.ConvertToCtx(context.Background(), inputPath, outputPath, format)
+}
+
+// ConvertToCtx is ConvertTo, killing LibreOffice once ctx is done
+func (c *LibreOfficeConverter) ConvertToCtx(ctx context.Context, inputPath, outputPath, format string) error {
+	tempDir, err := os.MkdirTemp(c.tempDir, "gopdfconv-lo-*")
 	if err != nil {
 		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create temp directory")
 	}
@@ -151,21 +208,11 @@ func (c *LibreOfficeConverter) ConvertTo(inputPath, outputPath, format string) e
 	// Build user installation URL for temp profile
 	userInstallURL := pathToFileURL(profileDir)
 
-	cmd := exec.Command(c.libreOfficePath,
-		"-env:UserInstallation="+userInstallURL,
-		"--headless",
-		"--invisible",
-		"--nologo",
-		"--nofirststartwizard",
-		"--convert-to", format,
-		"--outdir", tempDir,
-		absInputPath,
-	)
-
-	// Set environment to avoid GUI issues
-	cmd.Env = append(os.Environ(), "HOME="+tempDir)
-
+	cmd := c.command(ctx, userInstallURL, tempDir, format, absInputPath)
 	output, err := cmd.CombinedOutput()
+	if err := contextError(ctx, inputPath); err != nil {
+		return err
+	}
 	if err != nil {
 		return errors.NewWithDetails(errors.ErrConversionFailed, "LibreOffice conversion failed", inputPath, string(output))
 	}
@@ -205,6 +252,29 @@ func (c *LibreOfficeConverter) ConvertTo(inputPath, outputPath, format string) e
 	return nil
 }
 
+// command returns the headless LibreOffice run converting inputPath to format in
+// outDir, with the user profile at profileURL. It is killed, with the processes it
+// started, once ctx is done; its output is waited for only briefly after that, in
+// case one of them still holds the pipe.
+func (c *LibreOfficeConverter) command(ctx context.Context, profileURL, outDir, format, inputPath string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, c.libreOfficePath,
+		"-env:UserInstallation="+profileURL,
+		"--headless",
+		"--invisible",
+		"--nologo",
+		"--nofirststartwizard",
+		"--convert-to", format,
+		"--outdir", outDir,
+		inputPath,
+	)
+	killGroupOnCancel(cmd)
+	cmd.WaitDelay = 2 * time.Second
+
+	// Set environment to avoid GUI issues
+	cmd.Env = append(os.Environ(), "HOME="+outDir)
+	return cmd
+}
+
 // copyFile is a helper to copy a file if rename fails
 func copyFile(src, dst string) error {
 	data, err := os.ReadFile(src)
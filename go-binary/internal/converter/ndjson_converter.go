@@ -0,0 +1,356 @@
+package converter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+)
+
+func init() {
+	Register(FormatNDJSON, func() Converter { return NewNDJSONConverter() })
+}
+
+// NDJSONConverter converts JSON lines (one object per line) to a table, streaming
+// the objects so memory stays flat however long the file is. The columns are the
+// keys of the first object, or Options.JSONFields.
+type NDJSONConverter struct {
+	*CSVConverter
+}
+
+// NewNDJSONConverter creates a new NDJSON converter
+func NewNDJSONConverter() *NDJSONConverter {
+	return &NDJSONConverter{CSVConverter: NewCSVConverter()}
+}
+
+// SupportedExtensions returns extensions handled by this converter
+func (c *NDJSONConverter) SupportedExtensions() []string {
+	return []string{".ndjson", ".jsonl"}
+}
+
+// Validate checks that the first non-blank line of the file is a JSON object
+func (c *NDJSONConverter) Validate(inputPath string) error {
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return errors.NewWithFile(errors.ErrFileNotFound, "Cannot open file", inputPath)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, 64*1024)
+	for {
+		line, err := readJSONLine(reader)
+		if len(line) > 0 {
+			if _, _, perr := parseJSONObject(line); perr != nil {
+				return errors.NewWithDetails(errors.ErrInvalidFormat, "Invalid NDJSON format", inputPath, perr.Error())
+			}
+			return nil
+		}
+		if err != nil {
+			return nil // Empty file; Convert decides what to draw
+		}
+	}
+}
+
+// Convert performs the NDJSON to PDF conversion
+func (c *NDJSONConverter) Convert(inputPath, outputPath string, opts pdf.Options) error {
+	return c.ConvertCtx(context.Background(), inputPath, outputPath, opts)
+}
+
+// ConvertCtx is Convert, stopping between objects once ctx is done
+func (c *NDJSONConverter) ConvertCtx(ctx context.Context, inputPath, outputPath string, opts pdf.Options) error {
+	if err := c.Validate(inputPath); err != nil {
+		return err
+	}
+
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return errors.NewWithFile(errors.ErrFileNotFound, "Cannot open input file", inputPath)
+	}
+	defer file.Close()
+
+	// The keys are always the single header row
+	opts.HeaderRow, opts.HeaderRows, opts.AutoDetectHeader = true, 1, false
+	fields := parseJSONFields(opts.JSONFields)
+
+	// First pass: sample objects for column widths and types. A transposed table
+	// needs every object, so the whole file is read instead.
+	sampleLimit := c.maxSampleRows
+	if opts.Transpose {
+		sampleLimit = math.MaxInt
+	}
+	objects := newNDJSONRowIterator(file, fields)
+	sampleRows := readSample(objects, sampleLimit)
+	if len(sampleRows) <= 1 {
+		if opts.EmptyDataMessage == "" {
+			return errors.NewWithFile(errors.ErrInvalidFormat, "NDJSON file is empty", inputPath)
+		}
+		if err := c.convertEmpty(inputPath, outputPath, opts); err != nil {
+			return err
+		}
+		c.warnings = []string{"NDJSON file has no objects"}
+		return nil
+	}
+	fields = sampleRows[0]
+	headers := fields
+	var transposed [][]string
+	if opts.Transpose {
+		opts = transposedOptions(opts)
+		transposed = transposeRows(sampleRows)
+		sampleRows, headers = transposed, nil
+		if len(sampleRows) > c.maxSampleRows {
+			sampleRows = sampleRows[:c.maxSampleRows]
+		}
+	}
+
+	colWidths, shouldSwitchToLandscape, err := c.calculateColumnWidths(sampleRows, opts)
+	if err != nil {
+		return measureFailed(inputPath, err)
+	}
+	if shouldSwitchToLandscape {
+		opts.Orientation = pdf.Landscape
+	}
+
+	builder, err := pdf.NewBuilder(opts)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create PDF builder")
+	}
+	defer builder.Close()
+
+	if c.onProgress != nil {
+		builder.SetProgressCallback(c.onProgress)
+	}
+	builder.SetColumnTypes(sampleColumnTypes(sampleRows, opts))
+
+	builder.BeginSection(filepath.Base(inputPath))
+	builder.AddPage()
+	if opts.TableCaption != "" {
+		builder.AddCaption(opts.TableCaption)
+	}
+
+	// Second pass: stream the objects into the table
+	var rows pdf.RowIterator = &sliceRowIterator{rows: transposed}
+	if !opts.Transpose {
+		file.Seek(0, 0)
+		objects = newNDJSONRowIterator(file, fields)
+		rows = objects
+	}
+	rowIterator, err := newRowFilter(rows, opts, sampleRows[0])
+	if err != nil {
+		return errors.Wrap(err, errors.ErrInvalidOption, "Invalid row filter")
+	}
+	rowIterator.ctx = ctx
+	if err := builder.DrawTableStreaming(headers, rowIterator, colWidths, opts.HeaderRow); err != nil {
+		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to draw table")
+	}
+	if err := rowIterator.Err(); err != nil {
+		return err
+	}
+	if rowIterator.DataRows() == 0 {
+		builder.AddEmptyMessage(opts.EmptyDataMessage)
+	}
+	c.warnings = append(objects.Warnings(), rowIterator.Warnings("")...)
+	stats := &Stats{SkippedRows: objects.badLines, AutoOrientation: shouldSwitchToLandscape}
+	stats.addTable(rowIterator, len(colWidths))
+
+	if err := builder.Save(outputPath); err != nil {
+		return errors.Wrap(err, errors.ErrWriteFailed, "Failed to save PDF")
+	}
+	c.layout = builder.Layout()
+	stats.Orientation = c.layout.Orientation
+	c.stats = stats
+
+	return nil
+}
+
+// parseJSONFields splits Options.JSONFields ("id, name, total") into keys
+func parseJSONFields(s string) []string {
+	var fields []string
+	for _, field := range strings.Split(s, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// ndjsonRowIterator yields the header row (the column keys) and then one row per
+// object. Without preset fields, the keys of the first object are the columns.
+// Keys missing from an object give empty cells; keys not among the columns are
+// dropped and reported by Warnings. Lines that aren't JSON objects are skipped.
+type ndjsonRowIterator struct {
+	reader  *bufio.Reader
+	fields  []string
+	index   map[string]int // Column of each field
+	first   []string       // Row of the first object, yielded after the header row
+	current []string
+	started bool
+	done    bool
+
+	dropped     map[string]struct{} // Keys dropped for not being columns
+	driftedRows int
+	badLines    int
+}
+
+func newNDJSONRowIterator(r io.Reader, fields []string) *ndjsonRowIterator {
+	return &ndjsonRowIterator{reader: bufio.NewReaderSize(r, 64*1024), fields: fields}
+}
+
+func (it *ndjsonRowIterator) Next() bool {
+	if it.first != nil {
+		it.current, it.first = it.first, nil
+		return true
+	}
+	for !it.done {
+		line, err := readJSONLine(it.reader)
+		if err != nil {
+			it.done = true
+		}
+		if len(line) == 0 {
+			continue
+		}
+		keys, values, perr := parseJSONObject(line)
+		if perr != nil {
+			it.badLines++
+			continue
+		}
+
+		if !it.started {
+			it.started = true
+			if len(it.fields) == 0 {
+				it.fields = keys
+			}
+			it.index = make(map[string]int, len(it.fields))
+			for i, field := range it.fields {
+				it.index[field] = i
+			}
+			it.current, it.first = it.fields, it.row(keys, values)
+			return true
+		}
+		it.current = it.row(keys, values)
+		return true
+	}
+	return false
+}
+
+func (it *ndjsonRowIterator) Columns() ([]string, error) {
+	return it.current, nil
+}
+
+// row lays out an object's values in column order
+func (it *ndjsonRowIterator) row(keys []string, values map[string]string) []string {
+	row := make([]string, len(it.fields))
+	drifted := false
+	for _, key := range keys {
+		col, ok := it.index[key]
+		if !ok {
+			if it.dropped == nil {
+				it.dropped = make(map[string]struct{})
+			}
+			it.dropped[key] = struct{}{}
+			drifted = true
+			continue
+		}
+		row[col] = values[key]
+	}
+	if drifted {
+		it.driftedRows++
+	}
+	return row
+}
+
+// maxDroppedKeysListed caps the keys named in the schema drift warning
+const maxDroppedKeysListed = 5
+
+// Warnings reports skipped lines and dropped keys
+func (it *ndjsonRowIterator) Warnings() []string {
+	var warnings []string
+	if it.badLines > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d lines are not JSON objects and were skipped", it.badLines))
+	}
+	if it.driftedRows > 0 {
+		keys := make([]string, 0, len(it.dropped))
+		for key := range it.dropped {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		if len(keys) > maxDroppedKeysListed {
+			keys = append(keys[:maxDroppedKeysListed], "...")
+		}
+		warnings = append(warnings, fmt.Sprintf("%d objects have keys that are not columns (%s); their values were dropped",
+			it.driftedRows, strings.Join(keys, ", ")))
+	}
+	return warnings
+}
+
+// readJSONLine reads the next line without surrounding whitespace or a UTF-8 BOM.
+// At the end of the input it returns the last line, possibly empty, with io.EOF.
+func readJSONLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadBytes('\n')
+	line = bytes.TrimPrefix(bytes.TrimSpace(line), []byte("\xef\xbb\xbf"))
+	return line, err
+}
+
+// parseJSONObject parses one JSON object into its keys, in file order, and their
+// values as cell text: strings as is, numbers as written, true/false, null as an
+// empty cell and nested arrays and objects as compact JSON
+func parseJSONObject(data []byte) ([]string, map[string]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil, fmt.Errorf("not a JSON object")
+	}
+
+	var keys []string
+	values := make(map[string]string)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key, _ := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, nil, err
+		}
+		if _, seen := values[key]; !seen {
+			keys = append(keys, key)
+		}
+		values[key] = jsonCellText(raw)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, err
+	}
+	if dec.More() {
+		return nil, nil, fmt.Errorf("unexpected data after the object")
+	}
+	return keys, values, nil
+}
+
+// jsonCellText returns the cell text of a JSON value
+func jsonCellText(raw json.RawMessage) string {
+	switch {
+	case len(raw) == 0 || string(raw) == "null":
+		return ""
+	case raw[0] == '"':
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			return s
+		}
+	case raw[0] == '{' || raw[0] == '[':
+		var buf bytes.Buffer
+		if json.Compact(&buf, raw) == nil {
+			return buf.String()
+		}
+	}
+	return string(raw)
+}
@@ -0,0 +1,450 @@
+package converter
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+func init() {
+	Register(FormatODS, func() Converter { return NewODSConverter() })
+}
+
+// odsContentSizeLimit caps the uncompressed content.xml, like the unzip limit used for XLSX
+const odsContentSizeLimit = 100 << 20
+
+// ODSConverter converts OpenDocument spreadsheets natively: the sheets are read
+// from content.xml and drawn the same way as Excel sheets
+type ODSConverter struct {
+	excel *ExcelConverter
+}
+
+// NewODSConverter creates a new ODS converter
+func NewODSConverter() *ODSConverter {
+	return &ODSConverter{excel: NewExcelConverter()}
+}
+
+// SetProgressCallback sets the callback for progress reporting
+func (c *ODSConverter) SetProgressCallback(callback func(int)) {
+	c.excel.SetProgressCallback(callback)
+}
+
+// Layout returns the page structure of the last PDF written by Convert
+func (c *ODSConverter) Layout() pdf.Layout {
+	return c.excel.Layout()
+}
+
+// Warnings returns non-fatal notes from the last Convert (e.g. dropped rows)
+func (c *ODSConverter) Warnings() []string {
+	return c.excel.Warnings()
+}
+
+// Stats returns the statistics of the last Convert
+func (c *ODSConverter) Stats() *Stats {
+	return c.excel.Stats()
+}
+
+// SupportedExtensions returns extensions handled by this converter
+func (c *ODSConverter) SupportedExtensions() []string {
+	return []string{".ods"}
+}
+
+// Validate checks that the input is an ODF package with a content.xml
+func (c *ODSConverter) Validate(inputPath string) error {
+	if err := validateExists(inputPath); err != nil {
+		return err
+	}
+
+	zr, err := zip.OpenReader(inputPath)
+	if err != nil {
+		if hasSignature(inputPath, zipSignature) {
+			return errors.NewWithDetails(errors.ErrCorruptFile, "ODS file is damaged", inputPath, err.Error())
+		}
+		return errors.NewWithDetails(errors.ErrInvalidFormat, "Invalid ODS format", inputPath, err.Error())
+	}
+	defer zr.Close()
+
+	if odsContent(&zr.Reader) == nil {
+		return errors.NewWithDetails(errors.ErrCorruptFile, "ODS file is damaged", inputPath, "content.xml is missing")
+	}
+	return nil
+}
+
+// Convert performs the ODS to PDF conversion
+func (c *ODSConverter) Convert(inputPath, outputPath string, opts pdf.Options) error {
+	return c.ConvertCtx(context.Background(), inputPath, outputPath, opts)
+}
+
+// ConvertCtx is Convert, stopping between sheets and rows once ctx is done
+func (c *ODSConverter) ConvertCtx(ctx context.Context, inputPath, outputPath string, opts pdf.Options) error {
+	if err := c.Validate(inputPath); err != nil {
+		return err
+	}
+
+	// Transposing needs whole sheets
+	if opts.Transpose {
+		sheets, err := readODSSheets(inputPath, opts.Locale)
+		if err != nil {
+			return odsDamaged(inputPath, err)
+		}
+		return c.excel.convertSheetRows(ctx, nil, sheets, outputPath, opts)
+	}
+
+	tables, err := odsTables(inputPath)
+	if err != nil {
+		return odsDamaged(inputPath, err)
+	}
+	sheets := make([]sheetRows, len(tables))
+	for i, table := range tables {
+		table := table
+		sheets[i] = sheetRows{name: table.name, open: func() (rowStream, error) {
+			rows, err := openODSTable(inputPath, table, opts.Locale)
+			if err != nil {
+				return nil, odsDamaged(inputPath, err)
+			}
+			return rows, nil
+		}}
+	}
+	return c.excel.convertSheetRows(ctx, nil, sheets, outputPath, opts)
+}
+
+// odsDamaged is the error for an ODS file whose content.xml can't be read
+func odsDamaged(path string, err error) error {
+	return errors.NewWithDetails(errors.ErrCorruptFile, "ODS file is damaged", path, err.Error())
+}
+
+// odsContent returns the content.xml entry of an ODF package
+func odsContent(zr *zip.Reader) *zip.File {
+	for _, f := range zr.File {
+		if f.Name == "content.xml" {
+			return f
+		}
+	}
+	return nil
+}
+
+// odsContentReader reads the content.xml of an ODS file
+type odsContentReader struct {
+	io.Reader
+	zr *zip.ReadCloser
+	rc io.ReadCloser
+}
+
+// openODSContent opens the content.xml of an ODS file
+func openODSContent(path string) (*odsContentReader, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	content := odsContent(&zr.Reader)
+	if content == nil {
+		zr.Close()
+		return nil, fmt.Errorf("content.xml is missing")
+	}
+	if content.UncompressedSize64 > odsContentSizeLimit {
+		zr.Close()
+		return nil, fmt.Errorf("content.xml exceeds %d bytes", odsContentSizeLimit)
+	}
+	rc, err := content.Open()
+	if err != nil {
+		zr.Close()
+		return nil, err
+	}
+	return &odsContentReader{Reader: io.LimitReader(rc, odsContentSizeLimit), zr: zr, rc: rc}, nil
+}
+
+func (r *odsContentReader) Close() error {
+	r.rc.Close()
+	return r.zr.Close()
+}
+
+// odsTable is a sheet of an ODS file: its name and the offset of its table element
+// in content.xml, where its rows are read from
+type odsTable struct {
+	name   string
+	offset int64
+}
+
+// odsTables lists the sheets of an ODS file
+func odsTables(path string) ([]odsTable, error) {
+	content, err := openODSContent(path)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	var tables []odsTable
+	d := xml.NewDecoder(content)
+	for {
+		offset := d.InputOffset()
+		tok, err := d.Token()
+		if err == io.EOF {
+			return tables, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := tok.(xml.StartElement); ok && t.Name.Local == "table" {
+			tables = append(tables, odsTable{name: odsAttr(t, "name"), offset: offset})
+			if err := d.Skip(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// openODSTable streams the rows of a sheet of an ODS file. content.xml is
+// decompressed up to the sheet's table element without being parsed.
+func openODSTable(path string, table odsTable, locale string) (*odsRowIterator, error) {
+	content, err := openODSContent(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.CopyN(io.Discard, content, table.offset); err != nil {
+		content.Close()
+		return nil, err
+	}
+	// The decoder starts at the table element, outside the namespace declarations
+	// of the document; cells are matched by local name only
+	d := xml.NewDecoder(content)
+	if tok, err := d.Token(); err != nil || !isODSTable(tok) {
+		content.Close()
+		if err == nil {
+			err = fmt.Errorf("sheet %q not found in content.xml", table.name)
+		}
+		return nil, err
+	}
+	return &odsRowIterator{d: d, content: content, locale: locale}, nil
+}
+
+func isODSTable(tok xml.Token) bool {
+	t, ok := tok.(xml.StartElement)
+	return ok && t.Name.Local == "table"
+}
+
+// readODSSheets reads the text of every sheet in an ODS file into memory
+func readODSSheets(path, locale string) ([]sheetRows, error) {
+	tables, err := odsTables(path)
+	if err != nil {
+		return nil, err
+	}
+	var sheets []sheetRows
+	for _, table := range tables {
+		rows, err := openODSTable(path, table, locale)
+		if err != nil {
+			return nil, err
+		}
+		sheet := sheetRows{name: table.name, rows: readSample(rows, math.MaxInt)}
+		rows.Close()
+		if rows.err != nil {
+			return nil, rows.err
+		}
+		sheets = append(sheets, sheet)
+	}
+	return sheets, nil
+}
+
+// odsRowIterator streams the rows of one sheet from content.xml. Cells hold their
+// displayed text, so numbers and dates keep the document's formatting. Booleans
+// use the locale's TRUE/FALSE labels when a locale is set.
+//
+// ODF compresses runs of identical rows and cells with number-rows-repeated /
+// number-columns-repeated; empty runs are only expanded when something follows
+// them, as sheets commonly end with a run covering the rest of the grid.
+type odsRowIterator struct {
+	d       *xml.Decoder
+	content *odsContentReader
+	locale  string
+	err     error
+	done    bool // The table element has ended
+
+	current    []string
+	rows       int // Rows yielded, at most excelize.TotalRows
+	emptyRows  int // Empty rows read, yielded once a row with text follows them
+	emptyLeft  int // Empty rows still to yield before row
+	row        []string
+	rowLeft    int // Times row is still to be yielded
+	rowRepeat  int
+	emptyCells int // Empty cells not yet added to row
+}
+
+func (it *odsRowIterator) Next() bool {
+	for it.err == nil && it.rows < excelize.TotalRows {
+		switch {
+		case it.emptyLeft > 0:
+			it.emptyLeft--
+			it.current = nil
+		case it.rowLeft > 0:
+			it.rowLeft--
+			it.current = it.row
+		case it.done:
+			return false
+		default:
+			it.err = it.readRow()
+			continue
+		}
+		it.rows++
+		return true
+	}
+	return false
+}
+
+func (it *odsRowIterator) Columns() ([]string, error) {
+	return it.current, nil
+}
+
+// Err returns the error that ended the rows early, if any
+func (it *odsRowIterator) Err() error {
+	return it.err
+}
+
+// Close closes the ODS file
+func (it *odsRowIterator) Close() error {
+	return it.content.Close()
+}
+
+// readRow reads up to the end of the next row with text, or of the table
+func (it *odsRowIterator) readRow() error {
+	for {
+		tok, err := it.d.Token()
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "table-row":
+				it.row, it.emptyCells = nil, 0
+				it.rowRepeat = odsRepeat(t, "number-rows-repeated", excelize.TotalRows)
+			case "table-cell", "covered-table-cell":
+				text, err := it.cellText(t)
+				if err != nil {
+					return err
+				}
+				it.addCell(text, odsRepeat(t, "number-columns-repeated", excelize.MaxColumns))
+			}
+
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "table":
+				it.done = true
+				return nil
+			case "table-row":
+				if len(it.row) == 0 {
+					it.emptyRows += it.rowRepeat
+					continue
+				}
+				it.emptyLeft, it.emptyRows = it.emptyRows, 0
+				it.rowLeft = it.rowRepeat
+				return nil
+			}
+		}
+	}
+}
+
+// addCell appends a cell repeated n times to the current row
+func (it *odsRowIterator) addCell(text string, n int) {
+	if text == "" {
+		it.emptyCells += n
+		return
+	}
+	for ; it.emptyCells > 0 && len(it.row) < excelize.MaxColumns; it.emptyCells-- {
+		it.row = append(it.row, "")
+	}
+	it.emptyCells = 0
+	for i := 0; i < n && len(it.row) < excelize.MaxColumns; i++ {
+		it.row = append(it.row, text)
+	}
+}
+
+// cellText returns the displayed text of a cell and consumes the element. Paragraphs
+// are joined with newlines; comments (office:annotation) and sub-tables are left out.
+func (it *odsRowIterator) cellText(cell xml.StartElement) (string, error) {
+	d := it.d
+	if odsAttr(cell, "value-type") == "boolean" && it.locale != "" {
+		trueLabel, falseLabel := pdf.BooleanLabels(it.locale)
+		if err := d.Skip(); err != nil {
+			return "", err
+		}
+		if odsAttr(cell, "boolean-value") == "true" {
+			return trueLabel, nil
+		}
+		return falseLabel, nil
+	}
+
+	var text strings.Builder
+	paragraphs, depth := 0, 0 // depth > 0 inside a paragraph
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "annotation", "table":
+				if err := d.Skip(); err != nil {
+					return "", err
+				}
+			case "p", "h":
+				if depth == 0 && paragraphs > 0 {
+					text.WriteByte('\n')
+				}
+				paragraphs++
+				depth++
+			case "s":
+				text.WriteString(strings.Repeat(" ", odsRepeat(t, "c", 1024)))
+			case "tab":
+				text.WriteByte('\t')
+			case "line-break":
+				text.WriteByte('\n')
+			}
+		case xml.CharData:
+			if depth > 0 {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			switch {
+			case t.Name == cell.Name:
+				return text.String(), nil
+			case t.Name.Local == "p" || t.Name.Local == "h":
+				depth--
+			}
+		}
+	}
+}
+
+// odsAttr returns the value of the attribute with the given local name
+func odsAttr(el xml.StartElement, name string) string {
+	for _, attr := range el.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// odsRepeat returns a repeat count attribute, 1 when absent and at most max
+func odsRepeat(el xml.StartElement, name string, max int) int {
+	n, err := strconv.Atoi(odsAttr(el, name))
+	if err != nil || n < 1 {
+		return 1
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
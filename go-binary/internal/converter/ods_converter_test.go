@@ -0,0 +1,178 @@
+package converter
+
+import (
+	"archive/zip"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+)
+
+// odsCell is a string cell holding text
+func odsCell(text string) string {
+	return `<table:table-cell office:value-type="string"><text:p>` + text + `</text:p></table:table-cell>`
+}
+
+// odsRow is a row of string cells
+func odsRow(cells ...string) string {
+	var row strings.Builder
+	row.WriteString("<table:table-row>")
+	for _, cell := range cells {
+		row.WriteString(odsCell(cell))
+	}
+	row.WriteString("</table:table-row>")
+	return row.String()
+}
+
+// writeODSFile writes an ODS file to dir with one sheet per entry of tables, a
+// sheet name and the XML of its rows
+func writeODSFile(t *testing.T, dir string, tables ...[2]string) string {
+	t.Helper()
+	var body strings.Builder
+	for _, table := range tables {
+		body.WriteString(`<table:table table:name="` + table[0] + `">` + table[1] + `</table:table>`)
+	}
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" office:version="1.2"><office:body><office:spreadsheet>` +
+		body.String() + `</office:spreadsheet></office:body></office:document-content>`
+
+	path := filepath.Join(dir, "book.ods")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	zw := zip.NewWriter(file)
+	for _, part := range [][2]string{{"mimetype", "application/vnd.oasis.opendocument.spreadsheet"}, {"content.xml", content}} {
+		w, err := zw.Create(part[0])
+		if err == nil {
+			_, err = w.Write([]byte(part[1]))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestODSRows(t *testing.T) {
+	tests := []struct {
+		name string
+		rows string
+		want [][]string
+	}{
+		{
+			"repeated rows",
+			`<table:table-row table:number-rows-repeated="3">` + odsCell("a") + odsCell("b") + `</table:table-row>`,
+			[][]string{{"a", "b"}, {"a", "b"}, {"a", "b"}},
+		},
+		{
+			"repeated columns",
+			`<table:table-row><table:table-cell table:number-columns-repeated="3" office:value-type="string"><text:p>x</text:p></table:table-cell></table:table-row>`,
+			[][]string{{"x", "x", "x"}},
+		},
+		{
+			"empty cells between and after text",
+			`<table:table-row>` + odsCell("a") + `<table:table-cell table:number-columns-repeated="2"/>` + odsCell("b") + `<table:table-cell table:number-columns-repeated="16000"/></table:table-row>`,
+			[][]string{{"a", "", "", "b"}},
+		},
+		{
+			"covered cells",
+			`<table:table-row><table:table-cell table:number-columns-spanned="2" office:value-type="string"><text:p>Merged</text:p></table:table-cell><table:covered-table-cell/>` + odsCell("c") + `</table:table-row>` +
+				`<table:table-row><table:covered-table-cell table:number-columns-repeated="2"/>` + odsCell("d") + `</table:table-row>`,
+			[][]string{{"Merged", "", "c"}, {"", "", "d"}},
+		},
+		{
+			"empty rows between and after text",
+			odsRow("a") + `<table:table-row table:number-rows-repeated="2"><table:table-cell/></table:table-row>` + odsRow("b") +
+				`<table:table-row table:number-rows-repeated="1048000"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>`,
+			[][]string{{"a"}, nil, nil, {"b"}},
+		},
+		{
+			"paragraphs, spaces and comments",
+			`<table:table-row><table:table-cell office:value-type="string"><office:annotation><text:p>note</text:p></office:annotation><text:p>a<text:s text:c="2"/>b</text:p><text:p>c</text:p></table:table-cell></table:table-row>`,
+			[][]string{{"a  b\nc"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeODSFile(t, t.TempDir(), [2]string{"Sheet1", tt.rows})
+			sheets, err := readODSSheets(path, "")
+			if err != nil {
+				t.Fatalf("readODSSheets: %v", err)
+			}
+			if len(sheets) != 1 {
+				t.Fatalf("read %d sheets, want 1", len(sheets))
+			}
+			if !reflect.DeepEqual(sheets[0].rows, tt.want) {
+				t.Errorf("rows %q, want %q", sheets[0].rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestODSSheetsAreStreamedInTurn(t *testing.T) {
+	path := writeODSFile(t, t.TempDir(),
+		[2]string{"First", odsRow("Name", "Amount") + odsRow("Ada", "1")},
+		[2]string{"Second", odsRow("City") + odsRow("Oslo") + odsRow("Rome")},
+	)
+	tables, err := odsTables(path)
+	if err != nil {
+		t.Fatalf("odsTables: %v", err)
+	}
+	if len(tables) != 2 || tables[0].name != "First" || tables[1].name != "Second" {
+		t.Fatalf("tables %+v, want First and Second", tables)
+	}
+
+	// The second sheet is read on its own, from its offset
+	rows, err := openODSTable(path, tables[1], "")
+	if err != nil {
+		t.Fatalf("openODSTable: %v", err)
+	}
+	defer rows.Close()
+	if got := readSample(rows, 100); !reflect.DeepEqual(got, [][]string{{"City"}, {"Oslo"}, {"Rome"}}) || rows.Err() != nil {
+		t.Errorf("second sheet rows %q (%v), want its three rows", got, rows.Err())
+	}
+
+	c := NewODSConverter()
+	if err := c.Convert(path, filepath.Join(t.TempDir(), "book.pdf"), pdf.DefaultOptions()); err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	if stats := c.Stats(); stats.Sheets != 2 || stats.Rows != 3 {
+		t.Errorf("stats %+v, want 2 sheets and 3 data rows", stats)
+	}
+}
+
+func TestODSStreamingMaxMemoryBytes(t *testing.T) {
+	var rows strings.Builder
+	rows.WriteString(odsRow("Name", "Note"))
+	for i := 0; i < 500; i++ {
+		rows.WriteString(odsRow("Ada", strings.Repeat("x", 100)))
+	}
+	path := writeODSFile(t, t.TempDir(), [2]string{"Sheet1", rows.String()})
+
+	opts := pdf.DefaultOptions()
+	opts.MaxMemoryBytes = 10000
+	err := NewODSConverter().Convert(path, filepath.Join(t.TempDir(), "book.pdf"), opts)
+	var convErr *errors.ConversionError
+	if !stderrors.As(err, &convErr) || convErr.Code != errors.ErrMemoryLimit {
+		t.Errorf("Convert = %v, want the memory limit error", err)
+	}
+}
+
+func TestODSDamagedContent(t *testing.T) {
+	path := writeODSFile(t, t.TempDir(), [2]string{"Sheet1", odsRow("Name") + "<table:table-row>" + odsCell("Ada")})
+	err := NewODSConverter().Convert(path, filepath.Join(t.TempDir(), "book.pdf"), pdf.DefaultOptions())
+	var convErr *errors.ConversionError
+	if !stderrors.As(err, &convErr) || convErr.Code != errors.ErrCorruptFile {
+		t.Errorf("Convert = %v, want the damaged file error", err)
+	}
+}
@@ -0,0 +1,59 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+)
+
+// fitOutputSize enforces Options.MaxOutputBytes on the output conv just wrote to
+// outputPath. While a file is over the limit, the input is converted again at the
+// next lower Options.Quality, which downsamples and recompresses images. At the
+// lowest level the last output is kept with a warning. The returned converter
+// reports the quality of the output that was kept.
+func fitOutputSize(ctx context.Context, conv Converter, format FormatType, sourcePath, outputPath string, opts pdf.Options, cfg RunConfig, pages pageRange) (Converter, error) {
+	quality := opts.Quality
+	var warnings []string
+	for {
+		size := outputSize(conv, outputPath)
+		if size <= opts.MaxOutputBytes {
+			break
+		}
+		next := pdf.LowerQuality(quality)
+		if next == "" {
+			warnings = append(warnings, fmt.Sprintf("Output is %d bytes, over the %d-byte limit even at quality %q",
+				size, opts.MaxOutputBytes, quality))
+			break
+		}
+
+		retry, err := GetConverter(format)
+		if err != nil {
+			return conv, err
+		}
+		// Progress already reached 100% once; a retry doesn't report it again
+		Configure(retry, cfg.LibreOfficePath, cfg.Native, nil)
+		quality, opts.Quality = next, next
+		if conv, err = render(ctx, retry, sourcePath, outputPath, opts, pages); err != nil {
+			return conv, err
+		}
+	}
+	return &adjustedConverter{Converter: conv, quality: quality, warnings: warnings}, nil
+}
+
+// outputSize returns the size of the largest file of a conversion's output: the
+// output file, or each part of a split output
+func outputSize(conv Converter, outputPath string) int64 {
+	files := []string{outputPath}
+	if parts := LayoutOf(conv).OutputFiles; len(parts) > 1 {
+		files = parts
+	}
+	var largest int64
+	for _, path := range files {
+		if info, err := os.Stat(path); err == nil && info.Size() > largest {
+			largest = info.Size()
+		}
+	}
+	return largest
+}
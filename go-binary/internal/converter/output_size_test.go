@@ -0,0 +1,99 @@
+package converter
+
+import (
+	"image"
+	"image/png"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+)
+
+// writeNoisePNG writes a w x h PNG of random opaque pixels, which downsampling
+// and JPEG recompression shrink at every lower quality level
+func writeNoisePNG(t *testing.T, path string, w, h int) {
+	t.Helper()
+	rng := rand.New(rand.NewSource(1))
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(rng.Intn(256))
+		if i%4 == 3 {
+			img.Pix[i] = 255
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestFitOutputSize converts an image under size limits between the sizes it
+// has at each quality level: the first level whose output fits is kept, and at
+// the lowest level an output still over the limit is kept with a warning
+func TestFitOutputSize(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "photo.png")
+	writeNoisePNG(t, input, 1200, 900)
+	output := filepath.Join(dir, "photo.pdf")
+
+	sizes := make(map[string]int64)
+	for _, quality := range []string{pdf.QualityBalanced, pdf.QualityCompact, pdf.QualitySmall, pdf.QualityMinimum} {
+		opts := pdf.DefaultOptions()
+		opts.Quality = quality
+		if _, err := Convert(input, output, "", opts, RunConfig{Native: true}); err != nil {
+			t.Fatalf("Convert at %s: %v", quality, err)
+		}
+		info, err := os.Stat(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sizes[quality] = info.Size()
+	}
+	if !(sizes[pdf.QualityBalanced] > sizes[pdf.QualityCompact] && sizes[pdf.QualityCompact] > sizes[pdf.QualitySmall] && sizes[pdf.QualitySmall] > sizes[pdf.QualityMinimum]) {
+		t.Fatalf("sizes %v don't shrink at each lower quality", sizes)
+	}
+
+	tests := []struct {
+		name  string
+		limit int64
+		want  string
+		warn  bool
+	}{
+		{"fits as it is", sizes[pdf.QualityBalanced], pdf.QualityBalanced, false},
+		{"fits at compact", sizes[pdf.QualityCompact], pdf.QualityCompact, false},
+		{"fits at small", sizes[pdf.QualitySmall], pdf.QualitySmall, false},
+		{"fits at minimum", sizes[pdf.QualityMinimum], pdf.QualityMinimum, false},
+		{"never fits", sizes[pdf.QualityMinimum] - 1, pdf.QualityMinimum, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := pdf.DefaultOptions()
+			opts.MaxOutputBytes = tt.limit
+			conv, err := Convert(input, output, "", opts, RunConfig{Native: true})
+			if err != nil {
+				t.Fatalf("Convert: %v", err)
+			}
+			if got := QualityOf(conv); got != tt.want {
+				t.Errorf("kept the output at quality %q, want %q", got, tt.want)
+			}
+			info, err := os.Stat(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Size() != sizes[tt.want] {
+				t.Errorf("output is %d bytes, want the %d of quality %s", info.Size(), sizes[tt.want], tt.want)
+			}
+			warned := strings.Contains(strings.Join(WarningsOf(conv), "\n"), "over the")
+			if warned != tt.warn {
+				t.Errorf("warnings %q, want a size warning: %v", WarningsOf(conv), tt.warn)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package converter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+)
+
+// pageRange is a set of pages or slides, from Options.PageRange or
+// Options.SlideRange, as inclusive 1-based spans. A span with last 0 runs to the
+// end of the document. A nil pageRange selects everything.
+type pageRange []struct{ first, last int }
+
+// parsePageRange parses comma-separated numbers and spans such as "2-5,8" or
+// "10-" (10 to the end). An empty string returns nil.
+func parsePageRange(s string) (pageRange, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	var r pageRange
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		from, to, isSpan := strings.Cut(part, "-")
+		first, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil || first < 1 {
+			return nil, fmt.Errorf("%q is not a range like 2-5,8", s)
+		}
+		last := first
+		if isSpan {
+			if to = strings.TrimSpace(to); to == "" {
+				last = 0
+			} else if last, err = strconv.Atoi(to); err != nil || last < first {
+				return nil, fmt.Errorf("%q is not a range like 2-5,8", s)
+			}
+		}
+		r = append(r, struct{ first, last int }{first, last})
+	}
+	return r, nil
+}
+
+// slideRangeOption parses Options.SlideRange, reporting a malformed range as INVALID_FORMAT
+func slideRangeOption(opts pdf.Options) (pageRange, error) {
+	r, err := parsePageRange(opts.SlideRange)
+	if err != nil {
+		return nil, errors.NewWithDetails(errors.ErrInvalidFormat, "Invalid slide range", "", err.Error())
+	}
+	return r, nil
+}
+
+// pageRangeOption parses Options.PageRange, reporting a malformed range as INVALID_FORMAT
+func pageRangeOption(opts pdf.Options) (pageRange, error) {
+	r, err := parsePageRange(opts.PageRange)
+	if err != nil {
+		return nil, errors.NewWithDetails(errors.ErrInvalidFormat, "Invalid page range", "", err.Error())
+	}
+	return r, nil
+}
+
+// contains reports whether n, 1-based, is selected
+func (r pageRange) contains(n int) bool {
+	if r == nil {
+		return true
+	}
+	for _, span := range r {
+		if n >= span.first && (span.last == 0 || n <= span.last) {
+			return true
+		}
+	}
+	return false
+}
+
+// pages returns the selected numbers of a document with count pages, in order,
+// or an error naming the first span that goes past its end
+func (r pageRange) pages(count int) ([]int, error) {
+	for _, span := range r {
+		if span.first > count || span.last > count {
+			return nil, fmt.Errorf("%s is outside the %d-page document", pageRange{span}, count)
+		}
+	}
+	var pages []int
+	for n := 1; n <= count; n++ {
+		if r.contains(n) {
+			pages = append(pages, n)
+		}
+	}
+	return pages, nil
+}
+
+// String returns the range in the syntax of LibreOffice's PageRange export option
+func (r pageRange) String() string {
+	parts := make([]string, len(r))
+	for i, span := range r {
+		switch span.last {
+		case span.first:
+			parts[i] = strconv.Itoa(span.first)
+		case 0:
+			parts[i] = fmt.Sprintf("%d-", span.first)
+		default:
+			parts[i] = fmt.Sprintf("%d-%d", span.first, span.last)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// noSlidesSelected reports a range that selects none of a deck's slides
+func noSlidesSelected(opts pdf.Options, count int) error {
+	return errors.NewWithDetails(errors.ErrInvalidFormat, "Invalid slide range", "",
+		fmt.Sprintf("%q selects no slide of the %d-slide deck", strings.TrimSpace(opts.SlideRange), count))
+}
+
+// trimPages keeps only the pages of the PDF at outputPath selected by r, which must
+// all exist, and returns conv with its layout renumbered to match: sections keep
+// the pages they had that were kept, and sections with none are dropped.
+func trimPages(conv Converter, outputPath string, r pageRange) (Converter, error) {
+	count, err := pdf.PageCount(outputPath)
+	if err != nil {
+		return conv, errors.NewWithDetails(errors.ErrWriteFailed, "Failed to read PDF for page range", outputPath, err.Error())
+	}
+	pages, err := r.pages(count)
+	if err != nil {
+		return conv, errors.NewWithDetails(errors.ErrInvalidFormat, "Invalid page range", outputPath, err.Error())
+	}
+	if len(pages) < count {
+		if err := pdf.SelectPages(outputPath, pages); err != nil {
+			return conv, errors.NewWithDetails(errors.ErrWriteFailed, "Failed to apply page range", outputPath, err.Error())
+		}
+	}
+
+	layout := LayoutOf(conv)
+	layout.PageCount = len(pages)
+	var sections []pdf.Section
+	for _, section := range layout.Sections {
+		kept := pdf.Section{Name: section.Name}
+		for i, page := range pages {
+			if page >= section.StartPage && page <= section.EndPage {
+				if kept.StartPage == 0 {
+					kept.StartPage = i + 1
+				}
+				kept.EndPage = i + 1
+			}
+		}
+		if kept.StartPage > 0 {
+			sections = append(sections, kept)
+		}
+	}
+	layout.Sections = sections
+	return &adjustedConverter{Converter: conv, layout: &layout}, nil
+}
@@ -2,6 +2,7 @@ package converter
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -17,7 +18,9 @@ import (
 // PPTConverter handles legacy PowerPoint (.ppt) to PDF conversion
 // Uses OLE compound document parsing for text extraction
 type PPTConverter struct {
-	opts pdf.Options
+	opts   pdf.Options
+	layout pdf.Layout
+	stats  *Stats
 }
 
 // NewPPTConverter creates a new PPT converter
@@ -27,6 +30,16 @@ func NewPPTConverter() *PPTConverter {
 	}
 }
 
+// Layout returns the page structure of the last PDF written by Convert
+func (c *PPTConverter) Layout() pdf.Layout {
+	return c.layout
+}
+
+// Stats returns the statistics of the last Convert, or nil if it didn't render natively
+func (c *PPTConverter) Stats() *Stats {
+	return c.stats
+}
+
 // SupportedExtensions returns extensions handled by this converter
 func (c *PPTConverter) SupportedExtensions() []string {
 	return []string{".ppt"}
@@ -48,14 +61,24 @@ func (c *PPTConverter) Validate(inputPath string) error {
 
 	doc, err := mscfb.New(file)
 	if err != nil {
+		// OLE header present but the directory can't be parsed
+		if hasSignature(inputPath, oleSignature) {
+			return errors.NewWithDetails(errors.ErrCorruptFile, "PPT file is damaged (broken OLE structure)", inputPath, err.Error())
+		}
 		return errors.NewWithDetails(errors.ErrInvalidFormat, "Not a valid PPT file (invalid OLE format)", inputPath, err.Error())
 	}
 
-	// Check for PowerPoint Document stream
+	// Check for PowerPoint Document stream and make sure it can be read
 	hasPPTStream := false
 	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
-		if entry.Name == "PowerPoint Document" || entry.Name == "Current User" {
+		if entry.Name == "Current User" {
 			hasPPTStream = true
+		}
+		if entry.Name == "PowerPoint Document" {
+			hasPPTStream = true
+			if _, err := io.Copy(io.Discard, entry); err != nil {
+				return errors.NewWithDetails(errors.ErrCorruptFile, "PPT file is damaged (unreadable PowerPoint Document stream)", inputPath, err.Error())
+			}
 			break
 		}
 	}
@@ -69,10 +92,19 @@ func (c *PPTConverter) Validate(inputPath string) error {
 
 // Convert performs the PPT to PDF conversion
 func (c *PPTConverter) Convert(inputPath, outputPath string, opts pdf.Options) error {
+	return c.ConvertCtx(context.Background(), inputPath, outputPath, opts)
+}
+
+// ConvertCtx is Convert, stopping between slides once ctx is done
+func (c *PPTConverter) ConvertCtx(ctx context.Context, inputPath, outputPath string, opts pdf.Options) error {
 	// Validate input
 	if err := c.Validate(inputPath); err != nil {
 		return err
 	}
+	rng, err := slideRangeOption(opts)
+	if err != nil {
+		return err
+	}
 
 	// Open file
 	file, err := os.Open(inputPath)
@@ -92,6 +124,18 @@ func (c *PPTConverter) Convert(inputPath, outputPath string, opts pdf.Options) e
 	if err != nil {
 		return err
 	}
+	if rng != nil {
+		count := len(slides)
+		selected := slides[:0]
+		for _, slide := range slides {
+			if rng.contains(slide.Index) {
+				selected = append(selected, slide)
+			}
+		}
+		if slides = selected; len(slides) == 0 && count > 0 {
+			return noSlidesSelected(opts, count)
+		}
+	}
 
 	// For PowerPoint, use only general options (page size, margins, watermark, header/footer)
 	// Ignore table-specific customization options (they only apply to spreadsheets)
@@ -102,14 +146,19 @@ func (c *PPTConverter) Convert(inputPath, outputPath string, opts pdf.Options) e
 	if err != nil {
 		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create PDF builder")
 	}
+	defer builder.Close()
 
 	// Render slides
-	c.renderSlides(builder, slides, pptOpts)
+	if err := c.renderSlides(ctx, builder, slides, pptOpts); err != nil {
+		return err
+	}
 
 	// Save PDF
 	if err := builder.Save(outputPath); err != nil {
 		return errors.Wrap(err, errors.ErrWriteFailed, "Failed to save PDF")
 	}
+	c.layout = builder.Layout()
+	c.stats = &Stats{Sheets: len(slides), Orientation: c.layout.Orientation}
 
 	return nil
 }
@@ -131,7 +180,7 @@ func (c *PPTConverter) extractSlides(doc *mscfb.Reader) ([]PPTSlide, error) {
 		if entry.Name == "PowerPoint Document" {
 			pptData, err = io.ReadAll(entry)
 			if err != nil {
-				return nil, errors.Wrap(err, errors.ErrConversionFailed, "Failed to read PowerPoint Document stream")
+				return nil, errors.Wrap(err, errors.ErrCorruptFile, "Failed to read PowerPoint Document stream")
 			}
 			break
 		}
@@ -308,19 +357,24 @@ func (c *PPTConverter) isValidText(s string) bool {
 	return float64(validCount)/float64(len(s)) > 0.6
 }
 
-// renderSlides renders extracted slides to PDF
-func (c *PPTConverter) renderSlides(builder *pdf.Builder, slides []PPTSlide, opts pdf.Options) {
+// renderSlides renders extracted slides to PDF, stopping once ctx is done
+func (c *PPTConverter) renderSlides(ctx context.Context, builder *pdf.Builder, slides []PPTSlide, opts pdf.Options) error {
 	titleStyle := pdf.HeaderStyle()
 	titleStyle.FontSize = 24
 
 	bodyStyle := pdf.DefaultStyle()
 	bodyStyle.FontSize = 12
+	bodyStyle.Alignment = opts.TextAlignment()
 
 	noteStyle := pdf.DefaultStyle()
 	noteStyle.FontSize = 10
 	noteStyle.TextColor = pdf.ColorGray
 
 	for i, slide := range slides {
+		if err := contextError(ctx, ""); err != nil {
+			return err
+		}
+		builder.BeginSection(fmt.Sprintf("Slide %d", slide.Index))
 		if i > 0 {
 			builder.AddPage()
 		} else {
@@ -334,15 +388,17 @@ func (c *PPTConverter) renderSlides(builder *pdf.Builder, slides []PPTSlide, opt
 
 		// Render title
 		if slide.Title != "" {
+			builder.KeepTogether(opts.TextLineHeight(titleStyle.FontSize, titleStyle) + 20 + opts.TextLineHeight(bodyStyle.FontSize, bodyStyle))
 			builder.AddText(slide.Title, titleStyle)
 			builder.NewLine(20)
 		}
 
 		// Render body text
+		builder.BeginTextColumns()
 		for _, text := range slide.Body {
-			builder.AddText("• "+text, bodyStyle)
-			builder.NewLine(bodyStyle.FontSize + 6)
+			builder.AddParagraph("• "+text, bodyStyle)
 		}
+		builder.EndTextColumns()
 
 		// Add slide number at bottom
 		builder.SetXY(opts.ContentWidth()-30, opts.ContentHeight()-10)
@@ -354,12 +410,11 @@ func (c *PPTConverter) renderSlides(builder *pdf.Builder, slides []PPTSlide, opt
 		builder.AddPage()
 		builder.AddText("No text content could be extracted from this PPT file.", noteStyle)
 		builder.NewLine(20)
-		builder.AddText("For best results with legacy .ppt files, consider:", noteStyle)
-		builder.NewLine(noteStyle.FontSize + 4)
-		builder.AddText("1. Converting to .pptx format first", noteStyle)
-		builder.NewLine(noteStyle.FontSize + 4)
+		builder.AddParagraph("For best results with legacy .ppt files, consider:", noteStyle)
+		builder.AddParagraph("1. Converting to .pptx format first", noteStyle)
 		builder.AddText("2. Installing LibreOffice for full fidelity conversion", noteStyle)
 	}
+	return nil
 }
 
 // sanitizeOptionsForPPT returns options with only general settings applied
@@ -375,6 +430,10 @@ func (c *PPTConverter) sanitizeOptionsForPPT(opts pdf.Options) pdf.Options {
 	pptOpts.Margin = opts.Margin
 	pptOpts.FontFamily = opts.FontFamily
 	pptOpts.FontSize = opts.FontSize
+	pptOpts.TextAlign = opts.TextAlign
+	pptOpts.LineHeight = opts.LineHeight
+	pptOpts.ParagraphSpacing = opts.ParagraphSpacing
+	pptOpts.TextColumns = opts.TextColumns
 	
 	// Keep metadata options
 	pptOpts.Title = opts.Title
@@ -384,12 +443,19 @@ func (c *PPTConverter) sanitizeOptionsForPPT(opts pdf.Options) pdf.Options {
 	// Keep header/footer options
 	pptOpts.HeaderText = opts.HeaderText
 	pptOpts.FooterText = opts.FooterText
+	pptOpts.HeaderFooterOverflow = opts.HeaderFooterOverflow
+	pptOpts.PageNumberStart = opts.PageNumberStart
+	pptOpts.DateFormat = opts.DateFormat
+	pptOpts.Timezone = opts.Timezone
+	pptOpts.Locale = opts.Locale
 	
 	// Keep watermark options
 	pptOpts.CustomFontPath = opts.CustomFontPath
 	pptOpts.WatermarkText = opts.WatermarkText
 	pptOpts.WatermarkImage = opts.WatermarkImage
 	pptOpts.WatermarkAlpha = opts.WatermarkAlpha
+	pptOpts.WatermarkTile = opts.WatermarkTile
+	pptOpts.WatermarkAngle = opts.WatermarkAngle
 	
 	// Keep quality options
 	pptOpts.Compression = opts.Compression
@@ -2,6 +2,7 @@ package converter
 
 import (
 	"archive/zip"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"image"
@@ -10,6 +11,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -20,12 +22,19 @@ import (
 	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
 )
 
+func init() {
+	Register(FormatPPTX, func() Converter { return NewPPTXConverter() })
+}
+
 // PPTXConverter handles PowerPoint (PPTX) to PDF conversion
 type PPTXConverter struct {
 	opts            pdf.Options
 	libreOfficePath string
 	useLibreOffice  bool
 	forceNative     bool
+	layout          pdf.Layout
+	warnings        []string
+	stats           *Stats
 }
 
 // NewPPTXConverter creates a new PPTX converter
@@ -37,6 +46,26 @@ func NewPPTXConverter() *PPTXConverter {
 	return c
 }
 
+// Layout returns the page structure of the last PDF written by Convert
+func (c *PPTXConverter) Layout() pdf.Layout {
+	return c.layout
+}
+
+// Stats returns the statistics of the last Convert, or nil if it didn't render natively
+func (c *PPTXConverter) Stats() *Stats {
+	return c.stats
+}
+
+// Warnings returns non-fatal notes from the last Convert (e.g. slides drawn as images)
+func (c *PPTXConverter) Warnings() []string {
+	return c.warnings
+}
+
+// Requirements lists LibreOffice, without which slides are drawn natively
+func (c *PPTXConverter) Requirements() []string {
+	return []string{RequirementLibreOffice}
+}
+
 // SupportedExtensions returns extensions handled by this converter
 func (c *PPTXConverter) SupportedExtensions() []string {
 	return []string{".pptx", ".ppt", ".odp"}
@@ -95,6 +124,10 @@ func (c *PPTXConverter) Validate(inputPath string) error {
 
 	r, err := zip.OpenReader(inputPath)
 	if err != nil {
+		// ZIP signature present but unreadable (e.g. truncated central directory)
+		if hasSignature(inputPath, zipSignature) {
+			return errors.NewWithDetails(errors.ErrCorruptFile, "PPTX file is damaged (broken ZIP)", inputPath, err.Error())
+		}
 		return errors.NewWithDetails(errors.ErrInvalidFormat, "Not a valid PPTX file (invalid ZIP)", inputPath, err.Error())
 	}
 	defer r.Close()
@@ -103,11 +136,19 @@ func (c *PPTXConverter) Validate(inputPath string) error {
 	hasPresentationXML := false
 
 	for _, f := range r.File {
+		isRequired := false
 		if f.Name == "[Content_Types].xml" {
 			hasContentTypes = true
+			isRequired = true
 		}
 		if strings.Contains(f.Name, "ppt/presentation.xml") {
 			hasPresentationXML = true
+			isRequired = true
+		}
+		if isRequired {
+			if err := checkZipEntry(f); err != nil {
+				return errors.NewWithDetails(errors.ErrCorruptFile, "PPTX file is damaged (unreadable "+f.Name+")", inputPath, err.Error())
+			}
 		}
 	}
 
@@ -121,31 +162,44 @@ func (c *PPTXConverter) Validate(inputPath string) error {
 
 // Convert performs the PPTX to PDF conversion
 func (c *PPTXConverter) Convert(inputPath, outputPath string, opts pdf.Options) error {
+	return c.ConvertCtx(context.Background(), inputPath, outputPath, opts)
+}
+
+// ConvertCtx is Convert, stopping between slides and killing LibreOffice once ctx is done
+func (c *PPTXConverter) ConvertCtx(ctx context.Context, inputPath, outputPath string, opts pdf.Options) error {
 	if err := c.Validate(inputPath); err != nil {
 		return err
 	}
+	rng, err := slideRangeOption(opts)
+	if err != nil {
+		return err
+	}
+	c.warnings = nil
 
 	// Use LibreOffice if available and not forced to native
 	if c.useLibreOffice && !c.forceNative {
-		err := c.convertWithLibreOffice(inputPath, outputPath)
-		if err == nil {
-			return nil
+		err := c.convertWithLibreOffice(ctx, inputPath, outputPath, opts, rng)
+		if err == nil || opts.PDFA || ctx.Err() != nil {
+			return err
 		}
-		// Fall back to native if LibreOffice fails
+		// Fall back to native if LibreOffice fails; native output can't be PDF/A
 	}
 
 	// Native Go conversion with improved rendering
-	return c.convertNative(inputPath, outputPath, opts)
+	return c.convertNative(ctx, inputPath, outputPath, opts, rng)
 }
 
 // convertWithLibreOffice uses LibreOffice for high-fidelity conversion
-func (c *PPTXConverter) convertWithLibreOffice(inputPath, outputPath string) error {
-	loConverter := NewLibreOfficeConverter(c.libreOfficePath)
-	return loConverter.Convert(inputPath, outputPath)
+func (c *PPTXConverter) convertWithLibreOffice(ctx context.Context, inputPath, outputPath string, opts pdf.Options, rng pageRange) error {
+	loConverter := NewLibreOfficeConverter(c.libreOfficePath, opts.TempDir)
+	loConverter.SetPDFA(opts.PDFA)
+	loConverter.SetImageQuality(opts.ImageQuality())
+	loConverter.SetPageRange(rng.String())
+	return loConverter.ConvertCtx(ctx, inputPath, outputPath)
 }
 
 // convertNative performs native Go conversion with improved slide rendering
-func (c *PPTXConverter) convertNative(inputPath, outputPath string, opts pdf.Options) error {
+func (c *PPTXConverter) convertNative(ctx context.Context, inputPath, outputPath string, opts pdf.Options, rng pageRange) error {
 	r, err := zip.OpenReader(inputPath)
 	if err != nil {
 		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to open PPTX")
@@ -153,7 +207,7 @@ func (c *PPTXConverter) convertNative(inputPath, outputPath string, opts pdf.Opt
 	defer r.Close()
 
 	// Create temp directory for extracted images
-	tempDir, err := os.MkdirTemp("", "pptx-images-*")
+	tempDir, err := os.MkdirTemp(opts.TempDir, "pptx-images-*")
 	if err != nil {
 		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create temp directory")
 	}
@@ -165,14 +219,18 @@ func (c *PPTXConverter) convertNative(inputPath, outputPath string, opts pdf.Opt
 	// Parse slide relationships to map images
 	relMap := c.parseRelationships(r)
 
-	// Parse slides with full content
-	slides, err := c.parseSlides(r, imageMap, relMap)
+	// Parse the selected slides with full content
+	slides, count, err := c.parseSlides(r, imageMap, relMap, rng)
 	if err != nil {
 		return err
 	}
+	if len(slides) == 0 && count > 0 && rng != nil {
+		return noSlidesSelected(opts, count)
+	}
 
 	// Get slide dimensions from presentation.xml
 	slideWidth, slideHeight := c.getSlideSize(r)
+	rasters := c.rasterizeUnsupported(ctx, inputPath, slides, opts, tempDir)
 
 	// For PowerPoint, use only general options (page size, margins, watermark, header/footer)
 	// Ignore table-specific customization options (they only apply to spreadsheets)
@@ -184,21 +242,40 @@ func (c *PPTXConverter) convertNative(inputPath, outputPath string, opts pdf.Opt
 	if err != nil {
 		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create PDF builder")
 	}
+	defer builder.Close()
 
 	// Render each slide
+	var rasterized []int
 	for i, slide := range slides {
+		if err := contextError(ctx, inputPath); err != nil {
+			return err
+		}
+		builder.BeginSection(fmt.Sprintf("Slide %d", slide.Index))
 		if i > 0 {
 			builder.AddPage()
 		} else {
 			builder.AddPage()
 		}
+		builder.AddAnchor(slideAnchor(slide.Index))
 
+		if raster, ok := rasters[slide.Position]; ok {
+			x, y, w, h := slideArea(pptOpts, slideWidth, slideHeight)
+			if err := builder.AddImage(raster, x, y, w, h); err == nil {
+				rasterized = append(rasterized, slide.Position)
+				continue
+			}
+		}
 		c.renderSlideEnhanced(builder, slide, pptOpts, slideWidth, slideHeight, tempDir)
 	}
+	if len(rasterized) > 0 {
+		c.warnings = append(c.warnings, fmt.Sprintf("Drawn as images, so their text can't be selected: %s", slideList(rasterized)))
+	}
 
 	if err := builder.Save(outputPath); err != nil {
 		return errors.Wrap(err, errors.ErrWriteFailed, "Failed to save PDF")
 	}
+	c.layout = builder.Layout()
+	c.stats = &Stats{Sheets: len(slides), Orientation: c.layout.Orientation}
 
 	return nil
 }
@@ -206,7 +283,9 @@ func (c *PPTXConverter) convertNative(inputPath, outputPath string, opts pdf.Opt
 
 // Slide represents a parsed PowerPoint slide with full content
 type Slide struct {
-	Index      int
+	Index      int  // Number in the slide's file name, ppt/slides/slide<Index>.xml
+	Position   int  // Place in the deck, counted from 1
+	Unsupported bool // Has content renderSlideEnhanced can't draw: charts, tables, SmartArt or grouped shapes
 	Title      string
 	Texts      []SlideText
 	Images     []SlideImage
@@ -226,6 +305,8 @@ type SlideText struct {
 	Alignment string
 	Color     string // Hex color like "FFFFFF"
 	IsTitle   bool
+	Links     []pdf.TextLink   // Hyperlinked runs, as byte ranges of Content
+	Formats   []pdf.TextFormat // Bold, italic and underlined runs, likewise
 }
 
 // SlideImage represents an image on a slide
@@ -278,7 +359,8 @@ func (c *PPTXConverter) extractImages(r *zip.ReadCloser, tempDir string) map[str
 	return imageMap
 }
 
-// parseRelationships parses slide relationships to map rId to image files
+// parseRelationships parses slide relationships to map rId to image files, hyperlink
+// targets and, for links to other slides, their file names (slide3.xml)
 func (c *PPTXConverter) parseRelationships(r *zip.ReadCloser) map[string]map[string]string {
 	relMap := make(map[string]map[string]string)
 
@@ -307,6 +389,7 @@ func (c *PPTXConverter) parseRelationships(r *zip.ReadCloser) map[string]map[str
 			// Parse relationships XML
 			type Relationship struct {
 				Id     string `xml:"Id,attr"`
+				Type   string `xml:"Type,attr"`
 				Target string `xml:"Target,attr"`
 			}
 			type Relationships struct {
@@ -317,7 +400,12 @@ func (c *PPTXConverter) parseRelationships(r *zip.ReadCloser) map[string]map[str
 			xml.Unmarshal(data, &rels)
 
 			for _, rel := range rels.Rels {
-				if strings.Contains(rel.Target, "media/") {
+				switch {
+				case strings.HasSuffix(rel.Type, "/hyperlink"):
+					relMap[slideNum][rel.Id] = rel.Target
+				case strings.HasSuffix(rel.Type, "/slide"):
+					relMap[slideNum][rel.Id] = path.Base(rel.Target)
+				case strings.Contains(rel.Target, "media/"):
 					relMap[slideNum][rel.Id] = filepath.Base(rel.Target)
 				}
 			}
@@ -360,8 +448,9 @@ func (c *PPTXConverter) getSlideSize(r *zip.ReadCloser) (float64, float64) {
 	return defaultWidth, defaultHeight
 }
 
-// parseSlides extracts slide information from PPTX with full content
-func (c *PPTXConverter) parseSlides(r *zip.ReadCloser, imageMap map[string]string, relMap map[string]map[string]string) ([]Slide, error) {
+// parseSlides extracts the slides selected by rng from PPTX with full content, and
+// returns them with the number of slides in the deck
+func (c *PPTXConverter) parseSlides(r *zip.ReadCloser, imageMap map[string]string, relMap map[string]map[string]string, rng pageRange) ([]Slide, int, error) {
 	var slides []Slide
 
 	slideFiles := make(map[int]*zip.File)
@@ -381,17 +470,20 @@ func (c *PPTXConverter) parseSlides(r *zip.ReadCloser, imageMap map[string]strin
 	}
 	sort.Ints(slideNums)
 
-	for _, num := range slideNums {
+	for i, num := range slideNums {
+		if !rng.contains(i + 1) {
+			continue
+		}
 		slideFile := slideFiles[num]
 		slide, err := c.parseSlideXMLEnhanced(slideFile, imageMap, relMap[strconv.Itoa(num)])
 		if err != nil {
 			continue
 		}
-		slide.Index = num
+		slide.Index, slide.Position = num, i+1
 		slides = append(slides, slide)
 	}
 
-	return slides, nil
+	return slides, len(slideNums), nil
 }
 
 // PPTX XML structures for enhanced parsing
@@ -410,6 +502,8 @@ type slideXMLEnhanced struct {
 		SpTree struct {
 			Sp  []shapeXMLEnhanced `xml:"sp"`
 			Pic []picXML           `xml:"pic"`
+			GraphicFrame []struct{} `xml:"graphicFrame"` // Charts, tables and SmartArt
+			GrpSp        []struct{} `xml:"grpSp"`
 		} `xml:"spTree"`
 	} `xml:"cSld"`
 }
@@ -451,6 +545,10 @@ type runXMLEnhanced struct {
 		Sz   string `xml:"sz,attr"`
 		B    string `xml:"b,attr"`
 		I    string `xml:"i,attr"`
+		U    string `xml:"u,attr"` // Underline kind, e.g. "sng"; "none" for none
+		HlinkClick *struct {
+			ID string `xml:"id,attr"` // r:id of the target's relationship
+		} `xml:"hlinkClick"`
 		SolidFill *struct {
 			SrgbClr *struct {
 				Val string `xml:"val,attr"`
@@ -502,8 +600,11 @@ func (c *PPTXConverter) parseSlideXMLEnhanced(f *zip.File, imageMap map[string]s
 	var sld slideXMLEnhanced
 	if err := xml.Unmarshal(data, &sld); err != nil {
 		// Fall back to simple text extraction
-		return c.extractTextSimple(data), nil
+		slide = c.extractTextSimple(data)
+		slide.Unsupported = true
+		return slide, nil
 	}
+	slide.Unsupported = len(sld.CSld.SpTree.GraphicFrame) > 0 || len(sld.CSld.SpTree.GrpSp) > 0
 
 	// Extract background color
 	if sld.CSld.Bg != nil && sld.CSld.Bg.BgPr != nil && sld.CSld.Bg.BgPr.SolidFill != nil {
@@ -519,7 +620,9 @@ func (c *PPTXConverter) parseSlideXMLEnhanced(f *zip.File, imageMap map[string]s
 			continue
 		}
 
-		var textContent strings.Builder
+		var paras []string
+		var links []pdf.TextLink
+		var formats []pdf.TextFormat
 		var fontSize float64 = 12
 		var color string
 		var bold, italic bool
@@ -529,18 +632,30 @@ func (c *PPTXConverter) parseSlideXMLEnhanced(f *zip.File, imageMap map[string]s
 			if p.PPr != nil && p.PPr.Algn != "" {
 				alignment = p.PPr.Algn
 			}
+			var para strings.Builder
+			var paraLinks []pdf.TextLink
+			var paraFormats []pdf.TextFormat
 			for _, r := range p.R {
-				textContent.WriteString(r.T)
+				start := para.Len()
+				para.WriteString(r.T)
+				if link, ok := runLink(r, slideRels); ok {
+					link.Start, link.End = start, para.Len()
+					paraLinks = append(paraLinks, link)
+				}
+				if format, ok := runFormat(r); ok {
+					format.Start, format.End = start, para.Len()
+					paraFormats = append(paraFormats, format)
+				}
 				if r.RPr != nil {
 					if r.RPr.Sz != "" {
 						if sz, err := strconv.ParseFloat(r.RPr.Sz, 64); err == nil {
 							fontSize = sz / 100 // Convert from hundredths of a point
 						}
 					}
-					if r.RPr.B == "1" {
+					if xmlBool(r.RPr.B) {
 						bold = true
 					}
-					if r.RPr.I == "1" {
+					if xmlBool(r.RPr.I) {
 						italic = true
 					}
 					if r.RPr.SolidFill != nil {
@@ -552,10 +667,34 @@ func (c *PPTXConverter) parseSlideXMLEnhanced(f *zip.File, imageMap map[string]s
 					}
 				}
 			}
-			textContent.WriteString("\n")
+
+			// Empty paragraphs are dropped and the others trimmed, moving the
+			// links and formats along
+			trimmed := strings.TrimSpace(para.String())
+			if trimmed == "" {
+				continue
+			}
+			lead := strings.Index(para.String(), trimmed)
+			base := 0 // Offset of the paragraph in the shape's text
+			for _, p := range paras {
+				base += len(p) + 1
+			}
+			for _, link := range paraLinks {
+				var ok bool
+				if link.Start, link.End, ok = shiftRange(link.Start, link.End, lead, len(trimmed), base); ok {
+					links = append(links, link)
+				}
+			}
+			for _, format := range paraFormats {
+				var ok bool
+				if format.Start, format.End, ok = shiftRange(format.Start, format.End, lead, len(trimmed), base); ok {
+					formats = append(formats, format)
+				}
+			}
+			paras = append(paras, trimmed)
 		}
 
-		text := strings.TrimSpace(textContent.String())
+		text := strings.Join(paras, "\n")
 		if text == "" {
 			continue
 		}
@@ -592,6 +731,8 @@ func (c *PPTXConverter) parseSlideXMLEnhanced(f *zip.File, imageMap map[string]s
 			Alignment: alignment,
 			Color:     color,
 			IsTitle:   isTitle,
+			Links:     links,
+			Formats:   formats,
 		})
 	}
 
@@ -642,6 +783,60 @@ func (c *PPTXConverter) parseSlideXMLEnhanced(f *zip.File, imageMap map[string]s
 	return slide, nil
 }
 
+// slideFilePattern matches the file name of a slide, capturing its number
+var slideFilePattern = regexp.MustCompile(`^slide(\d+)\.xml$`)
+
+// slideAnchor names the page of slide file ppt/slides/slide<num>.xml for links to it
+func slideAnchor(num int) string {
+	return fmt.Sprintf("slide-%d", num)
+}
+
+// runLink returns the link of a run with a hyperlink: to a web or mail address, or
+// to another slide of the deck. Other targets (files, programs) aren't linked.
+func runLink(r runXMLEnhanced, slideRels map[string]string) (pdf.TextLink, bool) {
+	if r.RPr == nil || r.RPr.HlinkClick == nil || r.RPr.HlinkClick.ID == "" {
+		return pdf.TextLink{}, false
+	}
+	target := slideRels[r.RPr.HlinkClick.ID]
+	if m := slideFilePattern.FindStringSubmatch(target); m != nil {
+		num, _ := strconv.Atoi(m[1])
+		return pdf.TextLink{Anchor: slideAnchor(num)}, true
+	}
+	lower := strings.ToLower(target)
+	for _, scheme := range []string{"http://", "https://", "mailto:"} {
+		if strings.HasPrefix(lower, scheme) {
+			return pdf.TextLink{URL: target}, true
+		}
+	}
+	return pdf.TextLink{}, false
+}
+
+// runFormat returns the bold, italic and underline of a run, if it has any
+func runFormat(r runXMLEnhanced) (pdf.TextFormat, bool) {
+	if r.RPr == nil {
+		return pdf.TextFormat{}, false
+	}
+	format := pdf.TextFormat{
+		Bold:      xmlBool(r.RPr.B),
+		Italic:    xmlBool(r.RPr.I),
+		Underline: r.RPr.U != "" && r.RPr.U != "none",
+	}
+	return format, format.Bold || format.Italic || format.Underline
+}
+
+// xmlBool reports whether an xsd:boolean attribute is true
+func xmlBool(s string) bool {
+	return s == "1" || s == "true"
+}
+
+// shiftRange moves a byte range of a paragraph to the shape's text, where the
+// paragraph starts at base without its first lead bytes and is length bytes long.
+// It returns false when nothing of the range is left.
+func shiftRange(start, end, lead, length, base int) (int, int, bool) {
+	start, end = max(start-lead, 0), min(end-lead, length)
+	return base + start, base + end, start < end
+}
+
 // mapSchemeColor maps PowerPoint scheme colors to hex values
 func (c *PPTXConverter) mapSchemeColor(scheme string) string {
 	colorMap := map[string]string{
@@ -697,6 +892,60 @@ func (c *PPTXConverter) extractTextSimple(data []byte) Slide {
 }
 
 
+// rasterizeUnsupported renders the slides renderSlideEnhanced can't draw as images
+// with Options.RasterizeFallback, into tempDir, and returns them by deck position.
+// When the tools are missing or fail, the slides are drawn natively with a warning.
+func (c *PPTXConverter) rasterizeUnsupported(ctx context.Context, inputPath string, slides []Slide, opts pdf.Options, tempDir string) map[int]string {
+	if !opts.RasterizeFallback {
+		return nil
+	}
+	var positions []int
+	for _, slide := range slides {
+		if slide.Unsupported {
+			positions = append(positions, slide.Position)
+		}
+	}
+	if len(positions) == 0 {
+		return nil
+	}
+
+	var rasters map[int]string
+	rasterizer, err := newSlideRasterizer(c.libreOfficePath, tempDir, opts.RasterDPI)
+	if err == nil {
+		rasters, err = rasterizer.rasterize(ctx, inputPath, positions)
+	}
+	if err != nil {
+		c.warnings = append(c.warnings, fmt.Sprintf("Not drawn as images (%v), so only their text is shown: %s", err, slideList(positions)))
+		return nil
+	}
+	return rasters
+}
+
+// slideList names slides by deck position: "Slide 3" or "Slides 3, 5"
+func slideList(positions []int) string {
+	parts := make([]string, len(positions))
+	for i, pos := range positions {
+		parts[i] = strconv.Itoa(pos)
+	}
+	if len(parts) == 1 {
+		return "Slide " + parts[0]
+	}
+	return "Slides " + strings.Join(parts, ", ")
+}
+
+// slideArea returns the box of the page renderSlideEnhanced draws a slide in,
+// keeping the slide's aspect ratio
+func slideArea(opts pdf.Options, slideW, slideH float64) (x, y, w, h float64) {
+	pageWidth, pageHeight := opts.PageSize.Height, opts.PageSize.Width
+	if opts.Orientation == pdf.Portrait {
+		pageWidth, pageHeight = opts.PageSize.Width, opts.PageSize.Height
+	}
+	contentWidth := pageWidth - (opts.Margin * 2)
+	contentHeight := pageHeight - (opts.Margin * 2) - 40
+	scale := min(contentWidth/slideW, contentHeight/slideH)
+	return opts.Margin, opts.Margin + 20, slideW * scale, slideH * scale
+}
+
 // renderSlideEnhanced renders a slide to PDF with images and better layout
 func (c *PPTXConverter) renderSlideEnhanced(builder *pdf.Builder, slide Slide, opts pdf.Options, slideW, slideH float64, tempDir string) {
 	// Calculate scale factor from EMUs to PDF points
@@ -823,9 +1072,6 @@ func (c *PPTXConverter) renderSlideEnhanced(builder *pdf.Builder, slide Slide, o
 		// Create text style
 		style := pdf.DefaultStyle()
 		style.FontSize = fontSize
-		if text.Bold {
-			style.FontStyle = "B"
-		}
 
 		// Handle text color with smart fallback
 		if text.Color != "" {
@@ -836,21 +1082,20 @@ func (c *PPTXConverter) renderSlideEnhanced(builder *pdf.Builder, slide Slide, o
 			}
 		}
 
-		builder.SetXY(textX, textY)
-		builder.SetFont(style.FontFamily, style.FontStyle, style.FontSize)
-		builder.SetTextColor(style.TextColor)
+		style.Alignment = slideTextAlignment(text.Alignment)
 
-		// Draw text lines
-		lines := strings.Split(text.Content, "\n")
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if line == "" {
-				continue
-			}
-			builder.GetPdf().Text(line)
-			textY += fontSize + 4
-			builder.SetXY(textX, textY)
+		// Wrap the text within the shape's box, or the rest of the slide for shapes
+		// without one (e.g. placeholders positioned by the layout)
+		textW := emuToPoints(text.Width)
+		if maxW := pageWidth - opts.Margin - textX; textW <= 0 || textW > maxW {
+			textW = maxW
 		}
+		textH := emuToPoints(text.Height)
+		if maxH := opts.Margin + 20 + contentHeight - textY; textH <= 0 || textH > maxH {
+			textH = maxH
+		}
+		builder.AddRichTextBlock(textX, textY, textW, textH,
+			pdf.RichText{Text: text.Content, Links: text.Links, Formats: text.Formats}, style)
 	}
 
 	// Add slide number
@@ -863,6 +1108,19 @@ func (c *PPTXConverter) renderSlideEnhanced(builder *pdf.Builder, slide Slide, o
 	builder.GetPdf().Text(fmt.Sprintf("Slide %d", slide.Index))
 }
 
+// slideTextAlignment maps a DrawingML paragraph alignment (algn) to a Style alignment
+func slideTextAlignment(algn string) int {
+	switch algn {
+	case "ctr":
+		return pdf.AlignCenter
+	case "r":
+		return pdf.AlignRight
+	case "just", "dist":
+		return pdf.AlignJustify
+	}
+	return pdf.AlignLeft
+}
+
 // HasLibreOffice returns whether LibreOffice is available
 func (c *PPTXConverter) HasLibreOffice() bool {
 	return c.useLibreOffice
@@ -891,6 +1149,9 @@ func (c *PPTXConverter) sanitizeOptionsForPPT(opts pdf.Options) pdf.Options {
 	pptOpts.Margin = opts.Margin
 	pptOpts.FontFamily = opts.FontFamily
 	pptOpts.FontSize = opts.FontSize
+	pptOpts.LineHeight = opts.LineHeight
+	pptOpts.ParagraphSpacing = opts.ParagraphSpacing
+	pptOpts.ShrinkTextToFit = opts.ShrinkTextToFit // Shrinks text overflowing its shape
 	
 	// Keep metadata options
 	pptOpts.Title = opts.Title
@@ -900,12 +1161,19 @@ func (c *PPTXConverter) sanitizeOptionsForPPT(opts pdf.Options) pdf.Options {
 	// Keep header/footer options
 	pptOpts.HeaderText = opts.HeaderText
 	pptOpts.FooterText = opts.FooterText
+	pptOpts.HeaderFooterOverflow = opts.HeaderFooterOverflow
+	pptOpts.PageNumberStart = opts.PageNumberStart
+	pptOpts.DateFormat = opts.DateFormat
+	pptOpts.Timezone = opts.Timezone
+	pptOpts.Locale = opts.Locale
 	
 	// Keep watermark options
 	pptOpts.CustomFontPath = opts.CustomFontPath
 	pptOpts.WatermarkText = opts.WatermarkText
 	pptOpts.WatermarkImage = opts.WatermarkImage
 	pptOpts.WatermarkAlpha = opts.WatermarkAlpha
+	pptOpts.WatermarkTile = opts.WatermarkTile
+	pptOpts.WatermarkAngle = opts.WatermarkAngle
 	
 	// Keep quality options
 	pptOpts.Compression = opts.Compression
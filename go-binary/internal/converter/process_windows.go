@@ -0,0 +1,8 @@
+//go:build windows
+
+package converter
+
+import "os/exec"
+
+// killGroupOnCancel leaves cmd as is: its context kills only the process itself
+func killGroupOnCancel(cmd *exec.Cmd) {}
@@ -0,0 +1,80 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+)
+
+// slideRasterizer draws whole slides as images, for Options.RasterizeFallback:
+// LibreOffice exports the slides to a PDF and pdftoppm (poppler-utils) renders
+// its pages as PNG files. The images look exactly like the slides, but they make
+// the output larger and their text can't be selected or searched.
+type slideRasterizer struct {
+	libreOfficePath string
+	pdftoppmPath    string
+	tempDir         string // Directory the PDF and images are written to
+	dpi             int
+}
+
+// newSlideRasterizer returns a rasterizer writing to tempDir, or an error naming
+// the tool that is missing
+func newSlideRasterizer(libreOfficePath, tempDir string, dpi int) (*slideRasterizer, error) {
+	if libreOfficePath == "" {
+		return nil, fmt.Errorf("LibreOffice is not installed")
+	}
+	pdftoppm, err := exec.LookPath("pdftoppm")
+	if err != nil {
+		return nil, fmt.Errorf("pdftoppm (poppler-utils) is not installed")
+	}
+	if dpi <= 0 {
+		dpi = pdf.DefaultOptions().RasterDPI
+	}
+	return &slideRasterizer{libreOfficePath: libreOfficePath, pdftoppmPath: pdftoppm, tempDir: tempDir, dpi: dpi}, nil
+}
+
+// rasterize renders the slides of the deck at inputPath at the given positions,
+// counted from 1 in deck order, and returns the image of each by position. The
+// programs it runs are killed once ctx is done.
+func (r *slideRasterizer) rasterize(ctx context.Context, inputPath string, positions []int) (map[int]string, error) {
+	sort.Ints(positions)
+	pages := make([]string, len(positions))
+	for i, pos := range positions {
+		pages[i] = strconv.Itoa(pos)
+	}
+
+	slidesPDF := filepath.Join(r.tempDir, "rasterized-slides.pdf")
+	lo := NewLibreOfficeConverter(r.libreOfficePath, r.tempDir)
+	lo.SetPageRange(strings.Join(pages, ","))
+	lo.SetExportHiddenSlides(true) // Keeps page numbers equal to deck positions
+	if err := lo.ConvertCtx(ctx, inputPath, slidesPDF); err != nil {
+		return nil, err
+	}
+
+	prefix := filepath.Join(r.tempDir, "rasterized-slide")
+	cmd := exec.CommandContext(ctx, r.pdftoppmPath, "-png", "-r", strconv.Itoa(r.dpi), slidesPDF, prefix)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("pdftoppm failed: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	// pdftoppm numbers the images by page, zero-padded to the same width
+	images, err := filepath.Glob(prefix + "-*.png")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(images)
+	if len(images) != len(positions) {
+		return nil, fmt.Errorf("LibreOffice exported %d pages for %d slides", len(images), len(positions))
+	}
+	byPosition := make(map[int]string, len(positions))
+	for i, pos := range positions {
+		byPosition[pos] = images[i]
+	}
+	return byPosition, nil
+}
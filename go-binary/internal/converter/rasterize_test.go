@@ -0,0 +1,161 @@
+package converter
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+)
+
+// fakeRasterTools writes shell scripts standing in for soffice and pdftoppm to
+// a directory put first on PATH, and returns the soffice path. soffice writes
+// its arguments, which hold the exported page range, as the slides PDF;
+// pdftoppm writes one image per page in that range holding the -r resolution,
+// one image fewer when FAKE_PDFTOPPM_SHORT is set, and fails when
+// FAKE_PDFTOPPM_FAIL is set.
+func fakeRasterTools(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake tools are shell scripts")
+	}
+	dir := t.TempDir()
+	scripts := map[string]string{
+		"soffice": `#!/bin/sh
+for arg; do
+	if [ "$prev" = --outdir ]; then out=$arg; fi
+	prev=$arg
+done
+echo "$@" > "$out/slides.pdf"
+`,
+		"pdftoppm": `#!/bin/sh
+if [ -n "$FAKE_PDFTOPPM_FAIL" ]; then echo "broken PDF" >&2; exit 1; fi
+pages=$(sed 's/.*"PageRange":{"type":"string","value":"\([^"]*\)".*/\1/' "$4" | tr ',' ' ')
+set -- "$@" $pages
+dpi=$3 prefix=$5
+shift 5
+if [ -n "$FAKE_PDFTOPPM_SHORT" ]; then shift; fi
+n=0
+for page; do
+	n=$((n + 1))
+	echo "$dpi" > "$(printf '%s-%02d.png' "$prefix" "$n")"
+done
+`,
+	}
+	for name, script := range scripts {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return filepath.Join(dir, "soffice")
+}
+
+func TestNewSlideRasterizer(t *testing.T) {
+	soffice := fakeRasterTools(t)
+	r, err := newSlideRasterizer(soffice, t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("newSlideRasterizer: %v", err)
+	}
+	if r.dpi != pdf.DefaultOptions().RasterDPI || filepath.Base(r.pdftoppmPath) != "pdftoppm" {
+		t.Errorf("rasterizer %+v, want the default DPI and pdftoppm from PATH", r)
+	}
+
+	if _, err := newSlideRasterizer("", t.TempDir(), 150); err == nil || !strings.Contains(err.Error(), "LibreOffice") {
+		t.Errorf("without LibreOffice: %v, want an error naming it", err)
+	}
+	t.Setenv("PATH", t.TempDir())
+	if _, err := newSlideRasterizer(soffice, t.TempDir(), 150); err == nil || !strings.Contains(err.Error(), "pdftoppm") {
+		t.Errorf("without pdftoppm: %v, want an error naming it", err)
+	}
+}
+
+func TestSlideRasterizerRasterize(t *testing.T) {
+	soffice := fakeRasterTools(t)
+	deck := filepath.Join(t.TempDir(), "deck.pptx")
+	if err := os.WriteFile(deck, []byte("deck"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rasterize := func(positions ...int) (map[int]string, error) {
+		r, err := newSlideRasterizer(soffice, t.TempDir(), 96)
+		if err != nil {
+			t.Fatalf("newSlideRasterizer: %v", err)
+		}
+		return r.rasterize(context.Background(), deck, positions)
+	}
+
+	// Positions are exported in deck order and matched to pdftoppm's numbered pages
+	images, err := rasterize(12, 3, 7)
+	if err != nil {
+		t.Fatalf("rasterize: %v", err)
+	}
+	got := make(map[int]string)
+	for pos, path := range images {
+		got[pos] = filepath.Base(path)
+		if data, err := os.ReadFile(path); err != nil || strings.TrimSpace(string(data)) != "96" {
+			t.Errorf("slide %d image %q (%v), want one rendered at 96 dpi", pos, data, err)
+		}
+	}
+	want := map[int]string{3: "rasterized-slide-01.png", 7: "rasterized-slide-02.png", 12: "rasterized-slide-03.png"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("images %v, want %v", got, want)
+	}
+
+	t.Setenv("FAKE_PDFTOPPM_SHORT", "1")
+	if _, err := rasterize(1, 2); err == nil || !strings.Contains(err.Error(), "exported 1 pages for 2 slides") {
+		t.Errorf("with a page missing: %v, want the count mismatch", err)
+	}
+	t.Setenv("FAKE_PDFTOPPM_SHORT", "")
+	t.Setenv("FAKE_PDFTOPPM_FAIL", "1")
+	if _, err := rasterize(1); err == nil || !strings.Contains(err.Error(), "broken PDF") {
+		t.Errorf("with pdftoppm failing: %v, want its output", err)
+	}
+}
+
+func TestSlideArea(t *testing.T) {
+	portrait := pdf.DefaultOptions()
+	portrait.Orientation = pdf.Portrait
+	landscape := portrait
+	landscape.Orientation = pdf.Landscape
+
+	tests := []struct {
+		name           string
+		opts           pdf.Options
+		slideW, slideH float64
+		fullWidth      bool // The slide fills the content width rather than its height
+	}{
+		{"16:9 on portrait", portrait, 16, 9, true},
+		{"16:9 on landscape", landscape, 16, 9, true},
+		{"4:3 on landscape", landscape, 4, 3, false},
+		{"tall slide on portrait", portrait, 9, 32, false},
+		{"wide slide on landscape", landscape, 40, 9, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pageW, pageH := tt.opts.PageSize.Width, tt.opts.PageSize.Height
+			if tt.opts.Orientation == pdf.Landscape {
+				pageW, pageH = pageH, pageW
+			}
+			contentW, contentH := pageW-2*tt.opts.Margin, pageH-2*tt.opts.Margin-40
+
+			x, y, w, h := slideArea(tt.opts, tt.slideW, tt.slideH)
+			if x != tt.opts.Margin || y != tt.opts.Margin+20 {
+				t.Errorf("slide at %v,%v, want below the top margin's header room", x, y)
+			}
+			if math.Abs(w/h-tt.slideW/tt.slideH) > 1e-9 {
+				t.Errorf("slide drawn %vx%v, want the %v:%v aspect ratio", w, h, tt.slideW, tt.slideH)
+			}
+			if w > contentW+1e-9 || h > contentH+1e-9 {
+				t.Errorf("slide drawn %vx%v, larger than the %vx%v content area", w, h, contentW, contentH)
+			}
+			if fits := math.Abs(w-contentW) < 1e-9; fits != tt.fullWidth {
+				t.Errorf("slide drawn %vx%v in %vx%v, want it to fill the width: %v", w, h, contentW, contentH, tt.fullWidth)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package converter
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+)
+
+// Factory creates a new converter instance for one conversion
+type Factory func() Converter
+
+// Registry maps input formats to converter factories
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[FormatType]Factory
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: make(map[FormatType]Factory),
+	}
+}
+
+// Register adds or replaces the factory for a format
+func (r *Registry) Register(format FormatType, factory Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[format] = factory
+}
+
+// GetConverter returns a new converter for the format
+func (r *Registry) GetConverter(format FormatType) (Converter, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[format]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, errors.New(errors.ErrUnsupportedFormat, "Unsupported file format: "+string(format))
+	}
+	return factory(), nil
+}
+
+// Formats returns the registered formats in sorted order
+func (r *Registry) Formats() []FormatType {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	formats := make([]FormatType, 0, len(r.factories))
+	for format := range r.factories {
+		formats = append(formats, format)
+	}
+	sort.Slice(formats, func(i, j int) bool { return formats[i] < formats[j] })
+	return formats
+}
+
+// defaultRegistry holds the built-in converters, which register themselves in init
+var defaultRegistry = NewRegistry()
+
+// Register adds a converter factory to the default registry. Embedders can use it
+// to support their own formats or replace a built-in converter.
+func Register(format FormatType, factory Factory) {
+	defaultRegistry.Register(format, factory)
+}
+
+// GetConverter returns a new converter for the format from the default registry
+func GetConverter(format FormatType) (Converter, error) {
+	return defaultRegistry.GetConverter(format)
+}
+
+// RegisteredFormats returns the formats in the default registry
+func RegisteredFormats() []FormatType {
+	return defaultRegistry.Formats()
+}
+
+// Optional interfaces a converter may implement to receive run settings or report results
+
+// ContextConverter is implemented by converters that stop when a context is done:
+// between rows, sheets or slides, and by killing the programs they run
+type ContextConverter interface {
+	ConvertCtx(ctx context.Context, inputPath, outputPath string, opts pdf.Options) error
+}
+
+// ProgressReporter is implemented by converters that report progress
+type ProgressReporter interface {
+	SetProgressCallback(callback func(int))
+}
+
+// LibreOfficeUser is implemented by converters that can use LibreOffice
+type LibreOfficeUser interface {
+	SetLibreOfficePath(path string)
+}
+
+// NativeForcer is implemented by converters that can skip LibreOffice
+type NativeForcer interface {
+	SetForceNative(force bool)
+}
+
+// LayoutReporter is implemented by converters that report the generated page layout
+type LayoutReporter interface {
+	Layout() pdf.Layout
+}
+
+// WarningReporter is implemented by converters that report non-fatal warnings
+type WarningReporter interface {
+	Warnings() []string
+}
+
+// StatsReporter is implemented by converters that report conversion statistics
+type StatsReporter interface {
+	Stats() *Stats
+}
+
+// RequirementReporter is implemented by converters that need external programs
+// installed to convert their format with full fidelity
+type RequirementReporter interface {
+	Requirements() []string
+}
+
+// Configure applies the common run settings to a converter, skipping any it doesn't support
+func Configure(c Converter, libreOfficePath string, native bool, onProgress func(int)) {
+	if p, ok := c.(ProgressReporter); ok && onProgress != nil {
+		p.SetProgressCallback(onProgress)
+	}
+	if l, ok := c.(LibreOfficeUser); ok && libreOfficePath != "" {
+		l.SetLibreOfficePath(libreOfficePath)
+	}
+	if n, ok := c.(NativeForcer); ok && native {
+		n.SetForceNative(true)
+	}
+}
+
+// UsesLibreOffice reports whether converting the format may start a LibreOffice
+// process: its converter can use LibreOffice and native mode doesn't rule it out
+func UsesLibreOffice(format FormatType, native bool) bool {
+	c, err := GetConverter(format)
+	if err != nil {
+		return false
+	}
+	if _, ok := c.(LibreOfficeUser); !ok {
+		return false
+	}
+	if _, ok := c.(NativeForcer); ok && native {
+		return false
+	}
+	return true
+}
+
+// LayoutOf returns the page layout reported by a converter, if any
+func LayoutOf(c Converter) pdf.Layout {
+	if l, ok := c.(LayoutReporter); ok {
+		return l.Layout()
+	}
+	return pdf.Layout{}
+}
+
+// StatsOf returns the statistics reported by a converter, or nil if it has none
+// (e.g. when LibreOffice rendered the PDF)
+func StatsOf(c Converter) *Stats {
+	if s, ok := c.(StatsReporter); ok {
+		return s.Stats()
+	}
+	return nil
+}
+
+// WarningsOf returns the warnings reported by a converter, if any
+func WarningsOf(c Converter) []string {
+	if w, ok := c.(WarningReporter); ok {
+		return w.Warnings()
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package converter
+
+import (
+	"os/exec"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+)
+
+// External programs a conversion may need, as listed by RequirementsOf
+const (
+	RequirementLibreOffice = "LibreOffice"
+	RequirementPdftoppm    = "pdftoppm" // From poppler-utils
+	// Either program renders Options.Thumbnail, pdftoppm by preference
+	RequirementThumbnailer = "pdftoppm or LibreOffice"
+)
+
+// RequirementsOf lists the external programs converting format with opts needs:
+// those of its converter and those of options such as Options.RasterizeFallback,
+// Options.PDFA and Options.Thumbnail. Formats rendered natively with such options
+// off need none and get an empty slice.
+func RequirementsOf(format FormatType, opts pdf.Options) ([]string, error) {
+	c, err := GetConverter(format)
+	if err != nil {
+		return nil, err
+	}
+	requirements := []string{}
+	if r, ok := c.(RequirementReporter); ok {
+		requirements = append(requirements, r.Requirements()...)
+	}
+	if opts.PDFA {
+		// Only LibreOffice's PDF export writes PDF/A
+		requirements = appendMissing(requirements, RequirementLibreOffice)
+	}
+	if opts.RasterizeFallback && (format == FormatPPTX || format == FormatPPT) {
+		requirements = appendMissing(requirements, RequirementLibreOffice, RequirementPdftoppm)
+	}
+	if opts.Thumbnail != "" {
+		requirements = appendMissing(requirements, RequirementThumbnailer)
+	}
+	return requirements, nil
+}
+
+// appendMissing appends the names not in list yet
+func appendMissing(list []string, names ...string) []string {
+	for _, name := range names {
+		found := false
+		for _, have := range list {
+			found = found || have == name
+		}
+		if !found {
+			list = append(list, name)
+		}
+	}
+	return list
+}
+
+// FindRequirement returns where an external program named by Requirements is
+// installed, and whether it is. libreOfficePath is an explicit LibreOffice binary
+// ("" = auto-detect), as for RunConfig.
+func FindRequirement(name, libreOfficePath string) (string, bool) {
+	switch name {
+	case RequirementLibreOffice:
+		return findLibreOffice(libreOfficePath)
+	case RequirementPdftoppm:
+		path, err := exec.LookPath("pdftoppm")
+		return path, err == nil
+	case RequirementThumbnailer:
+		if path, ok := FindRequirement(RequirementPdftoppm, libreOfficePath); ok {
+			return path, true
+		}
+		return findLibreOffice(libreOfficePath)
+	}
+	return "", false
+}
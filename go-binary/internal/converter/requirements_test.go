@@ -0,0 +1,55 @@
+package converter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+)
+
+func TestRequirementsOf(t *testing.T) {
+	with := func(set func(*pdf.Options)) pdf.Options {
+		opts := pdf.DefaultOptions()
+		set(&opts)
+		return opts
+	}
+	rasterize := with(func(o *pdf.Options) { o.RasterizeFallback = true })
+	pdfa := with(func(o *pdf.Options) { o.PDFA = true })
+	thumbnail := with(func(o *pdf.Options) { o.Thumbnail = "preview.png" })
+	everything := with(func(o *pdf.Options) { o.RasterizeFallback, o.PDFA, o.Thumbnail = true, true, "preview.png" })
+
+	tests := []struct {
+		format FormatType
+		opts   pdf.Options
+		want   []string
+	}{
+		{FormatCSV, pdf.DefaultOptions(), []string{}},
+		{FormatXLSX, pdf.DefaultOptions(), []string{}},
+		{FormatXLS, pdf.DefaultOptions(), []string{RequirementLibreOffice}},
+		{FormatPPT, pdf.DefaultOptions(), []string{RequirementLibreOffice}},
+		{FormatPPTX, pdf.DefaultOptions(), []string{RequirementLibreOffice}},
+		{FormatCSV, rasterize, []string{}},
+		{FormatPPTX, rasterize, []string{RequirementLibreOffice, RequirementPdftoppm}},
+		{FormatPPT, rasterize, []string{RequirementLibreOffice, RequirementPdftoppm}},
+		{FormatCSV, pdfa, []string{RequirementLibreOffice}},
+		{FormatXLS, pdfa, []string{RequirementLibreOffice}},
+		{FormatCSV, thumbnail, []string{RequirementThumbnailer}},
+		{FormatXLS, thumbnail, []string{RequirementLibreOffice, RequirementThumbnailer}},
+		{FormatText, everything, []string{RequirementLibreOffice, RequirementThumbnailer}},
+		{FormatPPTX, everything, []string{RequirementLibreOffice, RequirementPdftoppm, RequirementThumbnailer}},
+	}
+	for _, tt := range tests {
+		got, err := RequirementsOf(tt.format, tt.opts)
+		if err != nil {
+			t.Fatalf("RequirementsOf(%s): %v", tt.format, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("RequirementsOf(%s, rasterize %v, PDF/A %v, thumbnail %q) = %q, want %q",
+				tt.format, tt.opts.RasterizeFallback, tt.opts.PDFA, tt.opts.Thumbnail, got, tt.want)
+		}
+	}
+
+	if _, err := RequirementsOf("docx", pdf.DefaultOptions()); err == nil {
+		t.Error("RequirementsOf succeeded for an unsupported format")
+	}
+}
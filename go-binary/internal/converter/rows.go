@@ -0,0 +1,353 @@
+package converter
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+)
+
+// rowFilter wraps a RowIterator and skips fully-empty, duplicate and non-matching data rows
+// (Options.DropEmptyRows / Options.Deduplicate / Options.Filter). Header rows are always kept.
+// It also enforces Options.MaxMemoryBytes, stopping the stream once the limit is reached,
+// and stops it once ctx is done.
+type rowFilter struct {
+	rows      pdf.RowIterator
+	dropEmpty bool
+	dedupe    bool
+	header    int // Leading header rows still to pass through untouched
+	seen      map[[sha256.Size]byte]struct{}
+	expr      *filterExpr
+	current   []string
+	err       error
+
+	maxBytes int64 // Limit on cell text passed to the builder (0 = unlimited)
+	bytes    int64
+	ctx      context.Context // Cancels the stream (nil = never)
+	stopErr  error           // Why the stream stopped early
+
+	// Columns of the table the rows are drawn in (0 = unknown). Cells beyond them,
+	// e.g. of a ragged row longer than the sampled rows, are moved into the last one.
+	columns int
+
+	dataRows         int
+	emptyDropped     int
+	duplicateDropped int
+	filteredOut      int
+	wideRows         int // Rows with text in cells beyond columns
+}
+
+// newRowFilter wraps rows with the row filters enabled in opts. headers are used
+// to resolve filter columns given by name.
+func newRowFilter(rows pdf.RowIterator, opts pdf.Options, headers []string) (*rowFilter, error) {
+	expr, err := parseFilterExpr(opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if expr != nil {
+		expr.locale = opts.InputNumberLocale()
+		if err := expr.resolve(headers); err != nil {
+			return nil, err
+		}
+	}
+
+	f := &rowFilter{
+		expr:      expr,
+		rows:      rows,
+		dropEmpty: opts.DropEmptyRows,
+		dedupe:    opts.Deduplicate,
+		header:    headerRowCount(opts),
+		maxBytes:  opts.MaxMemoryBytes,
+	}
+	if f.dedupe {
+		// Only a hash of each row is kept so memory stays bounded on large exports
+		f.seen = make(map[[sha256.Size]byte]struct{})
+	}
+	return f, nil
+}
+
+// headerRowCount returns how many leading rows are table headers
+func headerRowCount(opts pdf.Options) int {
+	if !opts.HeaderRow {
+		return 0
+	}
+	return opts.HeaderRowCount()
+}
+
+func (f *rowFilter) Next() bool {
+	for f.rows.Next() {
+		if f.ctx != nil {
+			if f.stopErr = contextError(f.ctx, ""); f.stopErr != nil {
+				return false
+			}
+		}
+		f.current, f.err = f.rows.Columns()
+		if f.err != nil {
+			return true // Let the consumer see the error
+		}
+		if f.maxBytes > 0 {
+			for _, cell := range f.current {
+				f.bytes += int64(len(cell))
+			}
+			if f.bytes > f.maxBytes {
+				f.stopErr = memoryLimitError(f.maxBytes)
+				return false
+			}
+		}
+		if f.header > 0 {
+			f.header--
+			f.fitWidth()
+			return true
+		}
+		if f.dropEmpty && isEmptyRow(f.current) {
+			f.emptyDropped++
+			continue
+		}
+		if f.expr != nil && !f.expr.Match(f.current) {
+			f.filteredOut++
+			continue
+		}
+		if f.dedupe {
+			key := rowKey(f.current)
+			if _, dup := f.seen[key]; dup {
+				f.duplicateDropped++
+				continue
+			}
+			f.seen[key] = struct{}{}
+		}
+		f.dataRows++
+		f.fitWidth()
+		return true
+	}
+	return false
+}
+
+// fitWidth moves text in cells the table has no column for onto lines of its own
+// in the last column, so the builder, which draws only the table's columns, shows it
+func (f *rowFilter) fitWidth() {
+	if f.columns == 0 || len(f.current) <= f.columns || isEmptyRow(f.current[f.columns:]) {
+		return
+	}
+	f.wideRows++
+	row := make([]string, f.columns)
+	copy(row, f.current)
+	lines := []string{row[f.columns-1]}
+	for _, cell := range f.current[f.columns:] {
+		if strings.TrimSpace(cell) != "" {
+			lines = append(lines, cell)
+		}
+	}
+	row[f.columns-1] = strings.Join(lines, "\n")
+	f.current = row
+}
+
+// DataRows returns the number of data rows (not header rows) passed through so far
+func (f *rowFilter) DataRows() int {
+	return f.dataRows
+}
+
+// addTable counts a table drawn from f with the given number of columns
+func (s *Stats) addTable(f *rowFilter, columns int) {
+	s.Rows += f.dataRows
+	s.SkippedRows += f.emptyDropped + f.duplicateDropped + f.filteredOut
+	if columns > s.Columns {
+		s.Columns = columns
+	}
+	if ranged, ok := f.rows.(*rangeRowIterator); ok && ranged.truncated {
+		s.Truncated = true
+	}
+}
+
+func (f *rowFilter) Columns() ([]string, error) {
+	return f.current, f.err
+}
+
+// CellFills forwards the cell backgrounds of the current row from the wrapped iterator
+func (f *rowFilter) CellFills() map[int]pdf.Color {
+	if filler, ok := f.rows.(pdf.CellFiller); ok {
+		return filler.CellFills()
+	}
+	return nil
+}
+
+// CellComments forwards the cell comments of the current row from the wrapped iterator
+func (f *rowFilter) CellComments() map[int]string {
+	if commenter, ok := f.rows.(pdf.CellCommenter); ok {
+		return commenter.CellComments()
+	}
+	return nil
+}
+
+// Err returns the error that stopped the stream early, such as an exceeded memory
+// limit or a cancelled context
+func (f *rowFilter) Err() error {
+	return f.stopErr
+}
+
+// sliceRowIterator iterates over rows already read into memory
+type sliceRowIterator struct {
+	rows [][]string
+	next int
+}
+
+func (s *sliceRowIterator) Next() bool {
+	if s.next >= len(s.rows) {
+		return false
+	}
+	s.next++
+	return true
+}
+
+func (s *sliceRowIterator) Columns() ([]string, error) {
+	return s.rows[s.next-1], nil
+}
+
+// transposeRows swaps the rows and columns of a table (Options.Transpose). Short
+// rows are padded with empty cells, so the result is rectangular.
+func transposeRows(rows [][]string) [][]string {
+	cols := 0
+	for _, row := range rows {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+	transposed := make([][]string, cols)
+	for i := range transposed {
+		transposed[i] = make([]string, len(rows))
+		for j, row := range rows {
+			if i < len(row) {
+				transposed[i][j] = row[i]
+			}
+		}
+	}
+	return transposed
+}
+
+// transposedOptions returns opts for drawing a transposed table. Its first column
+// holds the field names, so it has no header row.
+func transposedOptions(opts pdf.Options) pdf.Options {
+	opts.HeaderRow, opts.HeaderRows, opts.AutoDetectHeader = false, 1, false
+	return opts
+}
+
+// readSample reads up to n rows from rows, e.g. to size columns before drawing
+func readSample(rows pdf.RowIterator, n int) [][]string {
+	var sample [][]string
+	for len(sample) < n && rows.Next() {
+		row, err := rows.Columns()
+		if err != nil {
+			continue
+		}
+		sample = append(sample, row)
+	}
+	return sample
+}
+
+// memoryLimitError is returned when accumulated row data exceeds Options.MaxMemoryBytes
+func memoryLimitError(limit int64) error {
+	return errors.NewWithDetails(errors.ErrMemoryLimit,
+		fmt.Sprintf("Input exceeds the memory limit of %d bytes", limit), "",
+		"Split the input into smaller files, filter rows with -filter, or raise -max-memory")
+}
+
+// Warnings describes the rows that were dropped, prefixed with scope (e.g. a sheet name) if set
+func (f *rowFilter) Warnings(scope string) []string {
+	prefix := ""
+	if scope != "" {
+		prefix = scope + ": "
+	}
+	var warnings []string
+	if f.emptyDropped > 0 {
+		warnings = append(warnings, fmt.Sprintf("%sdropped %d empty row(s)", prefix, f.emptyDropped))
+	}
+	if f.duplicateDropped > 0 {
+		warnings = append(warnings, fmt.Sprintf("%sdropped %d duplicate row(s)", prefix, f.duplicateDropped))
+	}
+	if f.filteredOut > 0 {
+		warnings = append(warnings, fmt.Sprintf("%sfiltered out %d row(s)", prefix, f.filteredOut))
+	}
+	if f.wideRows > 0 {
+		warnings = append(warnings, fmt.Sprintf("%s%d row(s) have more cells than the table's %d columns; the extra cells were added to the last column", prefix, f.wideRows, f.columns))
+	}
+	return warnings
+}
+
+// isEmptyRow reports whether every cell in row is blank
+func isEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// rowKey hashes a row for duplicate detection. Trailing empty cells are ignored
+// because spreadsheet rows of the same data can differ in reported length.
+func rowKey(row []string) [sha256.Size]byte {
+	end := len(row)
+	for end > 0 && row[end-1] == "" {
+		end--
+	}
+	return sha256.Sum256([]byte(strings.Join(row[:end], "\x1f")))
+}
+
+// detectHeaderRow guesses whether the sample starts with opts.HeaderRowCount()
+// header rows by comparing the last of them with the rows below it. Each column
+// whose data is numeric (in opts.InputNumberLocale) votes for a header if its label cell is
+// text and against one if that cell is a number. Without a majority (e.g. all-text
+// data or too few rows) opts.HeaderRow is returned.
+func detectHeaderRow(sample [][]string, opts pdf.Options) bool {
+	headerRows, fallback := opts.HeaderRowCount(), opts.HeaderRow
+	if len(sample) <= headerRows {
+		return fallback
+	}
+
+	votes := 0
+	for col, first := range sample[headerRows-1] {
+		if strings.TrimSpace(first) == "" || !isNumericColumn(sample[headerRows:], col, opts.InputNumberLocale()) {
+			continue
+		}
+		if _, ok := pdf.ParseNumber(first, opts.InputNumberLocale()); ok {
+			votes--
+		} else {
+			votes++
+		}
+	}
+
+	switch {
+	case votes > 0:
+		return true
+	case votes < 0:
+		return false
+	}
+	return fallback
+}
+
+// sampleColumnTypes infers the column types of a table from its sampled rows, so the
+// builder aligns and formats by column rather than by cell
+func sampleColumnTypes(sample [][]string, opts pdf.Options) []pdf.ColumnType {
+	if n := headerRowCount(opts); n < len(sample) {
+		return pdf.InferColumnTypes(sample[n:])
+	}
+	return nil
+}
+
+// isNumericColumn reports whether column col has at least one value and every
+// non-empty value in it is a number in the locale
+func isNumericColumn(rows [][]string, col int, locale string) bool {
+	found := false
+	for _, row := range rows {
+		if col >= len(row) || strings.TrimSpace(row[col]) == "" {
+			continue
+		}
+		if _, ok := pdf.ParseNumber(row[col], locale); !ok {
+			return false
+		}
+		found = true
+	}
+	return found
+}
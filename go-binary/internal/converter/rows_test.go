@@ -0,0 +1,108 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+)
+
+// filterRows passes rows (a header and data rows) through a rowFilter for a
+// table of columns columns and returns the rows it let through
+func filterRows(t *testing.T, rows [][]string, columns int, opts pdf.Options) (*rowFilter, [][]string) {
+	t.Helper()
+	f, err := newRowFilter(&sliceRowIterator{rows: rows}, opts, rows[0])
+	if err != nil {
+		t.Fatalf("newRowFilter: %v", err)
+	}
+	f.columns = columns
+	var kept [][]string
+	for f.Next() {
+		row, err := f.Columns()
+		if err != nil {
+			t.Fatalf("Columns: %v", err)
+		}
+		kept = append(kept, row)
+	}
+	return f, kept
+}
+
+func TestRowFilterRaggedRows(t *testing.T) {
+	header := []string{"Name", "Amount", "Date"}
+	dropEmpty := pdf.DefaultOptions()
+	dropEmpty.DropEmptyRows = true
+
+	tests := []struct {
+		name string
+		rows [][]string
+		opts pdf.Options
+		kept [][]string // Rows passed through, header included
+		wide int        // Rows with text beyond the table's columns
+	}{
+		{"rectangular", [][]string{header, {"Ada", "1", "2024-01-01"}}, pdf.DefaultOptions(), [][]string{header, {"Ada", "1", "2024-01-01"}}, 0},
+		{"short row is padded", [][]string{header, {"Ada"}, {"Bob", "2"}}, pdf.DefaultOptions(), [][]string{header, {"Ada"}, {"Bob", "2"}}, 0},
+		{"long row goes into the last column", [][]string{header, {"Ada", "1", "2024-01-01", "extra", "", "more"}}, pdf.DefaultOptions(), [][]string{header, {"Ada", "1", "2024-01-01\nextra\nmore"}}, 1},
+		{"blank cells past the table", [][]string{header, {"Ada", "1", "2024-01-01", "", " "}}, pdf.DefaultOptions(), [][]string{header, {"Ada", "1", "2024-01-01", "", " "}}, 0},
+		{"long header row", [][]string{append(header, "Note"), {"Ada", "1", "2024-01-01"}}, pdf.DefaultOptions(), [][]string{{"Name", "Amount", "Date\nNote"}, {"Ada", "1", "2024-01-01"}}, 1},
+		{"empty rows are kept", [][]string{header, {}, {"", ""}, {"Ada", "1"}}, pdf.DefaultOptions(), [][]string{header, {}, {"", ""}, {"Ada", "1"}}, 0},
+		{"empty rows are dropped", [][]string{header, {}, {"", "", "", ""}, {"Ada", "1"}}, dropEmpty, [][]string{header, {"Ada", "1"}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, kept := filterRows(t, tt.rows, len(header), tt.opts)
+			if !reflect.DeepEqual(kept, tt.kept) {
+				t.Errorf("kept %q, want %q", kept, tt.kept)
+			}
+			if f.wideRows != tt.wide {
+				t.Errorf("counted %d wide rows, want %d", f.wideRows, tt.wide)
+			}
+			var stats Stats
+			stats.addTable(f, len(header))
+			if stats.Truncated {
+				t.Error("Stats.Truncated is set, but no cells were left out")
+			}
+			if warned := strings.Contains(strings.Join(f.Warnings(""), "\n"), "more cells"); warned != (tt.wide > 0) {
+				t.Errorf("warnings %q, want a wide-row warning: %v", f.Warnings(""), tt.wide > 0)
+			}
+		})
+	}
+}
+
+func TestTransposeRowsPadsShortRows(t *testing.T) {
+	got := transposeRows([][]string{{"a", "b", "c"}, {"d"}, {}})
+	want := [][]string{{"a", "d", ""}, {"b", "", ""}, {"c", "", ""}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("transposeRows = %q, want %q", got, want)
+	}
+}
+
+// TestCSVRaggedRows converts a CSV whose rows are shorter, longer and emptier
+// than its header, including rows past the width sample
+func TestCSVRaggedRows(t *testing.T) {
+	var data strings.Builder
+	data.WriteString("Name,Amount,Date\n")
+	for i := 0; i < 300; i++ { // Beyond the rows sampled for column widths
+		data.WriteString("Ada,1.50,2024-01-01\n")
+	}
+	data.WriteString("Bob\n,,\n\nCy,3,2024-02-01,overflow\n")
+	dir := t.TempDir()
+	input := filepath.Join(dir, "ragged.csv")
+	if err := os.WriteFile(input, []byte(data.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCSVConverter()
+	if err := c.Convert(input, filepath.Join(dir, "ragged.pdf"), pdf.DefaultOptions()); err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	stats := c.Stats()
+	if stats.Columns != 3 || stats.Truncated {
+		t.Errorf("stats %+v, want 3 columns and nothing truncated", stats)
+	}
+	if !strings.Contains(strings.Join(c.Warnings(), "\n"), "1 row(s) have more cells than the table's 3 columns; the extra cells were added to the last column") {
+		t.Errorf("warnings %q, want one row reported with extra cells", c.Warnings())
+	}
+}
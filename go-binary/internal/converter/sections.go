@@ -0,0 +1,206 @@
+package converter
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+	"github.com/richardlehane/mscfb"
+)
+
+// CountSections returns the number of slides or sheets in a file without converting
+// it, reading only the part of the file that lists them. Formats drawn as a single
+// section (CSV, NDJSON, text, images) count 1. ODP presentations, which have no
+// FormatType of their own, are counted by their extension.
+func CountSections(path string) (int, error) {
+	if err := validateExists(path); err != nil {
+		return 0, err
+	}
+
+	var n int
+	var err error
+	format := DetectFormat(path)
+	if format == FormatAuto && getExtension(path) == ".odp" {
+		format = formatODP
+	}
+	switch format {
+	case FormatXLSX, FormatXLSM:
+		n, err = countZipElements(path, "xl/workbook.xml", "sheet")
+	case FormatODS:
+		n, err = countZipElements(path, "content.xml", "table")
+	case FormatPPTX:
+		n, err = countZipElements(path, "ppt/presentation.xml", "sldId")
+	case formatODP:
+		n, err = countZipElements(path, "content.xml", "page")
+	case FormatXLS:
+		n, err = countXLSSheets(path)
+	case FormatPPT:
+		n, err = countPPTSlides(path)
+	case FormatCSV, FormatTSV, FormatNDJSON, FormatText, FormatPNG, FormatJPEG:
+		return 1, nil
+	default:
+		return 0, errors.NewWithFile(errors.ErrUnsupportedFormat, "Cannot count sections of this format", path)
+	}
+	if err != nil {
+		return 0, errors.NewWithDetails(errors.ErrCorruptFile, "Cannot read file structure", path, err.Error())
+	}
+	return n, nil
+}
+
+// formatODP stands for .odp files in CountSections. LibreOffice converts them, as
+// part of the PPTX converter, so DetectFormat doesn't report them.
+const formatODP FormatType = "odp"
+
+// countZipElements counts the elements with the given local name in one entry of a
+// zip package. Their content is skipped, so elements nested in a counted one (e.g.
+// tables inside ODS cells) are not counted.
+func countZipElements(path, entry, name string) (int, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return 0, err
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if f.Name != entry {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return 0, err
+		}
+		defer rc.Close()
+
+		n := 0
+		d := xml.NewDecoder(rc)
+		for {
+			tok, err := d.Token()
+			if err == io.EOF {
+				return n, nil
+			}
+			if err != nil {
+				return 0, err
+			}
+			if start, ok := tok.(xml.StartElement); ok && start.Name.Local == name {
+				n++
+				if err := d.Skip(); err != nil {
+					return 0, err
+				}
+			}
+		}
+	}
+	return 0, fmt.Errorf("%s is missing", entry)
+}
+
+// Record types of the legacy binary formats read by countXLSSheets and countPPTSlides
+const (
+	xlsBoundSheet        = 0x0085 // BoundSheet8: one per sheet in the workbook globals
+	xlsEOF               = 0x000A // End of the workbook globals substream
+	pptDocument          = 0x03E8 // DocumentContainer
+	pptSlideListWithText = 0x0FF0
+	pptSlidePersistAtom  = 0x03F3 // One per slide in the slide list
+)
+
+// readOLEStream returns the first of the named streams in an OLE compound file
+func readOLEStream(path string, names ...string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	doc, err := mscfb.New(file)
+	if err != nil {
+		return nil, err
+	}
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		for _, name := range names {
+			if entry.Name == name {
+				return io.ReadAll(entry)
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s stream is missing", names[0])
+}
+
+// countXLSSheets counts the BoundSheet8 records of an .xls workbook's globals
+func countXLSSheets(path string) (int, error) {
+	data, err := readOLEStream(path, "Workbook", "Book")
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for pos := 0; pos+4 <= len(data); {
+		recType := binary.LittleEndian.Uint16(data[pos:])
+		recLen := int(binary.LittleEndian.Uint16(data[pos+2:]))
+		switch recType {
+		case xlsBoundSheet:
+			n++
+		case xlsEOF:
+			return n, nil
+		}
+		pos += 4 + recLen
+	}
+	return n, nil
+}
+
+// countPPTSlides counts the slides listed in the last DocumentContainer of a .ppt
+// file (later ones are written by incremental saves)
+func countPPTSlides(path string) (int, error) {
+	data, err := readOLEStream(path, "PowerPoint Document")
+	if err != nil {
+		return 0, err
+	}
+
+	n, found := 0, false
+	for pos := 0; pos+8 <= len(data); {
+		recType, recLen := pptRecordHeader(data[pos:])
+		end := pos + 8 + recLen
+		if end > len(data) {
+			end = len(data)
+		}
+		if recType == pptDocument {
+			n, found = countPPTSlideList(data[pos+8:end]), true
+		}
+		pos = end
+	}
+	if !found {
+		return 0, fmt.Errorf("no document record")
+	}
+	return n, nil
+}
+
+// countPPTSlideList counts the SlidePersistAtoms in the slide list (instance 0 of
+// SlideListWithTextContainer) of a DocumentContainer's content
+func countPPTSlideList(data []byte) int {
+	for pos := 0; pos+8 <= len(data); {
+		recType, recLen := pptRecordHeader(data[pos:])
+		end := pos + 8 + recLen
+		if end > len(data) {
+			end = len(data)
+		}
+		if recType == pptSlideListWithText && binary.LittleEndian.Uint16(data[pos:])>>4 == 0 {
+			n := 0
+			for child := pos + 8; child+8 <= end; {
+				childType, childLen := pptRecordHeader(data[child:])
+				if childType == pptSlidePersistAtom {
+					n++
+				}
+				child += 8 + childLen
+			}
+			return n
+		}
+		pos = end
+	}
+	return 0
+}
+
+// pptRecordHeader returns the type and length from the 8-byte header of a PPT record
+func pptRecordHeader(data []byte) (uint16, int) {
+	return binary.LittleEndian.Uint16(data[2:]), int(binary.LittleEndian.Uint32(data[4:]))
+}
@@ -0,0 +1,243 @@
+package converter
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+)
+
+// writeZipParts writes a zip package of the named parts to path
+func writeZipParts(t *testing.T, path string, parts map[string]string) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	zw := zip.NewWriter(file)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err == nil {
+			_, err = w.Write([]byte(content))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeOLEFile writes a version 3 compound file holding one stream, padded to
+// 4096 bytes so it is stored in regular sectors: the header, a FAT sector, a
+// directory sector and the stream's eight sectors
+func writeOLEFile(t *testing.T, path, stream string, data []byte) {
+	t.Helper()
+	const (
+		sectorSize = 512
+		freeSect   = 0xFFFFFFFF
+		endOfChain = 0xFFFFFFFE
+		fatSect    = 0xFFFFFFFD
+		noStream   = 0xFFFFFFFF
+	)
+	if len(data) > 8*sectorSize {
+		t.Fatalf("stream of %d bytes is too long", len(data))
+	}
+	file := make([]byte, 11*sectorSize)
+	le := binary.LittleEndian
+
+	header := file[:sectorSize]
+	copy(header, []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1})
+	le.PutUint16(header[24:], 0x003E) // Minor version
+	le.PutUint16(header[26:], 3)      // Major version
+	le.PutUint16(header[28:], 0xFFFE) // Little-endian
+	le.PutUint16(header[30:], 9)      // 512-byte sectors
+	le.PutUint16(header[32:], 6)      // 64-byte mini sectors
+	le.PutUint32(header[44:], 1)      // FAT sectors
+	le.PutUint32(header[48:], 1)      // First directory sector
+	le.PutUint32(header[56:], 4096)   // Mini stream cutoff
+	le.PutUint32(header[60:], endOfChain)
+	le.PutUint32(header[68:], endOfChain)
+	le.PutUint32(header[76:], 0) // The FAT is sector 0
+	for i := 80; i < sectorSize; i += 4 {
+		le.PutUint32(header[i:], freeSect)
+	}
+
+	fat := file[sectorSize : 2*sectorSize]
+	for i := 0; i < sectorSize/4; i++ {
+		next := uint32(freeSect)
+		switch {
+		case i == 0:
+			next = fatSect
+		case i == 1 || i == 9:
+			next = endOfChain
+		case i >= 2 && i < 9:
+			next = uint32(i + 1)
+		}
+		le.PutUint32(fat[4*i:], next)
+	}
+
+	dir := file[2*sectorSize : 3*sectorSize]
+	entry := func(i int, name string, kind byte, child, start uint32, size uint64) {
+		e := dir[128*i : 128*(i+1)]
+		units := utf16.Encode([]rune(name))
+		for j, u := range units {
+			le.PutUint16(e[2*j:], u)
+		}
+		le.PutUint16(e[64:], uint16(2*len(units)+2))
+		e[66], e[67] = kind, 1
+		le.PutUint32(e[68:], noStream)
+		le.PutUint32(e[72:], noStream)
+		le.PutUint32(e[76:], child)
+		le.PutUint32(e[116:], start)
+		le.PutUint64(e[120:], size)
+	}
+	entry(0, "Root Entry", 5, 1, endOfChain, 0)
+	entry(1, stream, 2, noStream, 2, 8*sectorSize)
+	for i := 2; i < 4; i++ {
+		le.PutUint32(dir[128*i+68:], noStream)
+		le.PutUint32(dir[128*i+72:], noStream)
+		le.PutUint32(dir[128*i+76:], noStream)
+	}
+
+	copy(file[3*sectorSize:], data)
+	if err := os.WriteFile(path, file, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// biffRecord returns a BIFF record (XLS) of the given type and content
+func biffRecord(recType uint16, content []byte) []byte {
+	rec := make([]byte, 4, 4+len(content))
+	binary.LittleEndian.PutUint16(rec, recType)
+	binary.LittleEndian.PutUint16(rec[2:], uint16(len(content)))
+	return append(rec, content...)
+}
+
+// pptRecord returns a PowerPoint record of the given instance, type and content
+func pptRecord(instance, recType uint16, content []byte) []byte {
+	rec := make([]byte, 8, 8+len(content))
+	binary.LittleEndian.PutUint16(rec, instance<<4|0xF)
+	binary.LittleEndian.PutUint16(rec[2:], recType)
+	binary.LittleEndian.PutUint32(rec[4:], uint32(len(content)))
+	return append(rec, content...)
+}
+
+func TestCountSections(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	xlsx := filepath.Join(dir, "book.xlsx")
+	f := excelize.NewFile()
+	f.NewSheet("Second")
+	f.NewSheet("Third")
+	if err := f.SaveAs(xlsx); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	ods := writeODSFile(t, dir, [2]string{"One", `<table:table-row><table:table-cell><table:table table:name="nested"/></table:table-cell></table:table-row>`}, [2]string{"Two", odsRow("a")})
+
+	pptx := filepath.Join(dir, "deck.pptx")
+	writeZipParts(t, pptx, map[string]string{
+		"ppt/presentation.xml": `<p:presentation xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"><p:sldIdLst><p:sldId id="256"/><p:sldId id="257"/><p:sldId id="258"/><p:sldId id="259"/></p:sldIdLst></p:presentation>`,
+	})
+
+	odp := filepath.Join(dir, "deck.odp")
+	writeZipParts(t, odp, map[string]string{
+		"content.xml": `<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:draw="urn:oasis:names:tc:opendocument:xmlns:drawing:1.0" xmlns:presentation="urn:oasis:names:tc:opendocument:xmlns:presentation:1.0"><office:body><office:presentation>` +
+			`<draw:page draw:name="1"><presentation:notes><draw:page-thumbnail/></presentation:notes></draw:page><draw:page draw:name="2"/></office:presentation></office:body></office:document-content>`,
+	})
+
+	xls := filepath.Join(dir, "book.xls")
+	var globals []byte
+	for i := 0; i < 3; i++ {
+		globals = append(globals, biffRecord(xlsBoundSheet, make([]byte, 8))...)
+	}
+	globals = append(globals, biffRecord(xlsEOF, nil)...)
+	globals = append(globals, biffRecord(xlsBoundSheet, make([]byte, 8))...) // In a sheet substream
+	writeOLEFile(t, xls, "Workbook", globals)
+
+	ppt := filepath.Join(dir, "deck.ppt")
+	slideList := func(n int) []byte {
+		var atoms []byte
+		for i := 0; i < n; i++ {
+			atoms = append(atoms, pptRecord(0, pptSlidePersistAtom, make([]byte, 20))...)
+		}
+		return pptRecord(0, pptSlideListWithText, atoms)
+	}
+	// An incremental save appends a second document with one more slide; the
+	// master list (instance 1) isn't counted
+	var stream []byte
+	stream = append(stream, pptRecord(0, pptDocument, slideList(2))...)
+	stream = append(stream, pptRecord(0, pptDocument, append(pptRecord(1, pptSlideListWithText, make([]byte, 0)), slideList(3)...))...)
+	writeOLEFile(t, ppt, "PowerPoint Document", stream)
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"xlsx", xlsx, 3},
+		{"ods", ods, 2},
+		{"pptx", pptx, 4},
+		{"odp", odp, 2},
+		{"xls", xls, 3},
+		{"ppt", ppt, 3},
+		{"csv", write("data.csv", "a,b\n1,2\n"), 1},
+		{"text", write("notes.txt", "Plain text\n"), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := CountSections(tt.path)
+			if err != nil {
+				t.Fatalf("CountSections: %v", err)
+			}
+			if n != tt.want {
+				t.Errorf("CountSections = %d, want %d", n, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountSectionsErrors(t *testing.T) {
+	dir := t.TempDir()
+	damaged := filepath.Join(dir, "damaged.pptx")
+	writeZipParts(t, damaged, map[string]string{"ppt/presentation.xml": "<p:presentation><p:sldIdLst>"})
+	listless := filepath.Join(dir, "listless.xlsx")
+	writeZipParts(t, listless, map[string]string{"[Content_Types].xml": "<Types/>"})
+	unknown := filepath.Join(dir, "archive.zip")
+	writeZipParts(t, unknown, map[string]string{"a.txt": "a"})
+
+	tests := []struct {
+		path string
+		code errors.ErrorCode
+	}{
+		{damaged, errors.ErrCorruptFile},
+		{listless, errors.ErrCorruptFile},
+		{unknown, errors.ErrUnsupportedFormat},
+		{filepath.Join(dir, "absent.xlsx"), errors.ErrFileNotFound},
+	}
+	for _, tt := range tests {
+		n, err := CountSections(tt.path)
+		var convErr *errors.ConversionError
+		if !stderrors.As(err, &convErr) || convErr.Code != tt.code {
+			t.Errorf("CountSections(%s) = %d, %v, want a %s error", filepath.Base(tt.path), n, err, tt.code)
+		}
+	}
+}
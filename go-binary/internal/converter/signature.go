@@ -0,0 +1,42 @@
+package converter
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+)
+
+// File signatures used to tell a damaged file ("right type, broken") from a file
+// of the wrong type
+var (
+	zipSignature  = []byte("PK\x03\x04")
+	oleSignature  = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+	gzipSignature = []byte{0x1F, 0x8B}
+)
+
+// hasSignature reports whether the file at path starts with the given magic bytes
+func hasSignature(path string, magic []byte) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(file, header); err != nil {
+		return false
+	}
+	return bytes.Equal(header, magic)
+}
+
+// checkZipEntry reads a ZIP entry to the end so truncated data and CRC mismatches surface
+func checkZipEntry(f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	_, err = io.Copy(io.Discard, rc)
+	return err
+}
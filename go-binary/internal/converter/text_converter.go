@@ -0,0 +1,240 @@
+package converter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+)
+
+func init() {
+	Register(FormatText, func() Converter { return NewTextConverter() })
+}
+
+// TextConverter renders plain text and log files as wrapped lines
+type TextConverter struct {
+	onProgress func(int)
+	layout     pdf.Layout
+	stats      *Stats
+}
+
+// NewTextConverter creates a new plain text converter
+func NewTextConverter() *TextConverter {
+	return &TextConverter{}
+}
+
+// SetProgressCallback sets the callback for progress reporting
+func (c *TextConverter) SetProgressCallback(callback func(int)) {
+	c.onProgress = callback
+}
+
+// Layout returns the page structure of the last PDF written by Convert
+func (c *TextConverter) Layout() pdf.Layout {
+	return c.layout
+}
+
+// Stats returns the statistics of the last Convert, or nil if it didn't render natively
+func (c *TextConverter) Stats() *Stats {
+	return c.stats
+}
+
+// SupportedExtensions returns extensions handled by this converter
+func (c *TextConverter) SupportedExtensions() []string {
+	return []string{".txt", ".log"}
+}
+
+// Validate checks that the input exists and looks like text rather than binary data
+func (c *TextConverter) Validate(inputPath string) error {
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return errors.NewWithFile(errors.ErrFileNotFound, "Cannot open file", inputPath)
+	}
+	defer file.Close()
+
+	head := make([]byte, 8*1024)
+	n, _ := io.ReadFull(file, head)
+	if bytes.IndexByte(head[:n], 0) >= 0 {
+		return errors.NewWithFile(errors.ErrInvalidFormat, "File is not plain text", inputPath)
+	}
+	return nil
+}
+
+// Convert renders the file line by line. Blank lines and indentation are kept, long
+// lines wrap, and a form feed starts a new page. With opts.LineNumbers each source
+// line is numbered in a gutter.
+func (c *TextConverter) Convert(inputPath, outputPath string, opts pdf.Options) error {
+	return c.ConvertCtx(context.Background(), inputPath, outputPath, opts)
+}
+
+// ConvertCtx is Convert, stopping between lines once ctx is done
+func (c *TextConverter) ConvertCtx(ctx context.Context, inputPath, outputPath string, opts pdf.Options) error {
+	if err := c.Validate(inputPath); err != nil {
+		return err
+	}
+
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return errors.NewWithFile(errors.ErrFileNotFound, "Cannot open input file", inputPath)
+	}
+	defer file.Close()
+
+	var totalSize int64
+	if info, err := file.Stat(); err == nil {
+		totalSize = info.Size()
+	}
+	if totalSize == 0 {
+		return errors.NewWithFile(errors.ErrInvalidFormat, "Text file is empty", inputPath)
+	}
+
+	builder, err := pdf.NewBuilder(opts)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrConversionFailed, "Failed to create PDF builder")
+	}
+	defer builder.Close()
+	builder.AddPage()
+	builder.BeginTextColumns()
+
+	style := pdf.DefaultStyle()
+	style.FontSize = opts.FontSize
+	style.Alignment = opts.TextAlignment()
+
+	// Size the gutter for the widest number the file can need
+	var gutterWidth float64
+	if opts.LineNumbers {
+		builder.SetFont(style.FontFamily, style.FontStyle, style.FontSize)
+		gutterWidth = builder.MeasureTextWidth(strings.Repeat("0", len(fmt.Sprint(countLines(inputPath))))) + 10
+	}
+
+	reader := bufio.NewReaderSize(file, 64*1024)
+	stats := &Stats{}
+	var bytesRead int64
+	lastProgress := -1
+	for lineNum := 1; ; lineNum++ {
+		if err := contextError(ctx, inputPath); err != nil {
+			return err
+		}
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return errors.NewWithDetails(errors.ErrConversionFailed, "Failed to read file", inputPath, readErr.Error())
+		}
+		if line == "" && readErr == io.EOF {
+			break
+		}
+		bytesRead += int64(len(line))
+
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		// Form feeds are page breaks
+		for strings.HasPrefix(line, "\f") {
+			builder.AddPage()
+			line = line[1:]
+		}
+
+		gutter := ""
+		if opts.LineNumbers {
+			gutter = fmt.Sprint(lineNum)
+		}
+		builder.AddTextLine(line, gutter, gutterWidth, style)
+		stats.Rows++
+
+		if c.onProgress != nil {
+			if progress := int(bytesRead * 100 / totalSize); progress != lastProgress {
+				lastProgress = progress
+				c.onProgress(progress)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	if err := builder.Save(outputPath); err != nil {
+		return errors.Wrap(err, errors.ErrWriteFailed, "Failed to save PDF")
+	}
+	c.layout = builder.Layout()
+	stats.Orientation = c.layout.Orientation
+	c.stats = stats
+
+	return nil
+}
+
+// countLines returns the number of lines in the file (0 if it can't be read)
+func countLines(path string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	count := 0
+	var last byte = '\n'
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			count += bytes.Count(buf[:n], []byte{'\n'})
+			last = buf[n-1]
+		}
+		if err != nil {
+			break
+		}
+	}
+	// A final line without a trailing newline
+	if last != '\n' {
+		count++
+	}
+	return count
+}
+
+// textSampleLines is how many records are parsed when sniffing a .txt file
+const textSampleLines = 20
+
+// detectTextFormat tells delimited data from prose in a .txt file: if a common
+// delimiter splits the first lines into the same number (>1) of fields, it is CSV,
+// otherwise plain text.
+func detectTextFormat(path string) FormatType {
+	for _, delimiter := range []rune{',', '\t', ';', '|'} {
+		if hasConsistentDelimiter(path, delimiter) {
+			return FormatCSV
+		}
+	}
+	return FormatText
+}
+
+// hasConsistentDelimiter reports whether the first records of the file all have
+// the same number (>1) of fields when split on delimiter
+func hasConsistentDelimiter(path string, delimiter rune) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(bufio.NewReader(file))
+	reader.Comma = delimiter
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = 0 // All records must match the first
+
+	records := 0
+	for records < textSampleLines {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil || len(record) < 2 {
+			return false
+		}
+		records++
+	}
+	return records >= 2
+}
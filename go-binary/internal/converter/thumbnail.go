@@ -0,0 +1,88 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+)
+
+// thumbnailer renders the first page of a PDF as a PNG, for Options.Thumbnail:
+// with pdftoppm (poppler-utils) when it is installed, which is fast, otherwise
+// with LibreOffice Draw's image export
+type thumbnailer struct {
+	pdftoppmPath    string // "" = use LibreOffice
+	libreOfficePath string
+	tempDir         string
+}
+
+// newThumbnailer returns a thumbnailer, or INVALID_OPTION when neither pdftoppm
+// nor LibreOffice (libreOfficePath, or auto-detected when empty) is installed
+func newThumbnailer(libreOfficePath, tempDir string) (*thumbnailer, error) {
+	if path, err := exec.LookPath("pdftoppm"); err == nil {
+		return &thumbnailer{pdftoppmPath: path, tempDir: tempDir}, nil
+	}
+	if path, ok := findLibreOffice(libreOfficePath); ok {
+		return &thumbnailer{libreOfficePath: path, tempDir: tempDir}, nil
+	}
+	return nil, errors.NewWithDetails(errors.ErrInvalidOption, "No thumbnail renderer is installed", "",
+		"Thumbnails are rendered with pdftoppm (poppler-utils) or LibreOffice; install either, or convert without a thumbnail")
+}
+
+// render writes the first page of the PDF at pdfPath to pngPath, width pixels wide
+// (Options.ThumbnailWidth when <= 0). The programs it runs are killed once ctx is done.
+func (t *thumbnailer) render(ctx context.Context, pdfPath, pngPath string, width int) error {
+	if width <= 0 {
+		width = pdf.DefaultOptions().ThumbnailWidth
+	}
+	dir, err := os.MkdirTemp(t.tempDir, "gopdfconv-thumbnail-*")
+	if err != nil {
+		return errors.Wrap(err, errors.ErrWriteFailed, "Failed to create temp directory")
+	}
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(filepath.Dir(pngPath), 0755); err != nil {
+		return errors.Wrap(err, errors.ErrWriteFailed, "Failed to create thumbnail directory")
+	}
+
+	if t.pdftoppmPath == "" {
+		return t.renderWithLibreOffice(ctx, pdfPath, pngPath, width, dir)
+	}
+	prefix := filepath.Join(dir, "thumbnail")
+	cmd := exec.CommandContext(ctx, t.pdftoppmPath, "-png", "-f", "1", "-l", "1", "-singlefile",
+		"-scale-to-x", strconv.Itoa(width), "-scale-to-y", "-1", pdfPath, prefix)
+	output, err := cmd.CombinedOutput()
+	if err := contextError(ctx, pdfPath); err != nil {
+		return err
+	}
+	if err != nil {
+		return errors.NewWithDetails(errors.ErrConversionFailed, "Failed to render the thumbnail", pngPath,
+			fmt.Sprintf("pdftoppm failed: %v: %s", err, strings.TrimSpace(string(output))))
+	}
+	if err := copyFile(prefix+".png", pngPath); err != nil {
+		return errors.Wrap(err, errors.ErrWriteFailed, "Failed to write the thumbnail")
+	}
+	return nil
+}
+
+// renderWithLibreOffice exports the first page with LibreOffice Draw, which
+// needs the height in pixels too: it follows the page's aspect ratio
+func (t *thumbnailer) renderWithLibreOffice(ctx context.Context, pdfPath, pngPath string, width int, dir string) error {
+	w, h, err := pdf.FirstPageSize(pdfPath)
+	if err != nil {
+		return errors.NewWithDetails(errors.ErrConversionFailed, "Failed to render the thumbnail", pngPath, err.Error())
+	}
+	height := int(math.Round(float64(width) * h / w))
+	format := fmt.Sprintf(`png:draw_png_Export:{"PixelWidth":{"type":"long","value":"%d"},"PixelHeight":{"type":"long","value":"%d"}}`,
+		width, max(height, 1))
+	if err := NewLibreOfficeConverter(t.libreOfficePath, dir).ConvertToCtx(ctx, pdfPath, pngPath, format); err != nil {
+		return errors.WrapPreserve(err, errors.ErrConversionFailed, "Failed to render the thumbnail")
+	}
+	return nil
+}
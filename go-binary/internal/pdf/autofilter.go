@@ -0,0 +1,24 @@
+package pdf
+
+import "github.com/signintech/gopdf"
+
+// filterButtonWidth is the width of the arrow marking a filtered column's header
+const filterButtonWidth = 6.0
+
+// SetFilterColumns marks the columns of the tables drawn next that have an Excel
+// autofilter: their header cells, in the last header row, show a small down arrow
+// like Excel's filter button. nil (the default) marks none.
+func (b *Builder) SetFilterColumns(columns []bool) {
+	defer b.enter()()
+	b.filterColumns = columns
+}
+
+// drawFilterButton draws the filter arrow at the right end of the header cell at
+// x on the current row, w wide and h high, in the header text color
+func (b *Builder) drawFilterButton(x, w, h float64, style Style) {
+	size := min(filterButtonWidth, w/4, h/3)
+	right := x + w - min(style.Padding, 3)
+	top := b.currentY + (h-size/2)/2
+	b.setFillColor(style.TextColor)
+	b.pdf.Polygon([]gopdf.Point{{X: right - size, Y: top}, {X: right, Y: top}, {X: right - size/2, Y: top + size/2}}, "F")
+}
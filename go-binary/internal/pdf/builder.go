@@ -2,29 +2,91 @@ package pdf
 
 import (
 	"fmt"
+	"image"
+	"io"
+	_ "image/jpeg" // Register decoders for image.DecodeConfig
+	_ "image/png"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
 
 	"github.com/signintech/gopdf"
 )
 
-// Builder provides a fluent interface for creating PDF documents
+// Builder provides a fluent interface for creating PDF documents. It is not safe for
+// concurrent use, and its methods panic when two goroutines use it at once.
 type Builder struct {
 	pdf       *gopdf.GoPdf
 	options   Options
 	currentY  float64
 	pageNum   int
+	firstPageNumber int // Number shown on the first page; later pages count up from it
 	fontLoaded bool
+	fontName   string // Font file loaded by loadFont, EmbeddedFontName, or "" for none
+	fontSubstituted bool // Options.CustomFontPath couldn't be loaded
+	createdAt time.Time // Timestamp shown by {{date}}/{{time}}, fixed for the whole document
+	sections  []Section // Page ranges of sheets/slides, in document order
+	headerBand float64  // Extra height taken by wrapped header lines on the current page
+	footerBand float64  // Extra height taken by wrapped footer lines on the current page
+	docPageSize gopdf.Rect // Default page size of the gopdf document; pages of other sizes carry their own
+	orientation Orientation // Orientation of the pages added so far, MixedOrientation if they differ
+
+	// Text columns (Options.TextColumns), between BeginTextColumns and EndTextColumns
+	inColumns    bool
+	column       int     // Column the text cursor is in, 0-based
+	columnTop    float64 // Y where the columns start on the current page
+	columnBottom float64 // Lowest Y reached by an earlier column on the current page
+
+	// Output splitting (Options.MaxPagesPerFile)
+	partStart int      // Pages written to earlier parts
+	parts     []string // Temp files holding the finished parts, moved into place by Save
+	outputFiles []string // Files written by Save
+	err       error    // Deferred error from finishing a part, returned by Save
 	
+	columnTypes []ColumnType // Inferred column types of the tables drawn next (SetColumnTypes)
+	filterColumns []bool     // Columns of the tables drawn next with a filter button (SetFilterColumns)
+
+	// Cell comments (Options.RenderComments)
+	footnotes    []string         // Footnote lines of the current page, drawn by drawFootnotes
+	footnoteBand float64          // Height they take above the footer
+	noteCount    int              // Footnotes numbered so far in the document
+	annotations  []textAnnotation // Text annotations of the current part, added after it is written
+
+	scaledImages map[string]string // Image drawn for each source image and size (Options.Quality), removed by Close
+	faces        map[int]bool      // Bold and italic faces of the font loaded so far (setStyledFont)
+
 	onProgress func(int)
+	onPage     func(pageNum int)
+
+	inUse atomic.Bool // Set while a method runs, to catch concurrent use (enter)
 }
 
 // SetProgressCallback sets the callback for progress reporting
 func (b *Builder) SetProgressCallback(callback func(int)) {
+	defer b.enter()()
 	b.onProgress = callback
 }
 
+// SetPageCallback sets a callback invoked after each page is added and its
+// header/footer are drawn, with the physical page number (1-based). The callback
+// may draw on the builder, e.g. page decorations.
+func (b *Builder) SetPageCallback(callback func(pageNum int)) {
+	defer b.enter()()
+	b.onPage = callback
+}
+
+// SetColumnTypes sets the types of the columns of the tables drawn next, which decide
+// cell alignment and which cells get locale number formatting. With nil (the default)
+// or Options.PerCellAlignment each cell is aligned by its own content.
+func (b *Builder) SetColumnTypes(types []ColumnType) {
+	defer b.enter()()
+	b.columnTypes = types
+}
+
 // NewBuilder creates a new PDF builder with the given options
 func NewBuilder(opts Options) (*Builder, error) {
 	pdf := &gopdf.GoPdf{}
@@ -35,6 +97,14 @@ func NewBuilder(opts Options) (*Builder, error) {
 		options:  opts,
 		currentY: opts.Margin,
 		pageNum:  0,
+		firstPageNumber: opts.PageNumberStart,
+		createdAt: time.Now(),
+		docPageSize: *opts.GetPageRect(),
+	}
+
+	// Options built without DefaultOptions leave PageNumberStart at 0
+	if b.firstPageNumber == 0 {
+		b.firstPageNumber = 1
 	}
 
 	// Load default font
@@ -45,16 +115,23 @@ func NewBuilder(opts Options) (*Builder, error) {
 	return b, nil
 }
 
-// loadFont loads the specified font or falls back to built-in
+// loadFont loads the specified font or falls back to built-in. With
+// Options.RequireFont, a custom font that can't be loaded is an error instead.
 func (b *Builder) loadFont() error {
 	// 1. Try custom font if specified
 	if b.options.CustomFontPath != "" {
-		if _, err := os.Stat(b.options.CustomFontPath); err == nil {
-			if err := b.pdf.AddTTFFont("default", b.options.CustomFontPath); err == nil {
-				b.fontLoaded = true
-				return b.pdf.SetFont("default", "", b.options.FontSize)
-			}
+		_, err := os.Stat(b.options.CustomFontPath)
+		if err == nil {
+			err = b.pdf.AddTTFFont("default", b.options.CustomFontPath)
 		}
+		if err == nil {
+			b.fontLoaded, b.fontName = true, b.options.CustomFontPath
+			return b.pdf.SetFont("default", "", b.options.FontSize)
+		}
+		if b.options.RequireFont {
+			return fmt.Errorf("font %s could not be loaded: %v", b.options.CustomFontPath, err)
+		}
+		b.fontSubstituted = true
 	}
 
 	// 2. Try to use system fonts first
@@ -68,7 +145,7 @@ func (b *Builder) loadFont() error {
 	for _, fontPath := range fontPaths {
 		if _, err := os.Stat(fontPath); err == nil {
 			if err := b.pdf.AddTTFFont("default", fontPath); err == nil {
-				b.fontLoaded = true
+				b.fontLoaded, b.fontName = true, fontPath
 				return b.pdf.SetFont("default", "", b.options.FontSize)
 			}
 		}
@@ -80,8 +157,7 @@ func (b *Builder) loadFont() error {
 
 // embedMinimalFont embeds a minimal font for basic operation
 func (b *Builder) embedMinimalFont() error {
-	// Use the TTF font embedded in the binary or from a known location
-	// For now, we'll try common locations
+	// Try user and local font locations, then the TTF font embedded in the binary
 	homeDir, _ := os.UserHomeDir()
 	additionalPaths := []string{
 		filepath.Join(homeDir, ".fonts", "DejaVuSans.ttf"),
@@ -91,18 +167,146 @@ func (b *Builder) embedMinimalFont() error {
 	for _, fontPath := range additionalPaths {
 		if _, err := os.Stat(fontPath); err == nil {
 			if err := b.pdf.AddTTFFont("default", fontPath); err == nil {
-				b.fontLoaded = true
+				b.fontLoaded, b.fontName = true, fontPath
 				return b.pdf.SetFont("default", "", b.options.FontSize)
 			}
 		}
 	}
 
+	// Minimal containers (scratch/distroless) have no fonts at all
+	if err := b.pdf.AddTTFFontData("default", embeddedFont); err == nil {
+		b.fontLoaded, b.fontName = true, EmbeddedFontName
+		return b.pdf.SetFont("default", "", b.options.FontSize)
+	}
+
+	if b.options.RequireFont {
+		return fmt.Errorf("no font could be loaded")
+	}
 	return nil // Proceed without font, will use basic rendering
 }
 
-// AddPage adds a new page to the document
+// Section describes the page range of one sheet or slide in the output PDF
+type Section struct {
+	Name      string `json:"name"`
+	StartPage int    `json:"start_page"`
+	EndPage   int    `json:"end_page"`
+}
+
+// Layout describes the page structure of a generated PDF
+type Layout struct {
+	PageCount   int
+	Sections    []Section
+	OutputFiles []string // Files written by Save (several when the output was split)
+	Orientation Orientation // Orientation of all pages, or MixedOrientation
+	Font        string      // Font file the text is drawn with, EmbeddedFontName, or "" when none could be loaded
+	FontSubstituted bool    // Options.CustomFontPath couldn't be loaded, so Font is a fallback
+}
+
+// BeginSection starts a named section on the next page added.
+// The previous section ends on the current page.
+func (b *Builder) BeginSection(name string) {
+	defer b.enter()()
+	b.closeSection()
+	b.sections = append(b.sections, Section{Name: name, StartPage: b.pageNum + 1})
+}
+
+// closeSection sets the end page of the open section, if any
+func (b *Builder) closeSection() {
+	if n := len(b.sections); n > 0 {
+		last := &b.sections[n-1]
+		last.EndPage = b.pageNum
+		if last.EndPage < last.StartPage {
+			last.EndPage = last.StartPage
+		}
+	}
+}
+
+// Layout returns the page count and section page ranges of the document so far
+func (b *Builder) Layout() Layout {
+	defer b.enter()()
+	b.closeSection()
+	sections := make([]Section, len(b.sections))
+	copy(sections, b.sections)
+	return Layout{PageCount: b.pageNum, Sections: sections, OutputFiles: b.outputFiles, Orientation: b.orientation,
+		Font: b.fontName, FontSubstituted: b.fontSubstituted}
+}
+
+// AddPage adds a new page to the document, in the orientation of the previous page
 func (b *Builder) AddPage() {
-	b.pdf.AddPage()
+	defer b.enter()()
+	b.addPage()
+}
+
+func (b *Builder) addPage() {
+	b.drawFootnotes()
+	b.newPage()
+}
+
+// AddPageWithOrientation adds a page in the given orientation. Later pages added with
+// AddPage keep it; header, footer and watermark are laid out for the new page size.
+func (b *Builder) AddPageWithOrientation(orientation Orientation) {
+	defer b.enter()()
+	b.addPageWith(b.options.PageSize, orientation)
+}
+
+// AddPageWith adds a page of the given size and orientation, e.g. for a section
+// in another format. Later pages added with AddPage keep them, and page breaks,
+// ContentWidth and ContentHeight follow the current page.
+func (b *Builder) AddPageWith(size PageSize, orientation Orientation) {
+	defer b.enter()()
+	b.addPageWith(size, orientation)
+}
+
+func (b *Builder) addPageWith(size PageSize, orientation Orientation) {
+	b.drawFootnotes()
+	b.options.PageSize = size
+	b.options.Orientation = orientation
+	b.newPage()
+}
+
+// newPage adds a page in the current size and orientation. Pages in the size the
+// gopdf document was started with use its default; others carry their own.
+func (b *Builder) newPage() {
+	b.splitIfFull()
+	if page := b.options.GetPageRect(); page.W == b.docPageSize.W && page.H == b.docPageSize.H {
+		b.pdf.AddPage()
+	} else {
+		b.pdf.AddPageWithOption(gopdf.PageOption{PageSize: page})
+	}
+	b.trackOrientation()
+	b.startPage()
+}
+
+// trackOrientation records the orientation of a page just added for Layout
+func (b *Builder) trackOrientation() {
+	orientation := Portrait
+	if b.options.Orientation == Landscape {
+		orientation = Landscape
+	}
+	if b.orientation == "" {
+		b.orientation = orientation
+	} else if b.orientation != orientation {
+		b.orientation = MixedOrientation
+	}
+}
+
+// ContentWidth returns the width between the side margins of the current page
+func (b *Builder) ContentWidth() float64 {
+	return b.options.ContentWidth()
+}
+
+// ContentHeight returns the height between the top and bottom margins of the current page
+func (b *Builder) ContentHeight() float64 {
+	return b.options.ContentHeight()
+}
+
+// pageHeight returns the height of the current page
+func (b *Builder) pageHeight() float64 {
+	return b.options.GetPageRect().H
+}
+
+// startPage draws the page decorations and resets the cursor after a page is added
+func (b *Builder) startPage() {
 	b.currentY = b.options.Margin
 	b.pageNum++
 	
@@ -112,13 +316,22 @@ func (b *Builder) AddPage() {
 	// Draw global header and footer
 	b.drawHeader()
 	b.drawFooter()
+	if b.options.SignatureOnAllPages() {
+		// Content and footnotes stay above the stamp
+		b.footerBand += b.drawSignature()
+	}
 	
 	// Reset Y to below header (add extra space if header text exists)
 	if b.options.HeaderText != "" {
-		b.currentY = b.options.Margin + 25
+		b.currentY = b.options.Margin + 25 + b.headerBand
 	} else {
 		b.currentY = b.options.Margin
 	}
+	b.column, b.columnTop, b.columnBottom = 0, b.currentY, b.currentY
+
+	if b.onPage != nil {
+		b.release(func() { b.onPage(b.pageNum) })
+	}
 }
 
 func (b *Builder) drawHeader() {
@@ -131,28 +344,28 @@ func (b *Builder) drawHeader() {
 	style.FontSize = 10
 	style.TextColor = ColorBlack
 	
-	b.SetFont(style.FontFamily, style.FontStyle, style.FontSize)
-	b.SetTextColor(style.TextColor)
-	
-	// Set Y position at top margin
-	b.pdf.SetY(b.options.Margin - 5)
+	b.setFont(style.FontFamily, style.FontStyle, style.FontSize)
+	b.setTextColor(style.TextColor)
 	
-	// Draw centered header text
-	b.drawTextWithPlaceholders(b.options.HeaderText, AlignCenter)
+	// Draw centered header text from the top margin down, within the content width
+	lines := b.fitHeaderFooterText(b.resolvePlaceholders(b.options.HeaderText), b.options.ContentWidth())
+	lineHeight := style.FontSize * style.LineHeight
+	for i, line := range lines {
+		b.pdf.SetY(b.options.Margin - 5 + float64(i)*lineHeight)
+		b.drawAligned(line, AlignCenter, hasDeferredPlaceholder(line))
+	}
+	b.headerBand = float64(len(lines)-1) * lineHeight
 }
 
 func (b *Builder) drawFooter() {
-	pageHeight := b.options.PageSize.Height
-	if b.options.Orientation == Landscape {
-		pageHeight = b.options.PageSize.Width
-	}
+	pageHeight := b.pageHeight()
 	
 	style := DefaultStyle()
 	style.FontSize = 8
 	style.TextColor = ColorGray
 	
-	b.SetFont(style.FontFamily, style.FontStyle, style.FontSize)
-	b.SetTextColor(style.TextColor)
+	b.setFont(style.FontFamily, style.FontStyle, style.FontSize)
+	b.setTextColor(style.TextColor)
 	
 	footerY := pageHeight - b.options.Margin + 5
 
@@ -166,27 +379,116 @@ func (b *Builder) drawFooter() {
 		text = "Generated by GoPdfConverter" // Default
 	}
 	
-	b.pdf.SetX(b.options.Margin)
-	b.pdf.SetY(footerY)
-	b.drawTextWithPlaceholders(text, AlignLeft)
-
 	// Right Section: Page Info (Fixed)
 	// "Page X of Y"
-	pageInfo := "Page {{page}} of {{total}}"
+	pageInfo := b.resolvePlaceholders("Page {{page}} of {{total}}")
 	b.pdf.SetY(footerY)
-	b.drawTextWithPlaceholders(pageInfo, AlignRight)
+	b.drawAligned(pageInfo, AlignRight, true)
+
+	// Left text shares the line with the page info, so it gets the remaining width.
+	// Wrapped lines stack upwards so the last line stays on the footer baseline.
+	pageInfoWidth := b.MeasureTextWidth(pageInfo) + 20
+	lines := b.fitHeaderFooterText(b.resolvePlaceholders(text), b.options.ContentWidth()-pageInfoWidth-10)
+	lineHeight := style.FontSize * style.LineHeight
+	b.footerBand = float64(len(lines)-1) * lineHeight
+	for i, line := range lines {
+		b.pdf.SetX(b.options.Margin)
+		b.pdf.SetY(footerY - b.footerBand + float64(i)*lineHeight)
+		b.drawAligned(line, AlignLeft, hasDeferredPlaceholder(line))
+	}
+}
+
+// maxHeaderFooterLines caps how far wrapped header/footer text may grow into the page
+const maxHeaderFooterLines = 3
+
+// fitHeaderFooterText wraps or truncates header/footer text to maxWidth according to
+// Options.HeaderFooterOverflow. Wrapped text is capped at maxHeaderFooterLines lines.
+func (b *Builder) fitHeaderFooterText(text string, maxWidth float64) []string {
+	if maxWidth <= 0 || b.MeasureTextWidth(text) <= maxWidth {
+		return []string{text}
+	}
+	if strings.ToLower(b.options.HeaderFooterOverflow) == "truncate" {
+		return []string{b.truncateText(text, maxWidth)}
+	}
+
+	lines := b.wrapText(text, maxWidth)
+	if len(lines) > maxHeaderFooterLines {
+		last := strings.Join(lines[maxHeaderFooterLines-1:], " ")
+		lines = append(lines[:maxHeaderFooterLines-1], b.truncateText(last, maxWidth))
+	}
+	return lines
 }
 
-func (b *Builder) drawTextWithPlaceholders(text string, align int) {
+// resolvePlaceholders substitutes {{page}}, {{date}} and {{time}}. {{total}} is kept,
+// since it is only known when the document is saved.
+func (b *Builder) resolvePlaceholders(text string) string {
 	// Paging placeholders
-	text = strings.ReplaceAll(text, "{{page}}", fmt.Sprintf("%d", b.pageNum))
+	text = strings.ReplaceAll(text, "{{page}}", fmt.Sprintf("%d", b.displayPageNumber(b.pageNum)))
+	if strings.Contains(text, "{{section_page}}") {
+		sectionPage := b.displayPageNumber(b.pageNum)
+		if n := len(b.sections); n > 0 {
+			sectionPage = b.pageNum - b.partFirstPage(b.sections[n-1].StartPage) + 1
+		}
+		text = strings.ReplaceAll(text, "{{section_page}}", fmt.Sprintf("%d", sectionPage))
+	}
+
+	// Date placeholders, rendered in the configured timezone
+	if strings.Contains(text, "{{date}}") || strings.Contains(text, "{{time}}") {
+		now := b.createdAt.In(resolveTimezone(b.options.Timezone))
+		text = strings.ReplaceAll(text, "{{date}}", now.Format(dateLayout(b.options.DateFormat, b.options.Locale)))
+		text = strings.ReplaceAll(text, "{{time}}", now.Format("15:04"))
+	}
 	
 	// Total pages is tricky because we don't know it yet.
 	// For "Page X of Y", we usually use a template approach or write it at the end.
 	// Gopdf has a specific way to handle total pages using FillInPlaceHoldText
-	
-	hasTotal := strings.Contains(text, "{{total}}")
-	b.drawAligned(text, align, hasTotal)
+	return text
+}
+
+// displayPageNumber converts a physical page index (1-based) to the number printed on it.
+// Each part of a split output is numbered as a document of its own.
+func (b *Builder) displayPageNumber(page int) int {
+	return page - b.partStart + b.firstPageNumber - 1
+}
+
+// partFirstPage returns page, or the first page of the current part if page is in an earlier part
+func (b *Builder) partFirstPage(page int) int {
+	if page <= b.partStart {
+		return b.partStart + 1
+	}
+	return page
+}
+
+// Deferred placeholders are drawn as fixed-width boxes and filled in by Save
+const (
+	totalPlaceholder        = "{{total}}"
+	sectionTotalPlaceholder = "{{section_total}}"
+	placeholderWidth        = 20.0
+)
+
+// hasDeferredPlaceholder reports whether text contains a placeholder filled in by Save
+func hasDeferredPlaceholder(text string) bool {
+	return strings.Contains(text, totalPlaceholder) || strings.Contains(text, sectionTotalPlaceholder)
+}
+
+// placeholderName returns the gopdf placeholder name for a deferred token.
+// Each section gets its own name so it can be filled with its own page count.
+func (b *Builder) placeholderName(token string) string {
+	if token == sectionTotalPlaceholder && len(b.sections) > 0 {
+		return fmt.Sprintf("section_total_%d", len(b.sections)-1)
+	}
+	return "total"
+}
+
+// nextPlaceholder returns the position and token of the first deferred placeholder in text, or -1
+func nextPlaceholder(text string) (int, string) {
+	pos, token := -1, ""
+	for _, t := range []string{totalPlaceholder, sectionTotalPlaceholder} {
+		if i := strings.Index(text, t); i >= 0 && (pos < 0 || i < pos) {
+			pos, token = i, t
+		}
+	}
+	return pos, token
 }
 
 func (b *Builder) drawAligned(text string, align int, hasPlaceholder bool) {
@@ -200,7 +502,8 @@ func (b *Builder) drawAligned(text string, align int, hasPlaceholder bool) {
 	// Note: If hasPlaceholder, we might need to estimate
 	textWidth := b.MeasureTextWidth(text)
 	if hasPlaceholder {
-		textWidth += 20 // Estimate for number
+		count := strings.Count(text, totalPlaceholder) + strings.Count(text, sectionTotalPlaceholder)
+		textWidth += placeholderWidth * float64(count) // Estimate for number
 	}
 
 	var x float64
@@ -218,30 +521,37 @@ func (b *Builder) drawAligned(text string, align int, hasPlaceholder bool) {
 	b.pdf.SetX(x)
 	
 	if hasPlaceholder {
-		parts := strings.Split(text, "{{total}}")
-		
-		// 1. Draw Prefix (e.g. "Page 1 of ")
-		if len(parts) > 0 && parts[0] != "" {
-			b.pdf.Text(parts[0])
-			// Advance X manually
-			w, _ := b.pdf.MeasureTextWidth(parts[0])
-			x += w
+		for {
+			pos, token := nextPlaceholder(text)
+			if pos < 0 {
+				break
+			}
+			
+			// 1. Draw Prefix (e.g. "Page 1 of ")
+			if prefix := text[:pos]; prefix != "" {
+				b.pdf.Text(prefix)
+				// Advance X manually
+				w, _ := b.pdf.MeasureTextWidth(prefix)
+				x += w
+				b.pdf.SetX(x)
+			}
+			
+			// 2. Draw Placeholder
+			currentY := b.pdf.GetY()
+			b.pdf.PlaceHolderText(b.placeholderName(token), placeholderWidth)
+			
+			x += placeholderWidth // Advance X for placeholder width
 			b.pdf.SetX(x)
+			
+			// Restore Y just in case PlaceHolderText moved it (it shouldn't)
+			b.pdf.SetY(currentY)
+			
+			text = text[pos+len(token):]
 		}
 		
-		// 2. Draw Placeholder
-		currentY := b.pdf.GetY()
-		b.pdf.PlaceHolderText("total", 20)
-		
-		x += 20 // Advance X for placeholder width
-		b.pdf.SetX(x)
-		
-		// Restore Y just in case PlaceHolderText moved it (it shouldn't)
-		b.pdf.SetY(currentY)
-		
 		// 3. Draw Suffix
-		if len(parts) > 1 && parts[1] != "" {
-			b.pdf.Text(parts[1])
+		if text != "" {
+			b.pdf.Text(text)
 		}
 	} else {
 		b.pdf.Text(text)
@@ -250,6 +560,11 @@ func (b *Builder) drawAligned(text string, align int, hasPlaceholder bool) {
 
 // SetFont sets the current font
 func (b *Builder) SetFont(family string, style string, size float64) error {
+	defer b.enter()()
+	return b.setFont(family, style, size)
+}
+
+func (b *Builder) setFont(family string, style string, size float64) error {
 	if !b.fontLoaded {
 		return nil
 	}
@@ -258,16 +573,31 @@ func (b *Builder) SetFont(family string, style string, size float64) error {
 
 // SetTextColor sets the text color
 func (b *Builder) SetTextColor(c Color) {
+	defer b.enter()()
+	b.setTextColor(c)
+}
+
+func (b *Builder) setTextColor(c Color) {
 	b.pdf.SetTextColor(c.R, c.G, c.B)
 }
 
 // SetFillColor sets the fill color
 func (b *Builder) SetFillColor(c Color) {
+	defer b.enter()()
+	b.setFillColor(c)
+}
+
+func (b *Builder) setFillColor(c Color) {
 	b.pdf.SetFillColor(c.R, c.G, c.B)
 }
 
 // SetStrokeColor sets the stroke/border color
 func (b *Builder) SetStrokeColor(c Color) {
+	defer b.enter()()
+	b.setStrokeColor(c)
+}
+
+func (b *Builder) setStrokeColor(c Color) {
 	b.pdf.SetStrokeColor(c.R, c.G, c.B)
 }
 
@@ -283,6 +613,7 @@ func (b *Builder) GetY() float64 {
 
 // SetXY sets the current position
 func (b *Builder) SetXY(x, y float64) {
+	defer b.enter()()
 	b.pdf.SetX(x)
 	b.pdf.SetY(y)
 	b.currentY = y
@@ -312,7 +643,7 @@ func (b *Builder) drawWatermark() {
 			imgH := 200.0 // Default height
 			x := (pageW - imgW) / 2
 			y := (pageH - imgH) / 2
-			b.pdf.Image(b.options.WatermarkImage, x, y, &gopdf.Rect{W: imgW, H: imgH})
+			b.pdf.Image(b.scaledImage(b.options.WatermarkImage, imgW, imgH), x, y, &gopdf.Rect{W: imgW, H: imgH})
 		}
 	}
 
@@ -324,12 +655,17 @@ func (b *Builder) drawWatermark() {
 		fontSize := pageW / 10
 		b.pdf.SetFont("default", "", fontSize)
 		
+		if b.options.WatermarkTile {
+			b.drawTiledWatermark(pageW, pageH)
+			return
+		}
+
 		textWidth := b.MeasureTextWidth(b.options.WatermarkText)
 		x := (pageW - textWidth) / 2
 		y := pageH / 2
 
-		// Rotate text 45 degrees
-		b.pdf.Rotate(45, x+textWidth/2, y)
+		// Rotate text by WatermarkAngle (45 degrees by default)
+		b.pdf.Rotate(b.options.WatermarkAngle, x+textWidth/2, y)
 		b.pdf.SetX(x)
 		b.pdf.SetY(y)
 		b.pdf.Text(b.options.WatermarkText)
@@ -337,35 +673,70 @@ func (b *Builder) drawWatermark() {
 	}
 }
 
+// drawTiledWatermark repeats the watermark text over the whole page, for
+// Options.WatermarkTile: rows of copies spaced by the text's size, each row
+// shifted by half a copy, with the grid turned by WatermarkAngle around the page
+// center. The grid covers the page's diagonal so no corner is left bare at any angle.
+func (b *Builder) drawTiledWatermark(pageW, pageH float64) {
+	fontSize := pageW / 25
+	b.pdf.SetFont("default", "", fontSize)
+	textWidth := b.MeasureTextWidth(b.options.WatermarkText)
+	stepX := textWidth + fontSize*2
+	stepY := fontSize * 4
+
+	centerX, centerY := pageW/2, pageH/2
+	half := math.Hypot(pageW, pageH) / 2
+	b.pdf.Rotate(b.options.WatermarkAngle, centerX, centerY)
+	for row, y := 0, centerY-half; y <= centerY+half+stepY; row, y = row+1, y+stepY {
+		x := centerX - half - float64(row%2)*stepX/2
+		for ; x <= centerX+half; x += stepX {
+			b.pdf.SetX(x)
+			b.pdf.SetY(y)
+			b.pdf.Text(b.options.WatermarkText)
+		}
+	}
+	b.pdf.RotateReset()
+}
+
 // Cell draws a cell with text, supporting text wrapping for long content
 func (b *Builder) Cell(w, h float64, text string, style Style) error {
+	defer b.enter()()
+	return b.drawCell(w, h, text, style)
+}
+
+func (b *Builder) drawCell(w, h float64, text string, style Style) error {
+	w, h = clampSize(w), clampSize(h)
 	x := b.pdf.GetX()
 	y := b.currentY
 
 	// Draw background if specified
 	if style.HasBackground {
-		b.SetFillColor(style.FillColor)
+		b.setFillColor(style.FillColor)
 		b.pdf.Rectangle(x, y, x+w, y+h, "F", 0, 0)
 	}
 
 	// Draw border if specified
 	if style.HasBorder {
-		b.SetStrokeColor(style.BorderColor)
+		b.setStrokeColor(style.BorderColor)
 		b.pdf.SetLineWidth(style.BorderWidth)
 		b.pdf.Rectangle(x, y, x+w, y+h, "D", 0, 0)
 	}
 
 	// Draw text with padding and alignment
-	b.SetTextColor(style.TextColor)
+	b.setTextColor(style.TextColor)
 	
 	maxWidth := w - (style.Padding * 2)
-	lineHeight := style.FontSize * 1.2 // Line spacing
 	
 	// Wrap text into multiple lines if needed
-	lines := b.wrapText(text, maxWidth)
+	lines, fontSize := b.wrapCell(text, maxWidth, style)
+	if fontSize != style.FontSize {
+		b.setFont(style.FontFamily, style.FontStyle, fontSize)
+		defer b.setFont(style.FontFamily, style.FontStyle, style.FontSize)
+	}
+	lineHeight := fontSize * 1.2 // Line spacing
 	
 	// Draw each line
-	textY := y + style.Padding + style.FontSize
+	textY := y + style.Padding + fontSize
 	for i, line := range lines {
 		// Only draw lines that fit within cell height
 		lineY := textY + float64(i)*lineHeight
@@ -373,6 +744,11 @@ func (b *Builder) Cell(w, h float64, text string, style Style) error {
 			break // Stop if we exceed cell height
 		}
 		
+		if style.Alignment == AlignJustify && i < len(lines)-1 {
+			b.drawJustified(line, x+style.Padding, lineY, maxWidth)
+			continue
+		}
+
 		lineWidth := b.MeasureTextWidth(line)
 		var textX float64
 		switch style.Alignment {
@@ -380,7 +756,7 @@ func (b *Builder) Cell(w, h float64, text string, style Style) error {
 			textX = x + (w-lineWidth)/2
 		case AlignRight:
 			textX = x + w - lineWidth - style.Padding
-		default: // AlignLeft
+		default: // AlignLeft, and the last line of justified text
 			textX = x + style.Padding
 		}
 
@@ -395,9 +771,62 @@ func (b *Builder) Cell(w, h float64, text string, style Style) error {
 	return nil
 }
 
+// clampSize returns a width or height as is, or 0 when it is negative, NaN or
+// infinite. A bad column width from a layout computation then draws an empty
+// cell instead of writing NaN into the page or drawing the cell backwards.
+func clampSize(v float64) float64 {
+	if v > 0 && !math.IsInf(v, 1) {
+		return v
+	}
+	return 0
+}
+
+// drawJustified draws a wrapped line at x with the gaps between its words widened
+// so that it ends at x+width. A line without gaps is drawn as is.
+func (b *Builder) drawJustified(line string, x, y, width float64) {
+	words := strings.Fields(line)
+	if len(words) < 2 {
+		b.pdf.SetX(x)
+		b.pdf.SetY(y)
+		b.pdf.Text(line)
+		return
+	}
+
+	widths, gap := b.justifiedGap(words, width)
+	for i, word := range words {
+		b.pdf.SetX(x)
+		b.pdf.SetY(y)
+		b.pdf.Text(word)
+		x += widths[i] + gap
+	}
+}
+
+// justifiedGap returns the widths of the words of a justified line and the gap
+// between them that makes the line width wide, at least a space
+func (b *Builder) justifiedGap(words []string, width float64) ([]float64, float64) {
+	wordsWidth := 0.0
+	widths := make([]float64, len(words))
+	for i, word := range words {
+		widths[i] = b.MeasureTextWidth(word)
+		wordsWidth += widths[i]
+	}
+	gap := (width - wordsWidth) / float64(len(words)-1)
+	if space := b.MeasureTextWidth(" "); gap < space {
+		gap = space
+	}
+	return widths, gap
+}
+
 // wrapText splits text into multiple lines that fit within maxWidth
 // Optimized for memory efficiency with large text
 func (b *Builder) wrapText(text string, maxWidth float64) []string {
+	return b.wrapWords(text, maxWidth, true)
+}
+
+// wrapWords wraps text like wrapText. A word wider than maxWidth is broken across
+// lines at character boundaries when breakLong is set, and otherwise put on a line
+// of its own, cut to fit and ending in "...".
+func (b *Builder) wrapWords(text string, maxWidth float64, breakLong bool) []string {
 	if text == "" {
 		return []string{""}
 	}
@@ -450,7 +879,9 @@ func (b *Builder) wrapText(text string, maxWidth float64) []string {
 			}
 			// Check if single word is too long
 			wordWidth := b.MeasureTextWidth(word)
-			if wordWidth > maxWidth {
+			if wordWidth > maxWidth && !breakLong {
+				lines = append(lines, b.truncateText(word, maxWidth))
+			} else if wordWidth > maxWidth {
 				// Break word into chunks
 				remainder := b.breakLongWordOptimized(word, maxWidth, &lines)
 				currentLine.WriteString(remainder)
@@ -486,13 +917,93 @@ func (b *Builder) breakLongWordOptimized(word string, maxWidth float64, lines *[
 	return current.String()
 }
 
-// truncateText truncates text to fit within maxWidth (used for single-line cells)
+// Modes of Options.CellOverflow
+const (
+	CellOverflowWrap     = "wrap"
+	CellOverflowEllipsis = "ellipsis"
+	CellOverflowClip     = "clip"
+	CellOverflowShrink   = "shrink"
+)
+
+// CellOverflowMode returns how cell text wider than its column is laid out. Unset,
+// it is CellOverflowShrink with ShrinkTextToFit and CellOverflowWrap otherwise;
+// unknown values wrap.
+func (o Options) CellOverflowMode() string {
+	switch mode := strings.ToLower(strings.TrimSpace(o.CellOverflow)); mode {
+	case CellOverflowEllipsis, CellOverflowClip, CellOverflowShrink:
+		return mode
+	case "":
+		if o.ShrinkTextToFit {
+			return CellOverflowShrink
+		}
+	}
+	return CellOverflowWrap
+}
+
+// minShrinkFontSize is the smallest font CellOverflowShrink may use for a cell
+const minShrinkFontSize = 5.0
+
+// wrapCell lays out cell text in lines according to Options.CellOverflow and
+// returns the font size to draw them with. CellOverflowWrap wraps it like
+// wrapWords with Options.BreakLongWords; CellOverflowEllipsis and CellOverflowClip cut it to a single line;
+// with CellOverflowShrink, a cell whose longest word is wider than maxWidth gets
+// a smaller font (down to minShrinkFontSize) instead, and is then wrapped the
+// same way. The current font must be style's font and is left unchanged.
+func (b *Builder) wrapCell(text string, maxWidth float64, style Style) ([]string, float64) {
+	size := style.FontSize
+	switch b.options.CellOverflowMode() {
+	case CellOverflowEllipsis:
+		return []string{b.truncateText(singleLine(text), maxWidth)}, size
+	case CellOverflowClip:
+		return []string{b.clipText(singleLine(text), maxWidth)}, size
+	}
+	if b.options.CellOverflowMode() == CellOverflowShrink && maxWidth > 0 {
+		widest := 0.0
+		for _, word := range strings.Fields(text) {
+			if w := b.MeasureTextWidth(word); w > widest {
+				widest = w
+			}
+		}
+		// Text width scales linearly with the font size
+		if widest > maxWidth {
+			size = style.FontSize * maxWidth / widest
+			if size < minShrinkFontSize {
+				size = minShrinkFontSize
+			}
+		}
+	}
+	if size == style.FontSize {
+		return b.wrapWords(text, maxWidth, b.options.BreakLongWords), size
+	}
+
+	b.setFont(style.FontFamily, style.FontStyle, size)
+	lines := b.wrapWords(text, maxWidth, b.options.BreakLongWords)
+	b.setFont(style.FontFamily, style.FontStyle, style.FontSize)
+	return lines, size
+}
+
+// truncateText truncates text to fit within maxWidth, ending it in "..." (used for single-line cells)
 func (b *Builder) truncateText(text string, maxWidth float64) string {
+	return b.cutText(text, maxWidth, "...")
+}
+
+// clipText cuts text at the last character that fits within maxWidth
+func (b *Builder) clipText(text string, maxWidth float64) string {
+	return b.cutText(text, maxWidth, "")
+}
+
+// cutText shortens text that is wider than maxWidth by whole characters until it
+// fits with suffix appended
+func (b *Builder) cutText(text string, maxWidth float64, suffix string) string {
 	if !b.fontLoaded {
 		// Rough estimate: 6 points per character
 		maxChars := int(maxWidth / 6)
-		if len(text) > maxChars && maxChars > 3 {
-			return text[:maxChars-3] + "..."
+		if len(text) > maxChars && maxChars > len(suffix) {
+			cut := maxChars - len(suffix)
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+			return text[:cut] + suffix
 		}
 		return text
 	}
@@ -502,18 +1013,24 @@ func (b *Builder) truncateText(text string, maxWidth float64) string {
 		return text
 	}
 
-	// Binary search for the right length
-	for len(text) > 3 {
-		text = text[:len(text)-1]
-		width, _ = b.pdf.MeasureTextWidth(text + "...")
+	for len(text) > len(suffix) {
+		_, size := utf8.DecodeLastRuneInString(text)
+		text = text[:len(text)-size]
+		width, _ = b.pdf.MeasureTextWidth(text + suffix)
 		if width <= maxWidth {
-			return text + "..."
+			return text + suffix
 		}
 	}
 
 	return text
 }
 
+// singleLine joins the lines of multi-line cell text with spaces
+func singleLine(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(text, "\n", " ")
+}
+
 // MeasureTextWidth measures the width of text
 func (b *Builder) MeasureTextWidth(text string) float64 {
 	if !b.fontLoaded {
@@ -525,6 +1042,11 @@ func (b *Builder) MeasureTextWidth(text string) float64 {
 
 // NewLine moves to a new line
 func (b *Builder) NewLine(height float64) {
+	defer b.enter()()
+	b.newLine(height)
+}
+
+func (b *Builder) newLine(height float64) {
 	b.currentY += height
 	b.pdf.SetX(b.options.Margin)
 	b.pdf.SetY(b.currentY)
@@ -532,23 +1054,253 @@ func (b *Builder) NewLine(height float64) {
 
 // NewLineAt moves to a new line and sets X to startX
 func (b *Builder) NewLineAt(height float64, startX float64) {
+	defer b.enter()()
+	b.newLineAt(height, startX)
+}
+
+func (b *Builder) newLineAt(height float64, startX float64) {
 	b.currentY += height
 	b.pdf.SetX(startX)
 	b.pdf.SetY(b.currentY)
 }
 
+// KeepTogether starts a new page unless height points fit below the cursor. Called
+// before a heading with the height of the heading and the first line of what
+// follows, so the heading is not left alone at the bottom of a page.
+func (b *Builder) KeepTogether(height float64) {
+	defer b.enter()()
+	b.keepTogether(height)
+}
+
+func (b *Builder) keepTogether(height float64) {
+	if b.NeedsNewPage(height) {
+		b.addPage()
+	}
+}
+
 // NeedsNewPage checks if we need a new page for the given height
 func (b *Builder) NeedsNewPage(height float64) bool {
-	pageHeight := b.options.PageSize.Height
-	if b.options.Orientation == Landscape {
-		pageHeight = b.options.PageSize.Width
+	pageHeight := b.pageHeight()
+	return b.currentY+height > pageHeight-b.options.Margin-b.footerBand-b.footnoteBand
+}
+
+// zebraColor returns the fill of shaded rows: ZebraColor, else RowColor, else light gray
+func (b *Builder) zebraColor() Color {
+	switch {
+	case b.options.ZebraColor != "":
+		return ParseHexColor(b.options.ZebraColor)
+	case b.options.RowColor != "":
+		return ParseHexColor(b.options.RowColor)
+	}
+	return ColorLightGray
+}
+
+// isZebraRow reports whether the data row at rowIdx (0-based) is shaded: every
+// ZebraInterval-th row, by default every second one
+func (b *Builder) isZebraRow(rowIdx int) bool {
+	interval := b.options.ZebraInterval
+	if interval <= 0 {
+		interval = 2
+	}
+	return (rowIdx+1)%interval == 0
+}
+
+// Table border styles for Options.BorderStyle
+const (
+	BorderAll        = "all"        // A box around every cell
+	BorderOuter      = "outer"      // A frame around the table only
+	BorderHorizontal = "horizontal" // Rules between rows, no vertical lines
+	BorderNone       = "none"
+)
+
+// borderStyle returns the table border style; ShowGridLines=false means none
+func (b *Builder) borderStyle() string {
+	if !b.options.ShowGridLines {
+		return BorderNone
+	}
+	switch b.options.BorderStyle {
+	case BorderOuter, BorderHorizontal, BorderNone:
+		return b.options.BorderStyle
+	}
+	return BorderAll
+}
+
+// tableBorders draws the lines of outer and horizontal border styles. Row edges are
+// collected for the part of the table on the current page and drawn when that part
+// ends, so the lines sit on top of the row fills and each page gets a closed frame.
+type tableBorders struct {
+	style string
+	x     float64
+	width float64
+	color Color
+	line  float64
+	edges []float64 // Top of the table on this page, then the bottom of each row
+}
+
+func (b *Builder) newTableBorders(x, width float64, style Style) *tableBorders {
+	return &tableBorders{style: b.borderStyle(), x: x, width: width, color: style.BorderColor, line: style.BorderWidth}
+}
+
+// addEdge records a row boundary at y; the first one on a page is the table top
+func (t *tableBorders) addEdge(y float64) {
+	if t.style == BorderOuter || t.style == BorderHorizontal {
+		t.edges = append(t.edges, y)
+	}
+}
+
+// close draws the lines for the current page and starts over for the next one
+func (t *tableBorders) close(b *Builder) {
+	edges := t.edges
+	t.edges = nil
+	if len(edges) < 2 {
+		return
+	}
+
+	b.setStrokeColor(t.color)
+	b.pdf.SetLineWidth(t.line)
+	if t.style == BorderOuter {
+		b.pdf.Rectangle(t.x, edges[0], t.x+t.width, edges[len(edges)-1], "D", 0, 0)
+		return
+	}
+	for _, y := range edges {
+		b.pdf.Line(t.x, y, t.x+t.width, y)
+	}
+}
+
+// maxRotatedHeaderShare caps a rotated header band at this fraction of the content height
+const maxRotatedHeaderShare = 1.0 / 3
+
+// headerRowHeight returns the height of one table header row: tall enough for
+// the label that wraps onto the most lines, or, rotated, for the longest label.
+func (b *Builder) headerRowHeight(headers []string, colWidths []float64, headerStyle Style, baseLineHeight float64, rotate, span bool) float64 {
+	if b.options.HeaderHeight > 0 {
+		return b.options.HeaderHeight
+	}
+	height := baseLineHeight + (headerStyle.Padding * 2) + 4
+
+	b.setFont(headerStyle.FontFamily, headerStyle.FontStyle, headerStyle.FontSize)
+	if !rotate {
+		maxLines := 1
+		for i := 0; i < len(colWidths); i++ {
+			var header string
+			var width float64
+			header, width, i = headerCell(headers, colWidths, i, span)
+			lines, _ := b.wrapCell(header, width-(headerStyle.Padding*2), headerStyle)
+			maxLines = max(maxLines, len(lines))
+		}
+		return height + float64(maxLines-1)*headerStyle.FontSize*1.2
+	}
+	for _, header := range headers {
+		if h := b.MeasureTextWidth(header) + (headerStyle.Padding * 2) + 4; h > height {
+			height = h
+		}
+	}
+	if limit := b.options.ContentHeight() * maxRotatedHeaderShare; height > limit {
+		height = limit
 	}
-	return b.currentY+height > pageHeight-b.options.Margin
+	return height
+}
+
+// tableHeader holds the header rows of a table and their heights. Rows above the
+// last one are group headers: a label spans the blank cells to its right. With
+// Options.RotateHeaders the labels of the last row are rotated.
+type tableHeader struct {
+	rows    [][]string
+	heights []float64
+}
+
+func (b *Builder) newTableHeader(rows [][]string, colWidths []float64, headerStyle Style, baseLineHeight float64) tableHeader {
+	h := tableHeader{rows: rows}
+	for i, row := range rows {
+		last := i == len(rows)-1
+		h.heights = append(h.heights, b.headerRowHeight(row, colWidths, headerStyle, baseLineHeight, last && b.options.RotateHeaders, !last))
+	}
+	return h
+}
+
+// headerCell returns the label of header column i and its width. With span, a
+// label also covers the blank cells that follow it; last is the final column covered.
+func headerCell(headers []string, colWidths []float64, i int, span bool) (header string, width float64, last int) {
+	width = colWidths[i]
+	if i < len(headers) {
+		header = headers[i]
+	}
+	if span && strings.TrimSpace(header) != "" {
+		for i+1 < len(colWidths) && (i+1 >= len(headers) || strings.TrimSpace(headers[i+1]) == "") {
+			i++
+			width += colWidths[i]
+		}
+	}
+	return header, width, i
+}
+
+// drawTableHeader draws all header rows at startX, recording each row with borders
+func (b *Builder) drawTableHeader(h tableHeader, colWidths []float64, headerStyle Style, startX float64, borders *tableBorders) {
+	for i, row := range h.rows {
+		last := i == len(h.rows)-1
+		b.drawHeaderRow(row, colWidths, h.heights[i], headerStyle, startX, last && b.options.RotateHeaders, !last)
+		borders.addEdge(b.currentY)
+	}
+}
+
+// drawHeaderRow draws one row of header cells at startX and moves below it. With
+// span, a label also covers the blank cells that follow it. A row shorter than the
+// table, e.g. the header of data rows with extra cells, gets empty labels.
+func (b *Builder) drawHeaderRow(headers []string, colWidths []float64, height float64, headerStyle Style, startX float64, rotate, span bool) {
+	b.setFont(headerStyle.FontFamily, headerStyle.FontStyle, headerStyle.FontSize)
+	b.pdf.SetX(startX)
+	for i := 0; i < len(colWidths); i++ {
+		var header string
+		var width float64
+		header, width, i = headerCell(headers, colWidths, i, span)
+		x := b.pdf.GetX()
+		if !rotate {
+			b.drawCell(width, height, header, headerStyle)
+		} else {
+			// Background and border come from an empty cell; the label reads bottom to top,
+			// centred in the column and truncated to the band height
+			b.drawCell(width, height, "", headerStyle)
+			label := b.truncateText(header, height-(headerStyle.Padding*2))
+			b.setTextColor(headerStyle.TextColor)
+			b.rotatedText(label, x+(width+headerStyle.FontSize*0.7)/2, b.currentY+height-headerStyle.Padding, 90)
+		}
+		if !span && i < len(b.filterColumns) && b.filterColumns[i] {
+			b.drawFilterButton(x, width, height, headerStyle)
+		}
+		b.pdf.SetX(x + width)
+	}
+	b.newLineAt(height, startX)
+}
+
+// RotatedText draws text with its baseline starting at x, y, rotated counterclockwise
+// by angle degrees around that point
+func (b *Builder) RotatedText(text string, x, y, angle float64) {
+	defer b.enter()()
+	b.rotatedText(text, x, y, angle)
+}
+
+func (b *Builder) rotatedText(text string, x, y, angle float64) {
+	b.pdf.Rotate(angle, x, y)
+	b.pdf.SetX(x)
+	b.pdf.SetY(y)
+	b.pdf.Text(text)
+	b.pdf.RotateReset()
 }
 
 // DrawTable draws a complete table from data (for smaller datasets)
 // For large datasets, use DrawTableStreaming instead
 func (b *Builder) DrawTable(headers []string, rows [][]string, colWidths []float64) error {
+	defer b.enter()()
+	return b.drawTable(headers, rows, colWidths)
+}
+
+func (b *Builder) drawTable(headers []string, rows [][]string, colWidths []float64) error {
+	// All rows are at hand, so the column types can come from the table itself
+	if b.columnTypes == nil {
+		b.columnTypes = InferColumnTypes(rows)
+		defer func() { b.columnTypes = nil }()
+	}
+
 	style := DefaultStyle()
 	headerStyle := HeaderStyle()
 
@@ -573,8 +1325,8 @@ func (b *Builder) DrawTable(headers []string, rows [][]string, colWidths []float
 		style.BorderColor = c
 		headerStyle.BorderColor = c
 	}
-	style.HasBorder = b.options.ShowGridLines
-	headerStyle.HasBorder = b.options.ShowGridLines
+	style.HasBorder = b.borderStyle() == BorderAll
+	headerStyle.HasBorder = style.HasBorder
 
 	// Apply header font settings
 	if b.options.HeaderFontSize > 0 {
@@ -589,43 +1341,34 @@ func (b *Builder) DrawTable(headers []string, rows [][]string, colWidths []float
 	// Row height will be dynamic per row
 	baseLineHeight := style.FontSize * 1.2
 
-	// Calculate total table width for centering
+	// Calculate total table width for alignment
+	colWidths = b.usableColumnWidths(colWidths)
 	tableWidth := 0.0
 	for _, w := range colWidths {
 		tableWidth += w
 	}
 	
-	startX := b.options.Margin
-	contentWidth := b.options.ContentWidth()
-	if tableWidth < contentWidth {
-		startX = b.options.Margin + (contentWidth-tableWidth)/2
-	}
-
-	// Pre-calculate header height
-	var headerHeight float64
-	if b.options.HeaderHeight > 0 {
-		headerHeight = b.options.HeaderHeight
-	} else {
-		headerHeight = baseLineHeight + (headerStyle.Padding * 2) + 4
-	}
+	startX := b.tableStartX(tableWidth)
+	zebraColor := b.zebraColor()
+	borders := b.newTableBorders(startX, tableWidth, style)
+	borders.addEdge(b.currentY)
 
-	// Draw headers
+	// Header rows beyond the first (Options.HeaderRows) lead the data rows
+	var headerRows [][]string
 	if len(headers) > 0 && b.options.HeaderRow {
-		b.SetFont(headerStyle.FontFamily, headerStyle.FontStyle, headerStyle.FontSize)
-		b.pdf.SetX(startX)
-
-		for i, header := range headers {
-			if i < len(colWidths) {
-				if err := b.Cell(colWidths[i], headerHeight, header, headerStyle); err != nil {
-					return err
-				}
-			}
+		headerRows = append(headerRows, headers)
+		for len(headerRows) < b.options.HeaderRowCount() && len(rows) > 0 {
+			headerRows = append(headerRows, rows[0])
+			rows = rows[1:]
 		}
-		b.NewLineAt(headerHeight, startX)
 	}
+	header := b.newTableHeader(headerRows, colWidths, headerStyle, baseLineHeight)
+
+	// Draw headers
+	b.drawTableHeader(header, colWidths, headerStyle, startX, borders)
 
 	// Draw data rows
-	b.SetFont(style.FontFamily, style.FontStyle, style.FontSize)
+	b.setFont(style.FontFamily, style.FontStyle, style.FontSize)
 	totalRows := len(rows)
 	lastProgress := -1
 	
@@ -647,15 +1390,13 @@ func (b *Builder) DrawTable(headers []string, rows [][]string, colWidths []float
 
 		// Reuse rowStyle, only modify when needed
 		rowStyle := style
-		if rowIdx%2 == 1 {
-			if b.options.RowColor != "" {
-				rowStyle.FillColor = ParseHexColor(b.options.RowColor)
-			} else {
-				rowStyle.FillColor = ColorLightGray
-			}
+		if b.isZebraRow(rowIdx) {
+			rowStyle.FillColor = zebraColor
 			rowStyle.HasBackground = true
 		}
 		
+		row = b.localizeRow(row)
+
 		// Calculate row height
 		var currentRowHeight float64
 		if b.options.RowHeight > 0 {
@@ -664,7 +1405,7 @@ func (b *Builder) DrawTable(headers []string, rows [][]string, colWidths []float
 			maxLines := 1
 			for i, cell := range row {
 				if i < len(maxWidths) {
-					lines := b.wrapText(cell, maxWidths[i])
+					lines, _ := b.wrapCell(cell, maxWidths[i], style)
 					if len(lines) > maxLines {
 						maxLines = len(lines)
 					}
@@ -675,18 +1416,13 @@ func (b *Builder) DrawTable(headers []string, rows [][]string, colWidths []float
 
 		// Check for new page
 		if b.NeedsNewPage(currentRowHeight) {
-			b.AddPage()
+			borders.close(b)
+			b.addPage()
+			borders.addEdge(b.currentY)
 			// Re-draw headers on new page
-			if b.options.HeaderRow && len(headers) > 0 {
-				b.SetFont(headerStyle.FontFamily, headerStyle.FontStyle, headerStyle.FontSize)
-				b.pdf.SetX(startX)
-				for i, header := range headers {
-					if i < len(colWidths) {
-						b.Cell(colWidths[i], headerHeight, header, headerStyle)
-					}
-				}
-				b.NewLineAt(headerHeight, startX)
-				b.SetFont(style.FontFamily, style.FontStyle, style.FontSize)
+			if len(header.rows) > 0 {
+				b.drawTableHeader(header, colWidths, headerStyle, startX, borders)
+				b.setFont(style.FontFamily, style.FontStyle, style.FontSize)
 			}
 		}
 		
@@ -695,54 +1431,632 @@ func (b *Builder) DrawTable(headers []string, rows [][]string, colWidths []float
 		for i, cell := range row {
 			if i < len(colWidths) {
 				cellStyle := rowStyle
-				if isNumeric(cell) {
-					cellStyle.Alignment = AlignRight
-				}
-				if err := b.Cell(colWidths[i], currentRowHeight, cell, cellStyle); err != nil {
+				cellStyle.Alignment = b.cellAlignment(i, cell, rowStyle.Alignment)
+				if err := b.tableCell(colWidths[i], currentRowHeight, cell, cellStyle); err != nil {
 					return err
 				}
 			}
 		}
-		b.NewLineAt(currentRowHeight, startX)
+		b.newLineAt(currentRowHeight, startX)
+		borders.addEdge(b.currentY)
 	}
+	borders.close(b)
 
 	return nil
 }
 
-// AddText adds a text paragraph
+// AddText adds a text paragraph at the left margin. Newlines start new lines, long
+// lines wrap at the content width and pages break as needed.
 func (b *Builder) AddText(text string, style Style) error {
-	b.SetFont(style.FontFamily, style.FontStyle, style.FontSize)
-	b.SetTextColor(style.TextColor)
-	b.pdf.SetX(b.options.Margin)
+	defer b.enter()()
+	return b.addText(text, style)
+}
+
+func (b *Builder) addText(text string, style Style) error {
+	for _, line := range strings.Split(text, "\n") {
+		if err := b.addTextLine(strings.TrimSuffix(line, "\r"), "", 0, style); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// textColumnGap is the space between text columns
+const textColumnGap = 18.0
+
+// BeginTextColumns makes text added with AddText and AddTextLine flow into
+// Options.TextColumns columns, starting at the current line: a full column continues
+// at the top of the next one and the last one on a new page.
+func (b *Builder) BeginTextColumns() {
+	defer b.enter()()
+	if b.options.TextColumns < 2 {
+		return
+	}
+	b.inColumns = true
+	b.column, b.columnTop, b.columnBottom = 0, b.currentY, b.currentY
+}
+
+// EndTextColumns returns to full-width text below the longest column on the page
+func (b *Builder) EndTextColumns() {
+	defer b.enter()()
+	if !b.inColumns {
+		return
+	}
+	b.inColumns = false
+	if b.columnBottom > b.currentY {
+		b.currentY = b.columnBottom
+	}
+	b.column = 0
+	b.newLine(0)
+}
+
+// textArea returns the left edge and width available to text at the cursor: the
+// current column's, or the content width outside of text columns
+func (b *Builder) textArea() (float64, float64) {
+	if !b.inColumns {
+		return b.options.Margin, b.options.ContentWidth()
+	}
+	n := float64(b.options.TextColumns)
+	width := (b.options.ContentWidth() - textColumnGap*(n-1)) / n
+	return b.options.Margin + float64(b.column)*(width+textColumnGap), width
+}
+
+// nextColumn moves the cursor to the top of the next text column on the page and
+// reports whether there was one
+func (b *Builder) nextColumn() bool {
+	if !b.inColumns || b.column+1 >= b.options.TextColumns {
+		return false
+	}
+	if b.currentY > b.columnBottom {
+		b.columnBottom = b.currentY
+	}
+	b.column++
+	b.currentY = b.columnTop
+	return true
+}
+
+// AddParagraph adds text like AddText followed by Options.ParagraphSpacing
+func (b *Builder) AddParagraph(text string, style Style) error {
+	defer b.enter()()
+	if err := b.addText(text, style); err != nil {
+		return err
+	}
+	b.newLine(b.options.ParagraphSpacing)
+	return nil
+}
+
+// AddEmptyMessage draws text centred in gray below the cursor, as the placeholder
+// for a table without data rows. Nothing is drawn for empty text.
+func (b *Builder) AddEmptyMessage(text string) {
+	defer b.enter()()
+	if text == "" {
+		return
+	}
+	style := DefaultStyle()
+	style.FontSize = b.options.FontSize + 2
+	b.setFont(style.FontFamily, style.FontStyle, style.FontSize)
+	b.setTextColor(ColorGray)
+
+	b.newLine(style.FontSize * 2)
+	if b.NeedsNewPage(style.FontSize) {
+		b.addPage()
+		b.setFont(style.FontFamily, style.FontStyle, style.FontSize)
+		b.setTextColor(ColorGray)
+	}
+	b.pdf.SetX(b.options.Margin + (b.options.ContentWidth()-b.MeasureTextWidth(text))/2)
 	b.pdf.SetY(b.currentY)
 	b.pdf.Text(text)
-	b.NewLine(style.FontSize * style.LineHeight)
+	b.newLine(style.FontSize)
+}
+
+// sectionBarHeight is the height of the bar drawn by DrawSectionBar
+const sectionBarHeight = 6.0
+
+// DrawSectionBar draws a thin bar in color c across the top edge of the current page,
+// marking the start of a section
+func (b *Builder) DrawSectionBar(c Color) {
+	defer b.enter()()
+	page := b.options.GetPageRect()
+	b.setFillColor(c)
+	b.pdf.Rectangle(0, 0, page.W, sectionBarHeight, "F", 0, 0)
+}
+
+// AddCaption draws text as a bold line above a table, with some space around it
+func (b *Builder) AddCaption(text string) error {
+	defer b.enter()()
+	return b.addCaption(text)
+}
+
+func (b *Builder) addCaption(text string) error {
+	style := DefaultStyle()
+	style.FontStyle = "B"
+	style.FontSize = b.options.FontSize + 2
+
+	// Keep the caption on the page of the table's first row
+	rowHeight := b.options.RowHeight
+	if rowHeight <= 0 {
+		rowHeight = b.options.FontSize*1.2 + b.options.CellPadding*2 + 4
+	}
+	b.keepTogether(style.FontSize*1.5 + b.options.TextLineHeight(style.FontSize, style) + rowHeight)
+
+	// AddText draws on the baseline at the cursor, so leave room above it
+	b.newLine(style.FontSize)
+	if err := b.addText(text, style); err != nil {
+		return err
+	}
+	b.newLine(style.FontSize / 2)
+	return nil
+}
+
+// AddTextLine adds one line of text, wrapped to the content width or to the current
+// text column. The gutter text (e.g. a line number) is drawn at the left edge on the
+// first row and the line starts gutterWidth points to its right. Leading indentation
+// is kept, also on wrapped rows; tabs count as four spaces. With AlignJustify every
+// row but the last is stretched to the full width.
+func (b *Builder) AddTextLine(line, gutter string, gutterWidth float64, style Style) error {
+	defer b.enter()()
+	return b.addTextLine(line, gutter, gutterWidth, style)
+}
+
+func (b *Builder) addTextLine(line, gutter string, gutterWidth float64, style Style) error {
+	b.setFont(style.FontFamily, style.FontStyle, style.FontSize)
+	b.setTextColor(style.TextColor)
+	lineHeight := b.options.TextLineHeight(style.FontSize, style)
+
+	line = strings.ReplaceAll(line, "\t", "    ")
+	body := strings.TrimLeft(line, " ")
+	areaX, areaWidth := b.textArea()
+	textX := areaX + gutterWidth
+	maxWidth := areaWidth - gutterWidth
+	if indent := b.MeasureTextWidth(line[:len(line)-len(body)]); indent < maxWidth/2 {
+		// Deeper indents would leave too little room, so they are dropped
+		textX += indent
+		maxWidth -= indent
+	}
+
+	rows := b.wrapText(body, maxWidth)
+	for i, row := range rows {
+		if b.NeedsNewPage(lineHeight) {
+			// Continue in the next column, or in the first one on a new page
+			oldX := areaX
+			if !b.nextColumn() {
+				b.addPage()
+				// Header and footer leave their own font behind
+				b.setFont(style.FontFamily, style.FontStyle, style.FontSize)
+				b.setTextColor(style.TextColor)
+			}
+			areaX, _ = b.textArea()
+			textX += areaX - oldX
+		}
+		if i == 0 && gutter != "" {
+			b.pdf.SetX(areaX)
+			b.pdf.SetY(b.currentY)
+			b.pdf.Text(gutter)
+		}
+		if style.Alignment == AlignJustify && i < len(rows)-1 {
+			b.drawJustified(row, textX, b.currentY, maxWidth)
+		} else if row != "" {
+			b.pdf.SetX(textX)
+			b.pdf.SetY(b.currentY)
+			b.pdf.Text(row)
+		}
+		b.newLine(lineHeight)
+	}
 	return nil
 }
 
+// AddTextBlock draws text wrapped within the box at x, y of size w x h, such as a
+// slide's text frame, and returns the height it took. Each line of text is a
+// paragraph, aligned per style.Alignment and Options.ParagraphSpacing apart. Lines
+// that don't fit in the box are left out; with Options.ShrinkTextToFit the font is
+// made smaller instead, down to the smallest size cells shrink to. The cursor
+// doesn't move, and the font is left as drawn.
+func (b *Builder) AddTextBlock(x, y, w, h float64, text string, style Style) float64 {
+	defer b.enter()()
+	return b.addTextBlock(x, y, w, h, RichText{Text: text}, style)
+}
+
+// AddRichTextBlock is AddTextBlock with links and formatting over parts of the
+// text. A word is covered by a link when it overlaps the link's byte range.
+func (b *Builder) AddRichTextBlock(x, y, w, h float64, text RichText, style Style) float64 {
+	defer b.enter()()
+	return b.addTextBlock(x, y, w, h, text, style)
+}
+
+func (b *Builder) addTextBlock(x, y, w, h float64, text RichText, style Style) float64 {
+	size := style.FontSize
+	paras := b.wrapTextBlock(text, w, style, size)
+	for b.options.ShrinkTextToFit && size > minShrinkFontSize && b.textBlockHeight(paras, size, style) > h {
+		size *= 0.9
+		if size < minShrinkFontSize {
+			size = minShrinkFontSize
+		}
+		paras = b.wrapTextBlock(text, w, style, size)
+	}
+	b.setTextColor(style.TextColor)
+	flags := fontStyleFlags(style.FontStyle)
+	b.setStyledFont(flags, size)
+
+	lineHeight := b.options.TextLineHeight(size, style)
+	used := 0.0
+	paraStart := 0
+	for p, para := range strings.Split(text.Text, "\n") {
+		if p > 0 {
+			used += b.options.ParagraphSpacing
+		}
+		lines, pos := paras[p], 0
+		for i, line := range lines {
+			if used+lineHeight > h {
+				return used
+			}
+			top, baseline := y+used, y+used+size
+			used += lineHeight
+			justify := style.Alignment == AlignJustify && i < len(lines)-1
+			if line.pieces != nil {
+				b.drawRichLine(line.pieces, x, top, baseline, w, lineHeight, style.Alignment, justify, size, text.Links)
+				b.setStyledFont(flags, size)
+				continue
+			}
+			if line.text == "" {
+				continue
+			}
+			justifyWidth, lineX := 0.0, x
+			switch lineWidth := b.MeasureTextWidth(line.text); {
+			case justify:
+				justifyWidth = w
+			case style.Alignment == AlignCenter:
+				lineX = x + (w-lineWidth)/2
+			case style.Alignment == AlignRight:
+				lineX = x + w - lineWidth
+			}
+			if justifyWidth > 0 {
+				b.drawJustified(line.text, x, baseline, w)
+			} else {
+				b.pdf.SetX(lineX)
+				b.pdf.SetY(baseline)
+				b.pdf.Text(line.text)
+			}
+			if len(text.Links) > 0 {
+				pos = b.addLineLinks(line.text, para, paraStart, pos, lineX, top, lineHeight, justifyWidth, text.Links)
+			}
+		}
+		paraStart += len(para) + 1
+	}
+	return used
+}
+
+// blockLine is a line of a text block: a line of a paragraph without formatting,
+// drawn as is, or the pieces of a line of a formatted one
+type blockLine struct {
+	text   string
+	pieces []textPiece
+}
+
+// wrapTextBlock wraps each line of text to width in the font of style at size.
+// Paragraphs that text.Formats touch are laid out piece by piece (wrapRichParagraph).
+func (b *Builder) wrapTextBlock(text RichText, width float64, style Style, size float64) [][]blockLine {
+	flags := fontStyleFlags(style.FontStyle)
+	b.setStyledFont(flags, size)
+	var paras [][]blockLine
+	start := 0
+	for _, raw := range strings.Split(text.Text, "\n") {
+		para := strings.TrimSuffix(raw, "\r")
+		var lines []blockLine
+		if formatted(text.Formats, start, start+len(para)) {
+			for _, pieces := range b.wrapRichParagraph(para, start, text.Formats, flags, width, size) {
+				lines = append(lines, blockLine{pieces: pieces})
+			}
+			b.setStyledFont(flags, size)
+		} else {
+			for _, line := range b.wrapText(para, width) {
+				lines = append(lines, blockLine{text: line})
+			}
+		}
+		paras = append(paras, lines)
+		start += len(raw) + 1
+	}
+	return paras
+}
+
+// textBlockHeight returns the height of paragraphs laid out by wrapTextBlock at size
+func (b *Builder) textBlockHeight(paras [][]blockLine, size float64, style Style) float64 {
+	height := float64(len(paras)-1) * b.options.ParagraphSpacing
+	for _, lines := range paras {
+		height += float64(len(lines)) * b.options.TextLineHeight(size, style)
+	}
+	return height
+}
+
 // AddImage adds an image from file
 func (b *Builder) AddImage(imagePath string, x, y, w, h float64) error {
-	return b.pdf.Image(imagePath, x, y, &gopdf.Rect{W: w, H: h})
+	defer b.enter()()
+	return b.addImage(imagePath, x, y, w, h)
+}
+
+func (b *Builder) addImage(imagePath string, x, y, w, h float64) error {
+	return b.pdf.Image(b.scaledImage(imagePath, w, h), x, y, &gopdf.Rect{W: w, H: h})
+}
+
+// Image fit modes for AddImageFitted
+const (
+	ImageFit  = "fit"  // Scale to fit inside the box, keeping the whole image visible
+	ImageFill = "fill" // Scale to cover the box, cropping what overflows it
+)
+
+// AddImageFitted draws an image centred in the box at x, y of size w x h, scaled
+// according to mode while keeping its aspect ratio
+func (b *Builder) AddImageFitted(imagePath string, x, y, w, h float64, mode string) error {
+	defer b.enter()()
+	imgW, imgH, err := ImageSize(imagePath)
+	if err != nil {
+		return err
+	}
+
+	scale := w / imgW
+	if mode == ImageFill {
+		if h/imgH > scale {
+			scale = h / imgH
+		}
+	} else if h/imgH < scale {
+		scale = h / imgH
+	}
+	drawW, drawH := imgW*scale, imgH*scale
+	imagePath = b.scaledImage(imagePath, drawW, drawH)
+
+	if mode == ImageFill {
+		holder, err := gopdf.ImageHolderByPath(imagePath)
+		if err != nil {
+			return err
+		}
+		return b.pdf.ImageByHolderWithOptions(holder, gopdf.ImageOptions{
+			X:    x,
+			Y:    y,
+			Rect: &gopdf.Rect{W: drawW, H: drawH},
+			Crop: &gopdf.CropOptions{X: (drawW - w) / 2, Y: (drawH - h) / 2, Width: w, Height: h},
+		})
+	}
+	return b.pdf.Image(imagePath, x+(w-drawW)/2, y+(h-drawH)/2, &gopdf.Rect{W: drawW, H: drawH})
+}
+
+// ImageSize returns the pixel dimensions of a PNG or JPEG file without decoding it
+func ImageSize(imagePath string) (float64, float64, error) {
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	cfg, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return 0, 0, err
+	}
+	if cfg.Width == 0 || cfg.Height == 0 {
+		return 0, 0, fmt.Errorf("image has no pixels")
+	}
+	return float64(cfg.Width), float64(cfg.Height), nil
+}
+
+// ContentArea returns the space left for content on the current page: between the
+// cursor (below any header) and the footer, within the side margins
+func (b *Builder) ContentArea() (x, y, w, h float64) {
+	pageHeight := b.pageHeight()
+	return b.options.Margin, b.currentY, b.options.ContentWidth(), pageHeight - b.options.Margin - b.footerBand - b.footnoteBand - b.currentY
 }
 
 // Save writes the PDF to the specified path
 func (b *Builder) Save(outputPath string) error {
+	defer b.enter()()
 	// Ensure output directory exists
 	dir := filepath.Dir(outputPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
 	
-	// Fill in total page count
-	// Fill in total page count
+	if err := b.drawLegend(); err != nil {
+		return err
+	}
+	b.stampLastPage()
+	if b.err != nil {
+		return b.err
+	}
+	b.drawFootnotes()
+	b.fillTotals()
+
+	if len(b.parts) == 0 {
+		b.outputFiles = []string{outputPath}
+		if err := b.pdf.WritePdf(outputPath); err != nil {
+			return err
+		}
+		return b.annotatePart(outputPath)
+	}
+
+	b.outputFiles = nil
+	for i, part := range b.parts {
+		path := partPath(outputPath, i+1)
+		if err := moveFile(part, path); err != nil {
+			return err
+		}
+		b.outputFiles = append(b.outputFiles, path)
+	}
+	b.parts = nil
+
+	path := partPath(outputPath, len(b.outputFiles)+1)
+	if err := b.pdf.WritePdf(path); err != nil {
+		return err
+	}
+	if err := b.annotatePart(path); err != nil {
+		return err
+	}
+	b.outputFiles = append(b.outputFiles, path)
+	return nil
+}
+
+// Close removes finished parts that Save hasn't moved into place, e.g. when the
+// conversion failed half way. It is safe to call after Save.
+func (b *Builder) Close() {
+	defer b.enter()()
+	for _, part := range b.parts {
+		os.Remove(part)
+	}
+	b.parts = nil
+	for key, path := range b.scaledImages {
+		if !strings.HasPrefix(key, path+"@") {
+			os.Remove(path)
+		}
+	}
+	b.scaledImages = nil
+}
+
+// moveFile renames src to dst, copying when they are on different filesystems
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+// fillTotals fills the {{total}} and {{section_total}} placeholders of the current part
+func (b *Builder) fillTotals() {
 	// IMPORTANT: Set font to match the footer style so the numbers align correctly
 	// The footer uses default font, size 8, Gray color
 	b.pdf.SetFont("default", "", 8)
 	b.pdf.SetTextColor(128, 128, 128) // ColorGray approx
 	
-	b.pdf.FillInPlaceHoldText("total", fmt.Sprintf("%d", b.pageNum), gopdf.Left)
-	
-	return b.pdf.WritePdf(outputPath)
+	// Placeholders that were never drawn return an error, which is safe to ignore
+	b.pdf.FillInPlaceHoldText("total", fmt.Sprintf("%d", b.displayPageNumber(b.pageNum)), gopdf.Left)
+	b.closeSection()
+	for i, section := range b.sections {
+		if section.EndPage <= b.partStart {
+			continue
+		}
+		pages := section.EndPage - b.partFirstPage(section.StartPage) + 1
+		b.pdf.FillInPlaceHoldText(fmt.Sprintf("section_total_%d", i), fmt.Sprintf("%d", pages), gopdf.Left)
+	}
+}
+
+// splitIfFull finishes the current part when it has Options.MaxPagesPerFile pages and
+// starts a new document for the next page. gopdf keeps every page of a document in
+// memory until it is written, so finished parts go straight to a temp file in
+// Options.TempDir: peak memory then depends on MaxPagesPerFile, not on the total length.
+func (b *Builder) splitIfFull() {
+	max := b.options.MaxPagesPerFile
+	if max <= 0 || b.pageNum-b.partStart < max || b.err != nil {
+		return
+	}
+
+	b.fillTotals()
+	tmp, err := os.CreateTemp(b.options.TempDir, "gopdfconv-part-*.pdf")
+	if err != nil {
+		b.err = err
+		return
+	}
+	tmp.Close()
+	b.parts = append(b.parts, tmp.Name())
+	if err := b.pdf.WritePdf(tmp.Name()); err != nil {
+		b.err = err
+		return
+	}
+	if err := b.annotatePart(tmp.Name()); err != nil {
+		b.err = err
+		return
+	}
+	b.partStart = b.pageNum
+
+	b.pdf = &gopdf.GoPdf{}
+	b.pdf.Start(gopdf.Config{PageSize: *b.options.GetPageRect()})
+	b.docPageSize = *b.options.GetPageRect()
+	b.faces = nil
+	if err := b.loadFont(); err != nil {
+		b.err = err
+	}
+}
+
+// partPath returns the path of part n of a split output: report.pdf -> report_part2.pdf
+func partPath(outputPath string, n int) string {
+	ext := filepath.Ext(outputPath)
+	return fmt.Sprintf("%s_part%d%s", strings.TrimSuffix(outputPath, ext), n, ext)
+}
+
+// tableStartX returns the left edge of a table according to Options.TableAlign.
+// Tables as wide as the content area always start at the left margin.
+func (b *Builder) tableStartX(tableWidth float64) float64 {
+	startX := b.options.Margin
+	contentWidth := b.options.ContentWidth()
+	if tableWidth >= contentWidth {
+		return startX
+	}
+
+	switch strings.ToLower(b.options.TableAlign) {
+	case "center":
+		startX += (contentWidth - tableWidth) / 2
+	case "right":
+		startX += contentWidth - tableWidth
+	}
+	return startX
+}
+
+// localizeRow applies the configured locale's number formatting to the cells of
+// number columns in a data row, also with Options.PerCellAlignment; without column
+// types nothing is formatted. A copy is returned so the caller's row data is never
+// modified.
+func (b *Builder) localizeRow(row []string) []string {
+	if b.options.Locale == "" {
+		return row
+	}
+	localized := make([]string, len(row))
+	for i, cell := range row {
+		if i >= len(b.columnTypes) || b.columnTypes[i] != ColumnNumber {
+			// Numbers elsewhere are years, codes or IDs
+			localized[i] = cell
+			continue
+		}
+		localized[i] = localizeNumericText(cell, b.options.Locale)
+	}
+	return localized
+}
+
+// byColumnType reports whether table cells are aligned and formatted by their
+// column's type rather than by their own content (Options.PerCellAlignment)
+func (b *Builder) byColumnType() bool {
+	return b.columnTypes != nil && !b.options.PerCellAlignment
+}
+
+// cellAlignment returns the alignment of a data cell in column col: by the column's
+// type, else right for numbers and fallback otherwise
+func (b *Builder) cellAlignment(col int, cell string, fallback int) int {
+	if !b.byColumnType() {
+		if isNumeric(cell) {
+			return AlignRight
+		}
+		return fallback
+	}
+	if col < len(b.columnTypes) {
+		switch b.columnTypes[col] {
+		case ColumnNumber, ColumnCurrency:
+			return AlignRight
+		case ColumnDate:
+			return AlignCenter
+		}
+	}
+	return fallback
 }
 
 // isNumeric checks if a string represents a number
@@ -751,6 +2065,9 @@ func isNumeric(s string) bool {
 	if s == "" {
 		return false
 	}
+	if isCodeText(strings.TrimLeft(s, "+-")) {
+		return false // IDs such as 00123 stay left-aligned
+	}
 	// Simple check for digits, decimal point, and signs
 	hasDigit := false
 	for _, r := range s {
@@ -758,7 +2075,7 @@ func isNumeric(s string) bool {
 			hasDigit = true
 			continue
 		}
-		if r == '.' || r == '-' || r == '+' || r == ',' {
+		if r == '.' || r == '-' || r == '+' || r == ',' || r == '\u00a0' {
 			continue
 		}
 		return false
@@ -777,8 +2094,44 @@ type RowIterator interface {
 	Columns() ([]string, error)
 }
 
+// CellFiller is optionally implemented by a RowIterator whose cells carry their own
+// background (e.g. Excel conditional formatting). CellFills returns the fills of the
+// current row by column index, or nil.
+type CellFiller interface {
+	CellFills() map[int]Color
+}
+
+// CellCommenter is optionally implemented by a RowIterator whose cells carry
+// comments (e.g. Excel notes), drawn per Options.RenderComments. CellComments
+// returns the comments of the current row by column index, or nil.
+type CellCommenter interface {
+	CellComments() map[int]string
+}
+
+// usableColumnWidths returns colWidths with zero, negative, NaN or infinite
+// widths, which would draw degenerate cells, raised to the smallest width a column
+// is scaled to
+func (b *Builder) usableColumnWidths(colWidths []float64) []float64 {
+	floor := b.options.ScaledColumnFloor()
+	var widths []float64
+	for i, w := range colWidths {
+		if clampSize(w) > 0 {
+			continue
+		}
+		if widths == nil {
+			widths = append([]float64(nil), colWidths...)
+		}
+		widths[i] = floor
+	}
+	if widths == nil {
+		return colWidths
+	}
+	return widths
+}
+
 // DrawTableStreaming draws a table from streaming row data (memory efficient)
 func (b *Builder) DrawTableStreaming(headers []string, rows RowIterator, colWidths []float64, hasHeaderRow bool) error {
+	defer b.enter()()
 	style := DefaultStyle()
 	headerStyle := HeaderStyle()
 
@@ -803,8 +2156,8 @@ func (b *Builder) DrawTableStreaming(headers []string, rows RowIterator, colWidt
 		style.BorderColor = c
 		headerStyle.BorderColor = c
 	}
-	style.HasBorder = b.options.ShowGridLines
-	headerStyle.HasBorder = b.options.ShowGridLines
+	style.HasBorder = b.borderStyle() == BorderAll
+	headerStyle.HasBorder = style.HasBorder
 
 	if b.options.HeaderFontSize > 0 {
 		headerStyle.FontSize = b.options.HeaderFontSize
@@ -818,41 +2171,44 @@ func (b *Builder) DrawTableStreaming(headers []string, rows RowIterator, colWidt
 	baseLineHeight := style.FontSize * 1.2
 
 	// Calculate table positioning
+	colWidths = b.usableColumnWidths(colWidths)
 	tableWidth := 0.0
 	for _, w := range colWidths {
 		tableWidth += w
 	}
 	
-	startX := b.options.Margin
-	contentWidth := b.options.ContentWidth()
-	if tableWidth < contentWidth {
-		startX = b.options.Margin + (contentWidth-tableWidth)/2
-	}
-
-	// Calculate header height
-	var headerHeight float64
-	if b.options.HeaderHeight > 0 {
-		headerHeight = b.options.HeaderHeight
-	} else {
-		headerHeight = baseLineHeight + (headerStyle.Padding * 2) + 4
-	}
-
-	// Draw headers if provided
-	if len(headers) > 0 && hasHeaderRow {
-		b.SetFont(headerStyle.FontFamily, headerStyle.FontStyle, headerStyle.FontSize)
-		b.pdf.SetX(startX)
-		for i, header := range headers {
-			if i < len(colWidths) {
-				b.Cell(colWidths[i], headerHeight, header, headerStyle)
+	startX := b.tableStartX(tableWidth)
+	zebraColor := b.zebraColor()
+	borders := b.newTableBorders(startX, tableWidth, style)
+	borders.addEdge(b.currentY)
+
+	// The stream starts with the header rows; headers is the first of them
+	var headerRows [][]string
+	if hasHeaderRow {
+		for len(headerRows) < b.options.HeaderRowCount() && rows.Next() {
+			row, err := rows.Columns()
+			if err != nil {
+				continue
 			}
+			if len(headerRows) == 0 {
+				row = headers
+			}
+			headerRows = append(headerRows, row)
+		}
+		if len(headers) == 0 {
+			headerRows = nil
 		}
-		b.NewLineAt(headerHeight, startX)
 	}
+	header := b.newTableHeader(headerRows, colWidths, headerStyle, baseLineHeight)
+
+	// Draw headers if provided
+	b.drawTableHeader(header, colWidths, headerStyle, startX, borders)
 
 	// Stream rows
-	b.SetFont(style.FontFamily, style.FontStyle, style.FontSize)
+	b.setFont(style.FontFamily, style.FontStyle, style.FontSize)
 	rowIdx := 0
-	skipFirst := hasHeaderRow // Skip first row if it's the header
+	filler, _ := rows.(CellFiller)
+	commenter, _ := rows.(CellCommenter)
 
 	for rows.Next() {
 		row, err := rows.Columns()
@@ -860,27 +2216,19 @@ func (b *Builder) DrawTableStreaming(headers []string, rows RowIterator, colWidt
 			continue
 		}
 
-		// Skip header row in data
-		if skipFirst {
-			skipFirst = false
-			continue
-		}
-
 		// Progress reporting every 1000 rows
 		if b.onProgress != nil && rowIdx%1000 == 0 {
 			b.onProgress(rowIdx / 100) // Approximate progress
 		}
 
 		rowStyle := style
-		if rowIdx%2 == 1 {
-			if b.options.RowColor != "" {
-				rowStyle.FillColor = ParseHexColor(b.options.RowColor)
-			} else {
-				rowStyle.FillColor = ColorLightGray
-			}
+		if b.isZebraRow(rowIdx) {
+			rowStyle.FillColor = zebraColor
 			rowStyle.HasBackground = true
 		}
 
+		row = b.localizeRow(row)
+
 		// Calculate row height
 		var currentRowHeight float64
 		if b.options.RowHeight > 0 {
@@ -890,7 +2238,7 @@ func (b *Builder) DrawTableStreaming(headers []string, rows RowIterator, colWidt
 			for i, cell := range row {
 				if i < len(colWidths) {
 					maxWidth := colWidths[i] - (style.Padding * 2)
-					lines := b.wrapText(cell, maxWidth)
+					lines, _ := b.wrapCell(cell, maxWidth, style)
 					if len(lines) > maxLines {
 						maxLines = len(lines)
 					}
@@ -899,36 +2247,53 @@ func (b *Builder) DrawTableStreaming(headers []string, rows RowIterator, colWidt
 			currentRowHeight = (baseLineHeight * float64(maxLines)) + (style.Padding * 2) + 4
 		}
 
+		var comments map[int]string
+		if commenter != nil {
+			comments = commenter.CellComments()
+		}
+		notesHeight := b.footnotesHeight(comments, len(colWidths))
+		if notesHeight > 0 {
+			b.setFont(style.FontFamily, style.FontStyle, style.FontSize)
+		}
+
 		// Check for new page
-		if b.NeedsNewPage(currentRowHeight) {
-			b.AddPage()
+		if b.NeedsNewPage(currentRowHeight + notesHeight) {
+			borders.close(b)
+			b.addPage()
+			borders.addEdge(b.currentY)
 			// Redraw headers
-			if len(headers) > 0 && hasHeaderRow {
-				b.SetFont(headerStyle.FontFamily, headerStyle.FontStyle, headerStyle.FontSize)
-				b.pdf.SetX(startX)
-				for i, header := range headers {
-					if i < len(colWidths) {
-						b.Cell(colWidths[i], headerHeight, header, headerStyle)
-					}
-				}
-				b.NewLineAt(headerHeight, startX)
-				b.SetFont(style.FontFamily, style.FontStyle, style.FontSize)
+			if len(header.rows) > 0 {
+				b.drawTableHeader(header, colWidths, headerStyle, startX, borders)
+				b.setFont(style.FontFamily, style.FontStyle, style.FontSize)
 			}
 		}
 
+		var fills map[int]Color
+		if filler != nil {
+			fills = filler.CellFills()
+		}
+
 		b.pdf.SetX(startX)
 		for i, cell := range row {
 			if i < len(colWidths) {
 				cellStyle := rowStyle
-				if isNumeric(cell) {
-					cellStyle.Alignment = AlignRight
+				cellStyle.Alignment = b.cellAlignment(i, cell, rowStyle.Alignment)
+				if fill, ok := fills[i]; ok {
+					cellStyle.FillColor = fill
+					cellStyle.HasBackground = true
+				}
+				cellX := b.pdf.GetX()
+				b.tableCell(colWidths[i], currentRowHeight, cell, cellStyle)
+				if comment := comments[i]; comment != "" {
+					b.markComment(cellX, colWidths[i], comment, cellStyle)
 				}
-				b.Cell(colWidths[i], currentRowHeight, cell, cellStyle)
 			}
 		}
-		b.NewLineAt(currentRowHeight, startX)
+		b.newLineAt(currentRowHeight, startX)
+		borders.addEdge(b.currentY)
 		rowIdx++
 	}
+	borders.close(b)
 
 	return nil
 }
@@ -0,0 +1,150 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// headerBand draws a table of one short data row under headers and returns how
+// far it moved down the page, less the same table under one-word headers: the
+// extra height of the header band
+func headerBand(t *testing.T, opts Options, headers []string) float64 {
+	t.Helper()
+	drawn := func(headers []string) float64 {
+		b, err := NewBuilder(opts)
+		if err != nil {
+			t.Fatalf("NewBuilder: %v", err)
+		}
+		b.AddPage()
+		top := b.GetY()
+		if err := b.DrawTable(headers, [][]string{{"1", "2"}}, []float64{60, 100}); err != nil {
+			t.Fatalf("DrawTable: %v", err)
+		}
+		return b.GetY() - top
+	}
+	return drawn(headers) - drawn([]string{"A", "B"})
+}
+
+func TestHeaderRowHeight(t *testing.T) {
+	long := []string{"Customer account reference number", "B"}
+	rotated := DefaultOptions()
+	rotated.RotateHeaders = true
+	truncated := DefaultOptions()
+	truncated.CellOverflow = CellOverflowEllipsis
+	fixed := DefaultOptions()
+	fixed.HeaderHeight = 30
+
+	tests := []struct {
+		name    string
+		opts    Options
+		headers []string
+		taller  bool
+	}{
+		{"fits on one line", DefaultOptions(), []string{"Name", "B"}, false},
+		{"wraps", DefaultOptions(), long, true},
+		{"rotated", rotated, long, true},
+		{"one line with ellipsis", truncated, long, false},
+		{"fixed HeaderHeight", fixed, long, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extra := headerBand(t, tt.opts, tt.headers)
+			if tt.taller && extra <= 0 {
+				t.Errorf("header band grew by %.1fpt, want taller than a one-line header", extra)
+			}
+			if !tt.taller && extra != 0 {
+				t.Errorf("header band grew by %.1fpt, want the one-line height", extra)
+			}
+		})
+	}
+}
+
+func TestRotatedHeaderBandIsCapped(t *testing.T) {
+	opts := DefaultOptions()
+	opts.RotateHeaders = true
+	label := "a header label far longer than a third of the page is tall, " +
+		"which would leave no room for the data rows below it on each page"
+	extra := headerBand(t, opts, []string{label, "B"})
+	if limit := opts.ContentHeight() * maxRotatedHeaderShare; extra > limit {
+		t.Errorf("rotated header band grew by %.1fpt, want at most %.1fpt", extra, limit)
+	}
+}
+
+func TestLocalizeRow(t *testing.T) {
+	row := []string{"2024", "1234.5", "90210"}
+	perCell := DefaultOptions()
+	perCell.Locale = "en"
+	perCell.PerCellAlignment = true
+	byColumn := DefaultOptions()
+	byColumn.Locale = "en"
+
+	tests := []struct {
+		name  string
+		opts  Options
+		types []ColumnType
+		want  []string
+	}{
+		{"by column type", byColumn, []ColumnType{ColumnText, ColumnNumber, ColumnText}, []string{"2024", "1,234.5", "90210"}},
+		{"per-cell alignment", perCell, []ColumnType{ColumnText, ColumnNumber, ColumnText}, []string{"2024", "1,234.5", "90210"}},
+		{"no column types", byColumn, nil, row},
+		{"fewer types than cells", byColumn, []ColumnType{ColumnNumber}, []string{"2,024", "1234.5", "90210"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewBuilder(tt.opts)
+			if err != nil {
+				t.Fatalf("NewBuilder: %v", err)
+			}
+			b.SetColumnTypes(tt.types)
+			got := b.localizeRow(row)
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("localizeRow = %q, want %q", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestFitHeaderFooterText(t *testing.T) {
+	sentence := "Quarterly revenue by region and product line, prepared for the board"
+	long := strings.Repeat(sentence+" ", 6)
+	truncate := DefaultOptions()
+	truncate.HeaderFooterOverflow = "truncate"
+
+	tests := []struct {
+		name     string
+		opts     Options
+		text     string
+		lines    int
+		ellipsis bool
+	}{
+		{"fits", DefaultOptions(), "Quarterly revenue", 1, false},
+		{"wraps", DefaultOptions(), sentence + " " + sentence, 2, false},
+		{"wraps to the line limit", DefaultOptions(), long, maxHeaderFooterLines, true},
+		{"truncates", truncate, long, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewBuilder(tt.opts)
+			if err != nil {
+				t.Fatalf("NewBuilder: %v", err)
+			}
+			b.setFont("default", "", 10)
+			width := tt.opts.ContentWidth()
+			lines := b.fitHeaderFooterText(tt.text, width)
+			if len(lines) != tt.lines {
+				t.Fatalf("got %d lines %q, want %d", len(lines), lines, tt.lines)
+			}
+			for _, line := range lines {
+				if w := b.MeasureTextWidth(line); w > width {
+					t.Errorf("line %q is %.1fpt wide, wider than the content width %.1fpt", line, w, width)
+				}
+			}
+			if last := lines[len(lines)-1]; strings.HasSuffix(last, "...") != tt.ellipsis {
+				t.Errorf("last line %q, want an ellipsis: %v", last, tt.ellipsis)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package pdf
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnType is the kind of values a table column holds, inferred from sample rows
+type ColumnType int
+
+const (
+	ColumnText     ColumnType = iota // Anything else; drawn left-aligned
+	ColumnNumber                     // Plain or grouped numbers and percentages; right-aligned
+	ColumnCurrency                   // Numbers with a currency symbol; right-aligned
+	ColumnDate                       // Dates and timestamps; centred
+)
+
+// String returns the type's name
+func (t ColumnType) String() string {
+	switch t {
+	case ColumnNumber:
+		return "number"
+	case ColumnCurrency:
+		return "currency"
+	case ColumnDate:
+		return "date"
+	}
+	return "text"
+}
+
+// columnTypeShare is the share of a column's non-empty values that must be of one
+// type for the column to get it, so a stray "n/a" doesn't turn a number column to text
+const columnTypeShare = 0.9
+
+// maxNumberDigits is the most digits a value may have and still be taken for a
+// quantity. Longer digit strings are account, card or reference numbers, which
+// float64 couldn't hold exactly anyway.
+const maxNumberDigits = 15
+
+// currencySymbols are stripped from numbers before parsing; a column whose numbers
+// carry one is a currency column
+const currencySymbols = "$€£¥₹₩₽₺"
+
+// columnDateLayouts are the date formats recognised in cell text
+var columnDateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	"2006/01/02",
+	"01/02/2006",
+	"1/2/2006",
+	"01-02-06", // Excel's default short date
+	"1/2/06",
+	"02.01.2006",
+	"2.1.2006",
+	"02-Jan-2006",
+	"2 Jan 2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+}
+
+// InferColumnTypes classifies each column of the data rows (without header rows) as
+// number, currency, date or text. Columns without values are text.
+func InferColumnTypes(rows [][]string) []ColumnType {
+	numCols := 0
+	for _, row := range rows {
+		if len(row) > numCols {
+			numCols = len(row)
+		}
+	}
+
+	types := make([]ColumnType, numCols)
+	for col := range types {
+		var values, numbers, currencies, dates int
+		for _, row := range rows {
+			if col >= len(row) || strings.TrimSpace(row[col]) == "" {
+				continue
+			}
+			values++
+			switch cellType(row[col]) {
+			case ColumnNumber:
+				numbers++
+			case ColumnCurrency:
+				currencies++
+			case ColumnDate:
+				dates++
+			}
+		}
+
+		enough := func(n int) bool { return values > 0 && float64(n) >= columnTypeShare*float64(values) }
+		switch {
+		case enough(numbers + currencies):
+			// Currency columns often leave the symbol off some values
+			if currencies > 0 {
+				types[col] = ColumnCurrency
+			} else {
+				types[col] = ColumnNumber
+			}
+		case enough(dates):
+			types[col] = ColumnDate
+		}
+	}
+	return types
+}
+
+// cellType classifies a single value
+func cellType(s string) ColumnType {
+	s = strings.TrimSpace(s)
+	if isDateText(s) {
+		return ColumnDate
+	}
+
+	// Accounting negatives: (1,234.00)
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		s = s[1 : len(s)-1]
+	}
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	currency := false
+	if trimmed := strings.Trim(s, currencySymbols+" \u00a0"); trimmed != s {
+		currency, s = true, trimmed
+	}
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	s = strings.TrimSuffix(s, "%")
+	if isCodeText(s) {
+		return ColumnText
+	}
+	if !isGroupedNumber(s) {
+		return ColumnText
+	}
+	if currency {
+		return ColumnCurrency
+	}
+	return ColumnNumber
+}
+
+// IsNumberText reports whether s reads as a number or currency amount, as opposed
+// to text, a date or a numeric code such as 00123
+func IsNumberText(s string) bool {
+	t := cellType(s)
+	return t == ColumnNumber || t == ColumnCurrency
+}
+
+// isCodeText reports whether unsigned numeric text is rather a code or ID: leading
+// zeros (00123) or more than maxNumberDigits digits
+func isCodeText(s string) bool {
+	if len(s) > 1 && s[0] == '0' && s[1] != '.' && s[1] != ',' {
+		return true
+	}
+	digits := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			digits++
+		}
+	}
+	return digits > maxNumberDigits
+}
+
+// isGroupedNumber reports whether s is a number, allowing thousands separators
+// ("," "." space or no-break space) and either decimal mark
+func isGroupedNumber(s string) bool {
+	s = strings.NewReplacer(",", "", " ", "", "\u00a0", "").Replace(s)
+	// ParseFloat also takes words such as "Inf" and "NaN"
+	if s == "" || (s[0] < '0' || s[0] > '9') && s[0] != '.' {
+		return false
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	// 1.234.567 (dots as separators) or 1.234,5 after the commas were removed
+	_, err := strconv.ParseFloat(strings.ReplaceAll(s, ".", ""), 64)
+	return err == nil && strings.Count(s, ".") > 1
+}
+
+// isDateText reports whether s is in one of the recognised date formats
+func isDateText(s string) bool {
+	// Dates need a separator; this also keeps numbers from being tried as layouts
+	if !strings.ContainsAny(s, "-/. ") || len(s) < 6 {
+		return false
+	}
+	for _, layout := range columnDateLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
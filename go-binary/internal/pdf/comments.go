@@ -0,0 +1,281 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/signintech/gopdf"
+)
+
+// Modes of Options.RenderComments
+const (
+	CommentsOff        = "off"
+	CommentsFootnote   = "footnote"   // Numbered marker in the cell, note text at the bottom of the page
+	CommentsAnnotation = "annotation" // PDF text annotation (note icon with a popup) on the cell
+)
+
+const (
+	footnoteFontSize = 7.0 // Size of footnote text and of the markers in cells
+	footnoteRuleGap  = 6.0 // Space above the first footnote of a page, holding the separator rule
+	commentIconSize  = 12.0
+)
+
+// textAnnotation is a cell comment to be added as a PDF text annotation
+type textAnnotation struct {
+	page int        // Physical page number, 1-based
+	rect [4]float64 // PDF user space: left, bottom, right, top
+	text string
+}
+
+// CommentMode returns how cell comments are drawn: CommentsFootnote,
+// CommentsAnnotation or, for any other value, CommentsOff
+func (o Options) CommentMode() string {
+	switch strings.ToLower(o.RenderComments) {
+	case CommentsFootnote:
+		return CommentsFootnote
+	case CommentsAnnotation:
+		return CommentsAnnotation
+	}
+	return CommentsOff
+}
+
+// footnoteLines wraps a footnote to the content width. It leaves the footnote font set.
+func (b *Builder) footnoteLines(text string) []string {
+	b.setFont("default", "", footnoteFontSize)
+	return b.wrapText(text, b.options.ContentWidth())
+}
+
+// footnotesHeight returns the extra height the footnotes of a table row's comments
+// take at the bottom of the page. It leaves the footnote font set when there are any.
+func (b *Builder) footnotesHeight(comments map[int]string, cols int) float64 {
+	if b.options.CommentMode() != CommentsFootnote {
+		return 0
+	}
+	height := 0.0
+	for col, text := range comments {
+		if col < cols && text != "" {
+			n := len(b.footnoteLines(fmt.Sprintf("%d. %s", b.noteCount+1, text)))
+			height += float64(n) * footnoteFontSize * 1.2
+		}
+	}
+	if height > 0 && len(b.footnotes) == 0 {
+		height += footnoteRuleGap
+	}
+	return height
+}
+
+// markComment attaches a comment to the table cell at x on the current row: a
+// numbered marker in its top right corner with the text as a footnote of the page,
+// or a red corner triangle with a text annotation. style is the row's style, whose
+// font is set again afterwards.
+func (b *Builder) markComment(x, w float64, text string, style Style) {
+	top := b.currentY
+	switch b.options.CommentMode() {
+	case CommentsFootnote:
+		b.noteCount++
+		marker := strconv.Itoa(b.noteCount)
+		lines := b.footnoteLines(fmt.Sprintf("%s. %s", marker, text))
+		if len(b.footnotes) == 0 {
+			b.footnoteBand += footnoteRuleGap
+		}
+		b.footnotes = append(b.footnotes, lines...)
+		b.footnoteBand += float64(len(lines)) * footnoteFontSize * 1.2
+
+		b.setTextColor(ColorRed)
+		b.pdf.SetX(x + w - b.MeasureTextWidth(marker) - 1.5)
+		b.pdf.SetY(top + footnoteFontSize)
+		b.pdf.Text(marker)
+	case CommentsAnnotation:
+		// Excel's comment indicator
+		b.setFillColor(ColorRed)
+		b.pdf.Polygon([]gopdf.Point{{X: x + w - 5, Y: top}, {X: x + w, Y: top}, {X: x + w, Y: top + 5}}, "F")
+
+		pageHeight := b.pageHeight()
+		b.annotations = append(b.annotations, textAnnotation{
+			page: b.pageNum,
+			rect: [4]float64{x + w - commentIconSize, pageHeight - top - commentIconSize, x + w, pageHeight - top},
+			text: text,
+		})
+	}
+	b.setFont(style.FontFamily, style.FontStyle, style.FontSize)
+	b.pdf.SetX(x + w)
+}
+
+// drawFootnotes draws the current page's footnotes at the bottom of the page, above
+// the footer, and clears them. Called before the next page is added and by Save.
+func (b *Builder) drawFootnotes() {
+	if len(b.footnotes) == 0 {
+		return
+	}
+	pageHeight := b.pageHeight()
+	top := pageHeight - b.options.Margin - b.footerBand - b.footnoteBand
+
+	b.setStrokeColor(ColorGray)
+	b.pdf.SetLineWidth(0.5)
+	b.pdf.Line(b.options.Margin, top+footnoteRuleGap/2, b.options.Margin+72, top+footnoteRuleGap/2)
+
+	b.setFont("default", "", footnoteFontSize)
+	b.setTextColor(ColorDarkGray)
+	for i, line := range b.footnotes {
+		b.pdf.SetX(b.options.Margin)
+		b.pdf.SetY(top + footnoteRuleGap + footnoteFontSize + float64(i)*footnoteFontSize*1.2)
+		b.pdf.Text(line)
+	}
+	b.footnotes, b.footnoteBand = nil, 0
+}
+
+// annotatePart adds the text annotations of the pages in the part just written to
+// path, and forgets them
+func (b *Builder) annotatePart(path string) error {
+	if len(b.annotations) == 0 {
+		return nil
+	}
+	annots := b.annotations
+	b.annotations = nil
+	for i := range annots {
+		annots[i].page -= b.partStart
+	}
+	return appendTextAnnotations(path, annots)
+}
+
+// appendTextAnnotations adds text annotations to a PDF written by gopdf, which has
+// no API for them, as an incremental update: the annotation objects and new
+// versions of their pages' dictionaries are appended after the original file. It
+// relies on the layout gopdf writes, and fails without changing the file when the
+// trailer or a page's dictionary isn't laid out that way.
+func appendTextAnnotations(path string, annots []textAnnotation) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// gopdf writes a single cross-reference table with one subsection from object 0
+	start := bytes.LastIndex(data, []byte("startxref\n"))
+	if start < 0 {
+		return fmt.Errorf("annotations: no startxref")
+	}
+	xrefPos, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(string(data[start+10:])), "%%EOF")))
+	if err != nil || xrefPos >= len(data) {
+		return fmt.Errorf("annotations: bad startxref")
+	}
+	var first, count int
+	if _, err := fmt.Sscanf(string(data[xrefPos:]), "xref\n%d %d\n", &first, &count); err != nil {
+		return fmt.Errorf("annotations: bad xref: %v", err)
+	}
+	entries := xrefPos + bytes.IndexByte(data[xrefPos+5:], '\n') + 6
+	trailerPos := bytes.Index(data[xrefPos:], []byte("trailer\n"))
+	if trailerPos < 0 {
+		return fmt.Errorf("annotations: no trailer")
+	}
+	trailer := string(data[xrefPos+trailerPos+8 : start])
+	size := fmt.Sprintf("/Size %d\n", count)
+	if !strings.Contains(trailer, size) {
+		return fmt.Errorf("annotations: trailer has no %s", strings.TrimSpace(size))
+	}
+
+	// Page objects in document order, with the offsets of their dictionaries
+	type pageObj struct{ id, start, end int }
+	var pages []pageObj
+	for id := 1; id < count; id++ {
+		entry := entries + id*20
+		if entry+10 > len(data) {
+			break
+		}
+		offset, err := strconv.Atoi(string(data[entry : entry+10]))
+		if err != nil || offset >= len(data) {
+			continue
+		}
+		header := fmt.Sprintf("%d 0 obj\n", id)
+		if !bytes.HasPrefix(data[offset:], []byte(header)) {
+			continue
+		}
+		end := bytes.Index(data[offset:], []byte("endobj"))
+		if end < 0 {
+			continue
+		}
+		if bytes.Contains(data[offset:offset+end], []byte("/Type /Page\n")) {
+			pages = append(pages, pageObj{id, offset + len(header), offset + end})
+		}
+	}
+
+	byPage := make(map[int][]textAnnotation)
+	for _, a := range annots {
+		if a.page < 1 || a.page > len(pages) {
+			return fmt.Errorf("annotations: page %d not found among %d page objects", a.page, len(pages))
+		}
+		byPage[a.page] = append(byPage[a.page], a)
+	}
+	pageNums := make([]int, 0, len(byPage))
+	for p := range byPage {
+		pageNums = append(pageNums, p)
+	}
+	sort.Ints(pageNums)
+
+	var update bytes.Buffer
+	offsets := make(map[int]int)
+	nextID := count
+	for _, p := range pageNums {
+		var refs []string
+		for _, a := range byPage[p] {
+			offsets[nextID] = len(data) + update.Len()
+			fmt.Fprintf(&update, "%d 0 obj\n<</Type /Annot /Subtype /Text /Rect [%.2f %.2f %.2f %.2f] /Contents <%s> /Name /Comment /F 4>>\nendobj\n\n",
+				nextID, a.rect[0], a.rect[1], a.rect[2], a.rect[3], pdfTextString(a.text))
+			refs = append(refs, fmt.Sprintf("%d 0 R", nextID))
+			nextID++
+		}
+
+		page := pages[p-1]
+		dict := string(data[page.start:page.end])
+		if i := strings.Index(dict, "/Annots ["); i >= 0 {
+			i += len("/Annots [")
+			dict = dict[:i] + strings.Join(refs, " ") + " " + dict[i:]
+		} else if i := strings.LastIndex(dict, ">>"); i >= 0 {
+			dict = dict[:i] + "  /Annots [" + strings.Join(refs, " ") + "]\n" + dict[i:]
+		} else {
+			return fmt.Errorf("annotations: page object %d has no dictionary", page.id)
+		}
+		offsets[page.id] = len(data) + update.Len()
+		fmt.Fprintf(&update, "%d 0 obj\n%sendobj\n\n", page.id, dict)
+	}
+	if len(offsets) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(offsets))
+	for id := range offsets {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	xrefOffset := len(data) + update.Len()
+	update.WriteString("xref\n")
+	for _, id := range ids {
+		fmt.Fprintf(&update, "%d 1\n%010d 00000 n \n", id, offsets[id])
+	}
+	trailer = strings.Replace(trailer, size, fmt.Sprintf("/Size %d\n/Prev %d\n", nextID, xrefPos), 1)
+	fmt.Fprintf(&update, "trailer\n%sstartxref\n%d\n%%%%EOF\n", trailer, xrefOffset)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(update.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// pdfTextString encodes text as the hex digits of a UTF-16BE PDF text string
+func pdfTextString(text string) string {
+	var sb strings.Builder
+	sb.WriteString("FEFF")
+	for _, u := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&sb, "%04X", u)
+	}
+	return sb.String()
+}
@@ -0,0 +1,129 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// commentedRows yields a header and data rows, with a comment on the first cell of
+// every data row whose index is a multiple of every
+type commentedRows struct {
+	rows  [][]string
+	every int
+	next  int
+}
+
+func (r *commentedRows) Next() bool {
+	r.next++
+	return r.next <= len(r.rows)
+}
+
+func (r *commentedRows) Columns() ([]string, error) {
+	return r.rows[r.next-1], nil
+}
+
+func (r *commentedRows) CellComments() map[int]string {
+	if r.next == 1 || (r.next-1)%r.every != 0 {
+		return nil
+	}
+	return map[int]string{0: fmt.Sprintf("Checked row %d", r.next-1)}
+}
+
+// annotatedPDF draws a table of n data rows with a comment every tenth row as text
+// annotations and saves it, returning its path and layout
+func annotatedPDF(t *testing.T, n int) (string, Layout) {
+	t.Helper()
+	opts := DefaultOptions()
+	opts.RenderComments = CommentsAnnotation
+	b, err := NewBuilder(opts)
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	defer b.Close()
+
+	rows := [][]string{{"Name", "Amount"}}
+	for i := 1; i <= n; i++ {
+		rows = append(rows, []string{fmt.Sprintf("Row %d", i), strconv.Itoa(i)})
+	}
+	b.AddPage()
+	if err := b.DrawTableStreaming(rows[0], &commentedRows{rows: rows, every: 10}, []float64{200, 100}, true); err != nil {
+		t.Fatalf("DrawTableStreaming: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "annotated.pdf")
+	if err := b.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	return path, b.Layout()
+}
+
+// TestTextAnnotationsRoundTrip reads back a PDF with comment annotations: gofpdi
+// follows the incremental update to the same pages, and the last version of each
+// page's dictionary lists the annotations added on it
+func TestTextAnnotationsRoundTrip(t *testing.T) {
+	path, layout := annotatedPDF(t, 120)
+	if layout.PageCount < 2 {
+		t.Fatalf("table drawn on %d page(s), want several", layout.PageCount)
+	}
+	if n, err := PageCount(path); err != nil || n != layout.PageCount {
+		t.Fatalf("PageCount = %d, %v, want %d", n, err, layout.PageCount)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte("/Prev ")) {
+		t.Fatal("no incremental update with a /Prev cross-reference")
+	}
+	annots := regexp.MustCompile(`(?s)(\d+) 0 obj\n<</Type /Annot /Subtype /Text .*?/Contents <([0-9A-F]+)>`).FindAllSubmatch(data, -1)
+	if len(annots) != 12 {
+		t.Fatalf("%d text annotations, want one per commented row (12)", len(annots))
+	}
+	if want := pdfTextString("Checked row 10"); string(annots[0][2]) != want {
+		t.Errorf("first annotation contents %s, want %s", annots[0][2], want)
+	}
+
+	// Every annotation is referenced from the /Annots of a page rewritten in the update
+	listed := make(map[string]bool)
+	for _, m := range regexp.MustCompile(`/Annots \[([0-9 R]+)\]`).FindAllSubmatch(data, -1) {
+		for _, ref := range regexp.MustCompile(`(\d+) 0 R`).FindAllSubmatch(m[1], -1) {
+			listed[string(ref[1])] = true
+		}
+	}
+	for _, a := range annots {
+		if !listed[string(a[1])] {
+			t.Errorf("annotation %s 0 R isn't in any page's /Annots", a[1])
+		}
+	}
+}
+
+func TestTextAnnotationsUnexpectedLayout(t *testing.T) {
+	path, _ := annotatedPDF(t, 5)
+	original, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Undo the update, then hide the trailer's /Size from it
+	end := bytes.Index(original, []byte("%%EOF")) + len("%%EOF\n")
+	damaged := bytes.Replace(original[:end], []byte("/Size "), []byte("/Size  "), 1)
+	if err := os.WriteFile(path, damaged, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err = appendTextAnnotations(path, []textAnnotation{{page: 1, rect: [4]float64{10, 10, 22, 22}, text: "Note"}})
+	if err == nil {
+		t.Fatal("appendTextAnnotations succeeded on a trailer it can't update")
+	}
+	if after, _ := os.ReadFile(path); !bytes.Equal(after, damaged) {
+		t.Error("appendTextAnnotations changed the file it failed on")
+	}
+
+	if err := appendTextAnnotations(path, []textAnnotation{{page: 9, text: "Note"}}); err == nil {
+		t.Error("appendTextAnnotations succeeded for a page the file doesn't have")
+	}
+}
@@ -0,0 +1,12 @@
+package pdf
+
+import _ "embed"
+
+// embeddedFont is DejaVu Sans (Bitstream Vera license, see fonts/LICENSE). It is the
+// last-resort font when neither a custom nor a system font can be loaded.
+//
+//go:embed fonts/DejaVuSans.ttf
+var embeddedFont []byte
+
+// EmbeddedFontName is reported as Layout.Font when embeddedFont is used
+const EmbeddedFontName = "DejaVu Sans (embedded)"
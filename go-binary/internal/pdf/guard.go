@@ -0,0 +1,37 @@
+package pdf
+
+// errConcurrentUse is the panic value of a Builder used by two goroutines at once
+const errConcurrentUse = "pdf: Builder used by two goroutines at once; give each goroutine its own Builder"
+
+// enter marks the builder in use for the duration of an exported method, which
+// defers the function it returns: defer b.enter()(). A Builder, like the gopdf
+// document under it, is not safe for concurrent use, and two goroutines drawing
+// on one would silently corrupt the document, so enter panics instead. Exported
+// methods that other methods call are split into an unexported twin holding the
+// body, so that a method never enters the builder it already holds.
+//
+// Only the methods that change the document check; read-only ones such as
+// MeasureTextWidth don't, which keeps TextMeasurer usable from many goroutines.
+func (b *Builder) enter() func() {
+	if !b.inUse.CompareAndSwap(false, true) {
+		panic(errConcurrentUse)
+	}
+	return func() { b.inUse.Store(false) }
+}
+
+// release lets go of the builder while fn runs and takes it back after, for
+// callbacks such as the page callback that may draw with exported methods while
+// an exported method of their own goroutine holds the builder. Another goroutine
+// entering meanwhile still panics, when fn returns at the latest.
+func (b *Builder) release(fn func()) {
+	if !b.inUse.CompareAndSwap(true, false) {
+		fn() // Called outside an exported method
+		return
+	}
+	defer func() {
+		if !b.inUse.CompareAndSwap(false, true) {
+			panic(errConcurrentUse)
+		}
+	}()
+	fn()
+}
@@ -0,0 +1,91 @@
+package pdf
+
+import (
+	"sync"
+	"testing"
+)
+
+func newTestBuilder(t testing.TB) *Builder {
+	t.Helper()
+	b, err := NewBuilder(DefaultOptions())
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	return b
+}
+
+// panicValue runs fn and returns what it panicked with, or nil
+func panicValue(fn func()) (value interface{}) {
+	defer func() { value = recover() }()
+	fn()
+	return nil
+}
+
+func TestBuilderSequentialUse(t *testing.T) {
+	b := newTestBuilder(t)
+	drawTable := func() {
+		b.AddPage()
+		b.AddText("Report", DefaultStyle())
+		b.DrawTable([]string{"A", "B"}, [][]string{{"1", "2"}, {"3", "4"}}, []float64{100, 100})
+	}
+	if v := panicValue(drawTable); v != nil {
+		t.Fatalf("sequential use panicked: %v", v)
+	}
+}
+
+func TestBuilderPageCallbackDraws(t *testing.T) {
+	b := newTestBuilder(t)
+	var pages []int
+	b.SetPageCallback(func(pageNum int) {
+		pages = append(pages, pageNum)
+		b.RotatedText("DRAFT", 30, 30, 45)
+	})
+	rows := make([][]string, 200) // Spans several pages
+	for i := range rows {
+		rows[i] = []string{"row", "value"}
+	}
+	drawTable := func() {
+		b.AddPage()
+		b.DrawTable([]string{"A", "B"}, rows, []float64{100, 100})
+	}
+	if v := panicValue(drawTable); v != nil {
+		t.Fatalf("page callback drawing on the builder panicked: %v", v)
+	}
+	if len(pages) < 2 {
+		t.Fatalf("page callback ran for pages %v, want several", pages)
+	}
+	// The builder is free again afterwards
+	if v := panicValue(b.AddPage); v != nil {
+		t.Fatalf("AddPage after the callbacks panicked: %v", v)
+	}
+}
+
+func TestBuilderConcurrentUsePanics(t *testing.T) {
+	b := newTestBuilder(t)
+	b.AddPage()
+
+	// One goroutine holds the builder, as in the middle of a DrawTable, while
+	// another draws on it
+	holding, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		leave := b.enter()
+		close(holding)
+		<-done
+		leave()
+	}()
+	<-holding
+
+	var value interface{}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		value = panicValue(func() { b.AddText("second goroutine", DefaultStyle()) })
+	}()
+	wg.Wait()
+	close(done)
+
+	if value != errConcurrentUse {
+		t.Fatalf("concurrent use panicked with %v, want %q", value, errConcurrentUse)
+	}
+}
@@ -0,0 +1,86 @@
+package pdf
+
+import (
+	"sort"
+	"strconv"
+)
+
+// legendTitle is the caption above the legend table
+const legendTitle = "Legend"
+
+// legendHeaders are the header labels of the legend table
+var legendHeaders = []string{"Code", "Description"}
+
+// legendColumnTypes are the column types of the legend table: both columns are
+// text, so numeric codes are drawn as written rather than locale formatted
+var legendColumnTypes = []ColumnType{ColumnText, ColumnText}
+
+// drawLegend draws Options.Legend as a Code/Description table on a page of its
+// own after the content, so readers can look up coded values. The table always
+// has a single header row, whatever the options say about the data's. Called by
+// Save.
+func (b *Builder) drawLegend() error {
+	if len(b.options.Legend) == 0 || b.pageNum == 0 {
+		return nil
+	}
+
+	codes := legendCodes(b.options.Legend)
+	rows := make([][]string, len(codes))
+	for i, code := range codes {
+		rows[i] = []string{code, b.options.Legend[code]}
+	}
+
+	saved := b.options
+	b.options.HeaderRow, b.options.HeaderRows, b.options.RotateHeaders = true, 1, false
+	b.options.PerCellAlignment = false
+	b.columnTypes, b.filterColumns, b.onProgress = legendColumnTypes, nil, nil
+	defer func() { b.options = saved }()
+
+	b.addPage()
+	if err := b.addCaption(legendTitle); err != nil {
+		return err
+	}
+	return b.drawTable(legendHeaders, rows, b.legendWidths(codes))
+}
+
+// legendCodes returns the codes of a legend in order: numeric codes by value
+// first, then the others alphabetically
+func legendCodes(legend map[string]string) []string {
+	codes := make([]string, 0, len(legend))
+	for code := range legend {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		a, errA := strconv.ParseFloat(codes[i], 64)
+		b, errB := strconv.ParseFloat(codes[j], 64)
+		switch {
+		case errA == nil && errB == nil && a != b:
+			return a < b
+		case (errA == nil) != (errB == nil):
+			return errA == nil
+		}
+		return codes[i] < codes[j]
+	})
+	return codes
+}
+
+// legendWidths returns the column widths of the legend table: the code column as
+// wide as its longest code, up to a third of the content width, and the rest for
+// the descriptions
+func (b *Builder) legendWidths(codes []string) []float64 {
+	style := DefaultStyle()
+	b.setFont(style.FontFamily, style.FontStyle, style.FontSize)
+	padding := b.options.CellPadding
+	if padding <= 0 {
+		padding = style.Padding
+	}
+
+	minWidth, _ := b.options.ColumnWidthLimits()
+	codeWidth := minWidth
+	for _, code := range append([]string{legendHeaders[0]}, codes...) {
+		codeWidth = max(codeWidth, b.MeasureTextWidth(code)+padding*2+2)
+	}
+	content := b.options.ContentWidth()
+	codeWidth = min(codeWidth, content/3)
+	return []float64{codeWidth, content - codeWidth}
+}
@@ -0,0 +1,89 @@
+package pdf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLegendCodes(t *testing.T) {
+	legend := map[string]string{
+		"B":    "Backordered",
+		"10":   "Ten",
+		"2":    "Two",
+		"1000": "Thousand",
+		"-1":   "Refund",
+		"2.5":  "Half",
+		"A":    "Active",
+		"02":   "Leading zero",
+		"a":    "Lowercase",
+	}
+	// Numbers by value, equal values such as 2 and 02 by their text, then the rest
+	want := []string{"-1", "02", "2", "2.5", "10", "1000", "A", "B", "a"}
+	if got := legendCodes(legend); !reflect.DeepEqual(got, want) {
+		t.Errorf("legendCodes = %q, want %q", got, want)
+	}
+}
+
+func TestLegendWidths(t *testing.T) {
+	b, err := NewBuilder(DefaultOptions())
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	defer b.Close()
+	content := b.options.ContentWidth()
+	minWidth, _ := b.options.ColumnWidthLimits()
+
+	short := b.legendWidths([]string{"A", "B"})
+	if short[0] != minWidth {
+		t.Errorf("code column %v wide for one-letter codes, want the minimum %v", short[0], minWidth)
+	}
+
+	long := b.legendWidths([]string{"A", "PENDING-REVIEW"})
+	if long[0] <= minWidth || long[0] < b.MeasureTextWidth("PENDING-REVIEW") {
+		t.Errorf("code column %v wide, want room for the longest code", long[0])
+	}
+
+	capped := b.legendWidths([]string{strings.Repeat("VERY-LONG-CODE-", 20)})
+	if capped[0] != content/3 {
+		t.Errorf("code column %v wide, want a third of the content width (%v)", capped[0], content/3)
+	}
+
+	for _, widths := range [][]float64{short, long, capped} {
+		if widths[0]+widths[1] != content {
+			t.Errorf("widths %v, want them to fill the content width %v", widths, content)
+		}
+	}
+}
+
+// TestLegendCodesAreText checks that numeric codes in the legend keep their text
+// under a locale that groups the data's numbers
+func TestLegendCodesAreText(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Locale = "en_US"
+	opts.PerCellAlignment = true
+	opts.Legend = map[string]string{"1000": "Thousand", "7": "Seven"}
+	b, err := NewBuilder(opts)
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	defer b.Close()
+
+	b.SetColumnTypes([]ColumnType{ColumnNumber, ColumnNumber})
+	b.AddPage()
+	if got := b.localizeRow([]string{"1000", "2000"}); got[0] != "1,000" {
+		t.Fatalf("data row localized to %q, want number columns grouped", got)
+	}
+	if err := b.drawLegend(); err != nil {
+		t.Fatalf("drawLegend: %v", err)
+	}
+	if !reflect.DeepEqual(b.columnTypes, legendColumnTypes) {
+		t.Errorf("legend drawn with column types %v, want text", b.columnTypes)
+	}
+	if got := b.localizeRow([]string{"1000", "Thousand"}); got[0] != "1000" {
+		t.Errorf("legend row localized to %q, want the code as written", got)
+	}
+	if !b.options.PerCellAlignment {
+		t.Error("the data's options weren't restored after the legend")
+	}
+}
@@ -0,0 +1,139 @@
+package pdf
+
+import (
+	"regexp"
+	"strings"
+)
+
+// urlPattern matches cell text that is a single http(s) URL with a dotted host.
+// It is deliberately strict: text around the URL, spaces or a bare host such as
+// "example.com" are not linked.
+var urlPattern = regexp.MustCompile(`(?i)^https?://[a-z0-9](?:[a-z0-9-]*[a-z0-9])?(?:\.[a-z0-9](?:[a-z0-9-]*[a-z0-9])?)+(?::[0-9]{1,5})?(?:[/?#][^\s<>"]*)?$`)
+
+// cellURL returns the URL a table cell links to with Options.AutolinkURLs, or ""
+func (b *Builder) cellURL(text string) string {
+	if !b.options.AutolinkURLs {
+		return ""
+	}
+	text = strings.TrimSpace(text)
+	if !urlPattern.MatchString(text) {
+		return ""
+	}
+	return text
+}
+
+// tableCell draws a table cell with Cell. A cell whose text is a URL is drawn in
+// blue and covered by a link to it when Options.AutolinkURLs is set.
+func (b *Builder) tableCell(w, h float64, text string, style Style) error {
+	url := b.cellURL(text)
+	if url == "" {
+		return b.drawCell(w, h, text, style)
+	}
+	x := b.pdf.GetX()
+	style.TextColor = ColorBlue
+	if err := b.drawCell(w, h, text, style); err != nil {
+		return err
+	}
+	b.addLink(url, x, b.currentY, w, h)
+	return nil
+}
+
+// addLink adds a link to url over a rectangle of the current page. gopdf places
+// links by the document's default page height, so pages of another size added by
+// AddPageWith are offset to match.
+func (b *Builder) addLink(url string, x, y, w, h float64) {
+	y -= b.pageHeight() - b.docPageSize.H
+	b.pdf.AddExternalLink(url, x, y, w, h)
+}
+
+// TextLink makes the bytes Start to End of a text block a link to URL or, with no
+// URL, to the page marked by AddAnchor(Anchor)
+type TextLink struct {
+	Start, End int
+	URL        string
+	Anchor     string
+}
+
+// AddAnchor marks the top of the current page as the target of links to name.
+// Links to an anchor that is never added, or that ends up in another part of a
+// split output, are left out.
+func (b *Builder) AddAnchor(name string) {
+	defer b.enter()()
+	y := b.pdf.GetY()
+	b.pdf.SetY(0)
+	b.pdf.SetAnchor(name)
+	b.pdf.SetY(y)
+}
+
+// addInternalLink adds a link to an anchor over a rectangle of the current page,
+// offset like addLink
+func (b *Builder) addInternalLink(anchor string, x, y, w, h float64) {
+	y -= b.pageHeight() - b.docPageSize.H
+	b.pdf.AddInternalLink(anchor, x, y, w, h)
+}
+
+// addLineLinks covers the words of a line drawn at x by addTextBlock that fall in
+// links, one rectangle per run of words with the same link. The line was wrapped
+// from para, which starts at byte start of the block's text; its words are looked
+// up in para from pos on, and the position after the last one is returned for the
+// next line. justifyWidth is the width of a justified line, or 0.
+func (b *Builder) addLineLinks(line, para string, start, pos int, x, top, height, justifyWidth float64, links []TextLink) int {
+	words := strings.Fields(line)
+	var widths []float64
+	gap := 0.0
+	if justifyWidth > 0 && len(words) > 1 {
+		widths, gap = b.justifiedGap(words, justifyWidth)
+	}
+
+	current, linkX, linkEnd := -1, 0.0, 0.0
+	flush := func() {
+		if current >= 0 {
+			b.addTextLink(links[current], linkX, top, linkEnd-linkX, height)
+		}
+		current = -1
+	}
+	inLine, wordX := 0, x
+	for k, word := range words {
+		off := inLine + strings.Index(line[inLine:], word)
+		inLine = off + len(word)
+		wx, ww := wordX, 0.0
+		if widths != nil {
+			ww = widths[k]
+			wordX += widths[k] + gap
+		} else {
+			wx, ww = x+b.MeasureTextWidth(line[:off]), b.MeasureTextWidth(word)
+		}
+
+		if i := strings.Index(para[pos:], word); i >= 0 {
+			pos += i
+		}
+		link := linkAt(links, start+pos, start+pos+len(word))
+		pos += len(word)
+		if link != current {
+			flush()
+			current, linkX = link, wx
+		}
+		linkEnd = wx + ww
+	}
+	flush()
+	return pos
+}
+
+// linkAt returns the index of the first link overlapping the bytes start to end, or -1
+func linkAt(links []TextLink, start, end int) int {
+	for i, link := range links {
+		if link.Start < end && link.End > start {
+			return i
+		}
+	}
+	return -1
+}
+
+// addTextLink adds a TextLink over a rectangle of the current page
+func (b *Builder) addTextLink(link TextLink, x, y, w, h float64) {
+	if link.URL != "" {
+		b.addLink(link.URL, x, y, w, h)
+	} else if link.Anchor != "" {
+		b.addInternalLink(link.Anchor, x, y, w, h)
+	}
+}
@@ -0,0 +1,235 @@
+package pdf
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// localeBooleans maps a language code to its spreadsheet TRUE/FALSE labels
+var localeBooleans = map[string][2]string{
+	"en": {"TRUE", "FALSE"},
+	"de": {"WAHR", "FALSCH"},
+	"fr": {"VRAI", "FAUX"},
+	"es": {"VERDADERO", "FALSO"},
+	"it": {"VERO", "FALSO"},
+	"nl": {"WAAR", "ONWAAR"},
+	"pt": {"VERDADEIRO", "FALSO"},
+}
+
+// localeLanguage returns the lowercase language part of a locale ("de_DE" -> "de")
+func localeLanguage(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "_-."); i >= 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
+
+// BooleanLabels returns the TRUE/FALSE labels for a locale, defaulting to English
+func BooleanLabels(locale string) (string, string) {
+	if labels, ok := localeBooleans[localeLanguage(locale)]; ok {
+		return labels[0], labels[1]
+	}
+	labels := localeBooleans["en"]
+	return labels[0], labels[1]
+}
+
+// localeNumberSeparators maps a language code to its decimal mark and thousands separator
+var localeNumberSeparators = map[string][2]string{
+	"en": {".", ","},
+	"de": {",", "."},
+	"fr": {",", "\u00a0"}, // no-break space keeps groups together when wrapping
+	"es": {",", "."},
+	"it": {",", "."},
+	"nl": {",", "."},
+	"pt": {",", "."},
+}
+
+// NumberSeparators returns the decimal mark and thousands separator for a locale
+func NumberSeparators(locale string) (string, string) {
+	if seps, ok := localeNumberSeparators[localeLanguage(locale)]; ok {
+		return seps[0], seps[1]
+	}
+	seps := localeNumberSeparators["en"]
+	return seps[0], seps[1]
+}
+
+// FormatNumber formats a value with the locale's thousands separator and decimal mark
+// (e.g. 1234.56 -> "1,234.56" for en_US, "1.234,56" for de_DE)
+func FormatNumber(value float64, locale string) string {
+	return groupNumberText(strconv.FormatFloat(value, 'f', -1, 64), locale)
+}
+
+// localizeNumericText formats plain numeric text for the locale. Text that is already
+// formatted (grouping, currency, leading zeros, long IDs) is left as is, so a number format
+// applied in the source file wins over the locale default.
+func localizeNumericText(text, locale string) string {
+	if locale == "" || !isPlainNumber(text) {
+		return text
+	}
+	return groupNumberText(strings.TrimSpace(text), locale)
+}
+
+// groupNumberText inserts separators into a plain number string, keeping its decimals as written
+func groupNumberText(s, locale string) string {
+	decimal, group := NumberSeparators(locale)
+
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+
+	var out strings.Builder
+	out.WriteString(sign)
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			out.WriteString(group)
+		}
+		out.WriteRune(r)
+	}
+	if fracPart != "" {
+		out.WriteString(decimal)
+		out.WriteString(fracPart)
+	}
+	return out.String()
+}
+
+// isPlainNumber reports whether s is an unformatted number such as "1234.5" or "-42"
+func isPlainNumber(s string) bool {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		s = s[1:]
+	}
+	intPart, fracPart, hasDot := strings.Cut(s, ".")
+	if intPart == "" || (hasDot && fracPart == "") {
+		return false
+	}
+	// Leading zeros and long digit strings mark codes/IDs rather than quantities
+	if isCodeText(intPart) {
+		return false
+	}
+	for _, part := range []string{intPart, fracPart} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// InputNumberLocale returns the locale numbers in the input are parsed with:
+// NumberLocale, else Locale
+func (o Options) InputNumberLocale() string {
+	if o.NumberLocale != "" {
+		return o.NumberLocale
+	}
+	return o.Locale
+}
+
+// ParseNumber parses a cell as a number written for the locale ("1.234,56" for
+// de_DE, "1,234.56" for en_US) or unformatted ("1234.56"). Thousands separators
+// must group the digits in threes, so plain numbers such as "1234.5" keep their
+// meaning under locales that group with ".". An empty locale means en_US.
+func ParseNumber(s, locale string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+	decimal, group := NumberSeparators(locale)
+	if v, ok := parseGroupedNumber(s, decimal, group); ok {
+		return v, true
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
+
+// parseGroupedNumber parses s with the given decimal mark and thousands separator.
+// Where the separator is a no-break space, ordinary spaces are accepted too.
+func parseGroupedNumber(s, decimal, group string) (float64, bool) {
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+	if group == "\u00a0" {
+		s = strings.ReplaceAll(s, " ", group)
+	}
+	intPart, fracPart, hasDecimal := strings.Cut(s, decimal)
+	if intPart == "" || (hasDecimal && (fracPart == "" || !isDigits(fracPart))) {
+		return 0, false
+	}
+
+	groups := strings.Split(intPart, group)
+	for i, g := range groups {
+		if !isDigits(g) || (i > 0 && len(g) != 3) || (len(groups) > 1 && len(groups[0]) > 3) {
+			return 0, false
+		}
+	}
+	text := sign + strings.Join(groups, "")
+	if hasDecimal {
+		text += "." + fracPart
+	}
+	v, err := strconv.ParseFloat(text, 64)
+	return v, err == nil
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// localeShortDates maps a language code to its numeric date layout
+var localeShortDates = map[string]string{
+	"en": "01/02/2006",
+	"de": "02.01.2006",
+	"fr": "02/01/2006",
+	"es": "02/01/2006",
+	"it": "02/01/2006",
+	"nl": "02-01-2006",
+	"pt": "02/01/2006",
+}
+
+// dateLayout resolves Options.DateFormat to a Go time layout. Named formats are
+// "iso" (default), "short" (locale dependent), "rfc1123" and "rfc3339"; any other
+// value is used as a Go layout string.
+func dateLayout(format, locale string) string {
+	switch strings.ToLower(format) {
+	case "", "iso":
+		return "2006-01-02"
+	case "short":
+		if layout, ok := localeShortDates[localeLanguage(locale)]; ok {
+			return layout
+		}
+		return localeShortDates["en"]
+	case "rfc1123":
+		return time.RFC1123
+	case "rfc3339":
+		return time.RFC3339
+	}
+	return format
+}
+
+// resolveTimezone loads an IANA timezone, falling back to the server's local zone
+func resolveTimezone(name string) *time.Location {
+	if name == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
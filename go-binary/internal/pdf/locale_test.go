@@ -0,0 +1,66 @@
+package pdf
+
+import "testing"
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		s      string
+		locale string
+		want   float64
+		ok     bool
+	}{
+		{"1.500", "de_DE", 1500, true},
+		{"1.500", "en_US", 1.5, true},
+		{"1.500", "", 1.5, true},
+		{"1,5", "en_US", 0, false},
+		{"1,5", "de_DE", 1.5, true},
+		{"1,234.56", "en_US", 1234.56, true},
+		{"1.234,56", "de_DE", 1234.56, true},
+		{"-1.234.567,8", "de", -1234567.8, true},
+		{"1 234,5", "fr_FR", 1234.5, true}, // Ordinary space for the no-break one
+		{"1\u00a0234,5", "fr_FR", 1234.5, true},
+		// Unformatted numbers mean the same under every locale
+		{"1234.5", "de_DE", 1234.5, true},
+		{" 42 ", "de_DE", 42, true},
+		{"1e3", "en_US", 1000, true},
+		// Thousands separators group the digits in threes
+		{"1,23", "en_US", 0, false},
+		{"12,34,567", "en_US", 0, false},
+		{"1234,567", "en_US", 0, false},
+		{"", "en_US", 0, false},
+		{"12 apples", "en_US", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseNumber(tt.s, tt.locale)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("ParseNumber(%q, %q) = %v, %v, want %v, %v", tt.s, tt.locale, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseGroupedNumber(t *testing.T) {
+	tests := []struct {
+		s              string
+		decimal, group string
+		want           float64
+		ok             bool
+	}{
+		{"1,234,567.25", ".", ",", 1234567.25, true},
+		{"+1,000", ".", ",", 1000, true},
+		{"999", ".", ",", 999, true},
+		{"1.500", ",", ".", 1500, true},
+		{"1 500", ",", " ", 1500, true},
+		{"1,5", ".", ",", 0, false},      // Group of one digit
+		{"1234,567", ".", ",", 0, false}, // Leading group of four
+		{"1,234.", ".", ",", 0, false},   // Decimal mark without decimals
+		{"1,234.5x", ".", ",", 0, false},
+		{".5", ".", ",", 0, false},
+		{"-", ".", ",", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseGroupedNumber(tt.s, tt.decimal, tt.group)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("parseGroupedNumber(%q, %q, %q) = %v, %v, want %v, %v", tt.s, tt.decimal, tt.group, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package pdf
+
+import "sync"
+
+// TextMeasurer measures text in a builder's current font and size, and is safe for
+// concurrent use. gopdf isn't, so each distinct rune is measured once through it
+// (under a lock) and cached; a string's width is the sum of its runes' widths,
+// which is how gopdf computes it when kerning is off.
+type TextMeasurer struct {
+	mu     sync.RWMutex
+	b      *Builder
+	widths map[rune]float64
+}
+
+// TextMeasurer returns a measurer for the current font. The builder must not change
+// font or be drawn on while the measurer is in use.
+func (b *Builder) TextMeasurer() *TextMeasurer {
+	return &TextMeasurer{b: b, widths: make(map[rune]float64)}
+}
+
+// Width returns the width of text in points
+func (m *TextMeasurer) Width(text string) float64 {
+	width := 0.0
+	m.mu.RLock()
+	for i, r := range text {
+		w, ok := m.widths[r]
+		if !ok {
+			m.mu.RUnlock()
+			return width + m.measure(text[i:])
+		}
+		width += w
+	}
+	m.mu.RUnlock()
+	return width
+}
+
+// measure is the slow path of Width, measuring and caching runes not seen before
+func (m *TextMeasurer) measure(text string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	width := 0.0
+	for _, r := range text {
+		w, ok := m.widths[r]
+		if !ok {
+			w = m.b.MeasureTextWidth(string(r))
+			m.widths[r] = w
+		}
+		width += w
+	}
+	return width
+}
@@ -0,0 +1,201 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/phpdave11/gofpdi"
+	"github.com/signintech/gopdf"
+)
+
+// AppendFile appends the pages of src to the PDF at target. If target doesn't exist
+// yet it becomes a copy of src. The merged document is written next to target first
+// and then renamed over it, so a failed merge leaves target untouched.
+func AppendFile(target, src string) error {
+	if _, err := os.Stat(target); os.IsNotExist(err) {
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	}
+
+	if err := checkPDF(target); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".append-*.pdf")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+
+	if err := mergeFiles(tmpPath, target, src); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, target); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// checkPDF rejects files gofpdi can't parse without spinning or panicking: the PDF
+// header must be present and the cross-reference offset must be near the end
+func checkPDF(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(file, header); err != nil || !bytes.Equal(header, []byte("%PDF-")) {
+		return fmt.Errorf("%s is not a PDF file", path)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	tailSize := int64(1024)
+	if info.Size() < tailSize {
+		tailSize = info.Size()
+	}
+	tail := make([]byte, tailSize)
+	if _, err := file.ReadAt(tail, info.Size()-tailSize); err != nil {
+		return err
+	}
+	if !bytes.Contains(tail, []byte("startxref")) {
+		return fmt.Errorf("%s is truncated or damaged (no startxref)", path)
+	}
+	return nil
+}
+
+// mergeFiles writes the pages of all inputs, in order, to outputPath. Each page keeps
+// its own size.
+func mergeFiles(outputPath string, inputPaths ...string) (err error) {
+	// gofpdi reports unreadable PDFs by panicking
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cannot read PDF: %v", r)
+		}
+	}()
+
+	doc := &gopdf.GoPdf{}
+	doc.Start(gopdf.Config{PageSize: gopdf.Rect{W: PageA4.Width, H: PageA4.Height}})
+
+	for _, path := range inputPaths {
+		importer := gofpdi.NewImporter()
+		importer.SetSourceFile(path)
+		sizes := importer.GetPageSizes()
+
+		for page := 1; page <= importer.GetNumPages(); page++ {
+			importPage(doc, path, page, sizes)
+		}
+	}
+
+	return doc.WritePdf(outputPath)
+}
+
+// importPage adds page of the PDF at path to doc, at its own size
+func importPage(doc *gopdf.GoPdf, path string, page int, sizes map[int]map[string]map[string]float64) {
+	box := sizes[page]["/MediaBox"]
+	w, h := box["w"], box["h"]
+	if w <= 0 || h <= 0 {
+		w, h = PageA4.Width, PageA4.Height
+	}
+
+	doc.AddPageWithOption(gopdf.PageOption{PageSize: &gopdf.Rect{W: w, H: h}})
+	tpl := doc.ImportPage(path, page, "/MediaBox")
+	doc.UseImportedTemplate(tpl, 0, 0, w, h)
+}
+
+// PageCount returns the number of pages of the PDF at path
+func PageCount(path string) (count int, err error) {
+	if err := checkPDF(path); err != nil {
+		return 0, err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cannot read PDF: %v", r)
+		}
+	}()
+
+	importer := gofpdi.NewImporter()
+	importer.SetSourceFile(path)
+	return importer.GetNumPages(), nil
+}
+
+// FirstPageSize returns the width and height, in points, of the first page of the
+// PDF at path
+func FirstPageSize(path string) (w, h float64, err error) {
+	if err := checkPDF(path); err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cannot read PDF: %v", r)
+		}
+	}()
+
+	importer := gofpdi.NewImporter()
+	importer.SetSourceFile(path)
+	box := importer.GetPageSizes()[1]["/MediaBox"]
+	if box["w"] <= 0 || box["h"] <= 0 {
+		return 0, 0, fmt.Errorf("the first page has no size")
+	}
+	return box["w"], box["h"], nil
+}
+
+// SelectPages rewrites the PDF at path with only the given pages, numbered from 1,
+// in the order listed. Like AppendFile it writes next to path and renames over it.
+// Links and annotations of the kept pages are not carried over.
+func SelectPages(path string, pages []int) error {
+	if err := checkPDF(path); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".pages-*.pdf")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+
+	if err := writePages(tmpPath, path, pages); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// writePages writes the given pages of inputPath to outputPath
+func writePages(outputPath, inputPath string, pages []int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cannot read PDF: %v", r)
+		}
+	}()
+
+	doc := &gopdf.GoPdf{}
+	doc.Start(gopdf.Config{PageSize: gopdf.Rect{W: PageA4.Width, H: PageA4.Height}})
+
+	importer := gofpdi.NewImporter()
+	importer.SetSourceFile(inputPath)
+	sizes := importer.GetPageSizes()
+	for _, page := range pages {
+		importPage(doc, inputPath, page, sizes)
+	}
+
+	return doc.WritePdf(outputPath)
+}
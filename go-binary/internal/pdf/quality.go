@@ -0,0 +1,173 @@
+package pdf
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"strings"
+)
+
+// Levels of Options.Quality, from the largest output to the smallest. The first
+// three embed images as they are; the others downsample and recompress them.
+const (
+	QualityBest     = "best"
+	QualityBalanced = "balanced"
+	QualityFast     = "fast"
+	QualityCompact  = "compact" // Images at most 150 dpi, JPEG quality 75
+	QualitySmall    = "small"   // Images at most 96 dpi, JPEG quality 60
+	QualityMinimum  = "minimum" // Images at most 72 dpi, JPEG quality 40
+)
+
+// ImageQuality is how a Quality level embeds images: at most DPI pixels per inch
+// of their drawn size, recompressed as JPEG at JPEGQuality. DPI 0 keeps images as
+// they are.
+type ImageQuality struct {
+	DPI         int
+	JPEGQuality int
+}
+
+var imageQualities = map[string]ImageQuality{
+	QualityCompact: {150, 75},
+	QualitySmall:   {96, 60},
+	QualityMinimum: {72, 40},
+}
+
+// ImageQuality returns how images are embedded at Options.Quality
+func (o Options) ImageQuality() ImageQuality {
+	return imageQualities[strings.ToLower(o.Quality)]
+}
+
+// LowerQuality returns the Quality level after q when stepping down to shrink the
+// output, or "" when q is already the smallest. Levels that embed images as they
+// are step straight to QualityCompact.
+func LowerQuality(q string) string {
+	switch strings.ToLower(q) {
+	case QualityCompact:
+		return QualitySmall
+	case QualitySmall:
+		return QualityMinimum
+	case QualityMinimum:
+		return ""
+	}
+	return QualityCompact
+}
+
+// scaledImage returns the image to draw for imagePath at w x h points: imagePath
+// itself, or a temp file with the image downsampled and recompressed per
+// Options.Quality. Images it can't decode, or already small enough, are drawn as
+// they are.
+func (b *Builder) scaledImage(imagePath string, w, h float64) string {
+	q := b.options.ImageQuality()
+	if q.DPI == 0 {
+		return imagePath
+	}
+	maxW, maxH := int(w/72*float64(q.DPI)+0.5), int(h/72*float64(q.DPI)+0.5)
+	key := fmt.Sprintf("%s@%dx%d", imagePath, maxW, maxH)
+	if path, ok := b.scaledImages[key]; ok {
+		return path
+	}
+
+	path := imagePath
+	if scaled, err := downsampleImage(imagePath, maxW, maxH, q.JPEGQuality, b.options.TempDir); err == nil {
+		path = scaled
+	}
+	if b.scaledImages == nil {
+		b.scaledImages = make(map[string]string)
+	}
+	b.scaledImages[key] = path
+	return path
+}
+
+// downsampleImage writes the image at path, shrunk to fit maxW x maxH pixels, as a
+// JPEG temp file in dir and returns its name. Images with transparency are left
+// alone, as JPEG would fill it with black, and so are JPEGs already small enough
+// and images the JPEG wouldn't be smaller than.
+func downsampleImage(path string, maxW, maxH, quality int, dir string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	src, format, err := image.Decode(file)
+	file.Close()
+	if err != nil {
+		return "", err
+	}
+	if !isOpaque(src) {
+		return "", fmt.Errorf("image has transparency")
+	}
+
+	bounds := src.Bounds()
+	scale := 1.0
+	if maxW > 0 && bounds.Dx() > maxW {
+		scale = float64(maxW) / float64(bounds.Dx())
+	}
+	if maxH > 0 && float64(bounds.Dy())*scale > float64(maxH) {
+		scale = float64(maxH) / float64(bounds.Dy())
+	}
+	if scale == 1 && format == "jpeg" {
+		return "", fmt.Errorf("image is small enough")
+	}
+	dst := shrinkImage(src, scale)
+
+	out, err := os.CreateTemp(dir, "gopdfconv-image-*.jpg")
+	if err != nil {
+		return "", err
+	}
+	if err := jpeg.Encode(out, dst, &jpeg.Options{Quality: quality}); err != nil {
+		out.Close()
+		os.Remove(out.Name())
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(out.Name())
+		return "", err
+	}
+	if srcInfo, err := os.Stat(path); err == nil {
+		if info, err := os.Stat(out.Name()); err == nil && info.Size() >= srcInfo.Size() {
+			os.Remove(out.Name())
+			return "", fmt.Errorf("image is not smaller as JPEG")
+		}
+	}
+	return out.Name(), nil
+}
+
+// isOpaque reports whether an image has no transparent pixels
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return true
+}
+
+// shrinkImage scales img by scale (at most 1), averaging the source pixels that
+// fall in each destination pixel
+func shrinkImage(img image.Image, scale float64) *image.RGBA {
+	bounds := img.Bounds()
+	w, h := int(float64(bounds.Dx())*scale+0.5), int(float64(bounds.Dy())*scale+0.5)
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/h
+		y1 := bounds.Min.Y + (y+1)*bounds.Dy()/h
+		for x := 0; x < w; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/w
+			x1 := bounds.Min.X + (x+1)*bounds.Dx()/w
+			var r, g, bl, n uint64
+			for sy := y0; sy < y1 || sy == y0; sy++ {
+				for sx := x0; sx < x1 || sx == x0; sx++ {
+					cr, cg, cb, _ := img.At(sx, sy).RGBA()
+					r, g, bl, n = r+uint64(cr), g+uint64(cg), bl+uint64(cb), n+1
+				}
+			}
+			dst.Set(x, y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(bl / n), 0xffff})
+		}
+	}
+	return dst
+}
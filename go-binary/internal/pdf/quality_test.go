@@ -0,0 +1,136 @@
+package pdf
+
+import (
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestImage encodes img to dir as a PNG, or a JPEG when asJPEG is set
+func writeTestImage(t *testing.T, dir, name string, img image.Image, asJPEG bool) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if asJPEG {
+		err = jpeg.Encode(file, img, &jpeg.Options{Quality: 95})
+	} else {
+		err = png.Encode(file, img)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// noiseImage is an opaque w x h image of random pixels, which PNG stores poorly
+func noiseImage(w, h int) *image.RGBA {
+	rng := rand.New(rand.NewSource(1))
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = uint8(rng.Intn(256)), uint8(rng.Intn(256)), uint8(rng.Intn(256)), 255
+	}
+	return img
+}
+
+func TestShrinkImage(t *testing.T) {
+	// A 4x2 image of a black and a white 2x2 block averages to one pixel each
+	src := image.NewRGBA(image.Rect(10, 20, 14, 22))
+	for y := 20; y < 22; y++ {
+		for x := 10; x < 14; x++ {
+			if x >= 12 {
+				src.Set(x, y, color.White)
+			} else {
+				src.Set(x, y, color.Black)
+			}
+		}
+	}
+	dst := shrinkImage(src, 0.5)
+	if dst.Bounds() != image.Rect(0, 0, 2, 1) {
+		t.Fatalf("bounds %v, want 2x1", dst.Bounds())
+	}
+	if got := dst.RGBAAt(0, 0); got != (color.RGBA{0, 0, 0, 255}) {
+		t.Errorf("left pixel %v, want black", got)
+	}
+	if got := dst.RGBAAt(1, 0); got != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("right pixel %v, want white", got)
+	}
+
+	// Mixed pixels average, and a scale too small for a pixel still keeps one
+	gray := shrinkImage(src, 0.25)
+	if gray.Bounds() != image.Rect(0, 0, 1, 1) {
+		t.Fatalf("bounds %v, want 1x1", gray.Bounds())
+	}
+	if got := gray.RGBAAt(0, 0); got.R != 127 || got.G != 127 || got.B != 127 {
+		t.Errorf("pixel %v, want the gray average", got)
+	}
+	if tiny := shrinkImage(src, 0.01); tiny.Bounds() != image.Rect(0, 0, 1, 1) {
+		t.Errorf("bounds %v at scale 0.01, want 1x1", tiny.Bounds())
+	}
+
+	same := shrinkImage(src, 1)
+	if same.Bounds().Size() != src.Bounds().Size() || same.RGBAAt(3, 1) != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("scale 1 changed the image: bounds %v", same.Bounds())
+	}
+}
+
+func TestDownsampleImage(t *testing.T) {
+	dir := t.TempDir()
+	large := writeTestImage(t, dir, "large.png", noiseImage(400, 300), false)
+
+	scaled, err := downsampleImage(large, 100, 100, 75, dir)
+	if err != nil {
+		t.Fatalf("downsampleImage: %v", err)
+	}
+	file, err := os.Open(scaled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, format, err := image.DecodeConfig(file)
+	file.Close()
+	if err != nil || format != "jpeg" || config.Width != 100 || config.Height != 75 {
+		t.Errorf("wrote a %dx%d %s (%v), want a 100x75 JPEG fitted by width", config.Width, config.Height, format, err)
+	}
+	if filepath.Dir(scaled) != dir {
+		t.Errorf("wrote %s, want it in %s", scaled, dir)
+	}
+
+	// Fitted by height when that is the tighter bound
+	if scaled, err = downsampleImage(large, 1000, 150, 75, dir); err != nil {
+		t.Fatalf("downsampleImage: %v", err)
+	}
+	file, _ = os.Open(scaled)
+	config, _, _ = image.DecodeConfig(file)
+	file.Close()
+	if config.Width != 200 || config.Height != 150 {
+		t.Errorf("wrote %dx%d, want 200x150", config.Width, config.Height)
+	}
+
+	notImage := filepath.Join(dir, "notes.png")
+	if err := os.WriteFile(notImage, []byte("not a PNG"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for name, path := range map[string]string{
+		"transparent":         writeTestImage(t, dir, "transparent.png", image.NewNRGBA(image.Rect(0, 0, 400, 300)), false),
+		"small JPEG":          writeTestImage(t, dir, "small.jpg", noiseImage(50, 40), true),
+		"no smaller than PNG": writeTestImage(t, dir, "flat.png", image.NewGray(image.Rect(0, 0, 400, 300)), false),
+		"not an image":        notImage,
+		"missing":             filepath.Join(dir, "absent.png"),
+	} {
+		if scaled, err := downsampleImage(path, 100, 100, 75, dir); err == nil {
+			t.Errorf("%s: downsampled to %s, want it left as it is", name, scaled)
+		}
+	}
+	// Only the two images downsampled above were kept
+	if temps, _ := filepath.Glob(filepath.Join(dir, "gopdfconv-image-*.jpg")); len(temps) != 2 {
+		t.Errorf("%d downsampled images in the temp dir, want 2", len(temps))
+	}
+}
@@ -0,0 +1,136 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pages of Options.SignaturePages
+const (
+	SignatureLastPage = "last"
+	SignatureAllPages = "all"
+)
+
+// Positions of Options.SignaturePosition
+const (
+	SignatureRight  = "right"
+	SignatureLeft   = "left"
+	SignatureCenter = "center"
+)
+
+const (
+	signatureWidth       = 180.0 // Width of the signature block, at most the content width
+	signatureImageHeight = 40.0  // Height of the box the signature image is fitted into
+	signatureFontSize    = 9.0
+	signatureGap         = 12.0 // Space between the block and the content above it
+)
+
+// HasSignature reports whether a signature stamp is drawn
+func (o Options) HasSignature() bool {
+	return o.SignatureImage != "" || strings.TrimSpace(o.SignatureText) != ""
+}
+
+// SignatureOnAllPages reports whether the signature stamp is drawn on every page
+// rather than only the last one
+func (o Options) SignatureOnAllPages() bool {
+	return o.HasSignature() && strings.ToLower(o.SignaturePages) == SignatureAllPages
+}
+
+// signatureLayout returns the width of the signature block, its text lines and its
+// height, gap included. It leaves the signature font set when there is text.
+func (b *Builder) signatureLayout() (float64, []string, float64) {
+	width := signatureWidth
+	if w := b.options.ContentWidth(); w < width {
+		width = w
+	}
+	height := signatureGap
+	if b.options.SignatureImage != "" {
+		height += signatureImageHeight + 4
+	}
+
+	var lines []string
+	if text := strings.TrimSpace(b.options.SignatureText); text != "" {
+		b.setFont("default", "", signatureFontSize)
+		for _, para := range strings.Split(b.resolvePlaceholders(text), "\n") {
+			lines = append(lines, b.wrapText(para, width)...)
+		}
+		height += float64(len(lines)) * signatureFontSize * 1.2
+	}
+	return width, lines, height
+}
+
+// drawSignature draws the signature stamp at the bottom of the current page's
+// content area, above any footnotes and the footer, and returns its height. The
+// image sits on a rule with the text below it, all aligned to the side given by
+// Options.SignaturePosition.
+func (b *Builder) drawSignature() float64 {
+	width, lines, height := b.signatureLayout()
+	left := b.options.Margin
+	switch strings.ToLower(b.options.SignaturePosition) {
+	case SignatureLeft:
+	case SignatureCenter:
+		left += (b.options.ContentWidth() - width) / 2
+	default:
+		left += b.options.ContentWidth() - width
+	}
+	top := b.pageHeight() - b.options.Margin - b.footerBand - b.footnoteBand - height + signatureGap
+
+	if b.options.SignatureImage != "" {
+		imgW, imgH, err := ImageSize(b.options.SignatureImage)
+		if err != nil {
+			if b.err == nil {
+				b.err = fmt.Errorf("signature image: %v", err)
+			}
+			return height
+		}
+		scale := width / imgW
+		if signatureImageHeight/imgH < scale {
+			scale = signatureImageHeight / imgH
+		}
+		drawW, drawH := imgW*scale, imgH*scale
+		x := b.signatureX(left, width, drawW)
+		if err := b.addImage(b.options.SignatureImage, x, top+signatureImageHeight-drawH, drawW, drawH); err != nil && b.err == nil {
+			b.err = fmt.Errorf("signature image: %v", err)
+		}
+
+		b.setStrokeColor(ColorGray)
+		b.pdf.SetLineWidth(0.5)
+		b.pdf.Line(left, top+signatureImageHeight+2, left+width, top+signatureImageHeight+2)
+		top += signatureImageHeight + 4
+	}
+
+	if len(lines) > 0 {
+		b.setFont("default", "", signatureFontSize)
+		b.setTextColor(ColorDarkGray)
+		for i, line := range lines {
+			b.pdf.SetX(b.signatureX(left, width, b.MeasureTextWidth(line)))
+			b.pdf.SetY(top + signatureFontSize + float64(i)*signatureFontSize*1.2)
+			b.pdf.Text(line)
+		}
+	}
+	return height
+}
+
+// signatureX returns the left edge of an item w wide in the signature block at left
+func (b *Builder) signatureX(left, width, w float64) float64 {
+	switch strings.ToLower(b.options.SignaturePosition) {
+	case SignatureLeft:
+		return left
+	case SignatureCenter:
+		return left + (width-w)/2
+	}
+	return left + width - w
+}
+
+// stampLastPage draws the signature stamp on the last page, on a page of its own
+// when the content leaves no room for it. Called by Save.
+func (b *Builder) stampLastPage() {
+	if !b.options.HasSignature() || b.options.SignatureOnAllPages() || b.pageNum == 0 {
+		return
+	}
+	_, _, height := b.signatureLayout()
+	if b.NeedsNewPage(height) {
+		b.addPage()
+	}
+	b.drawSignature()
+}
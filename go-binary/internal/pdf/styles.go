@@ -21,12 +21,24 @@ var (
 	PageTabloid = PageSize{Width: 792, Height: 1224} // 11 x 17 inches - best for wide tables
 )
 
+// PageSizes maps the page size names accepted by the CLI to their dimensions
+var PageSizes = map[string]PageSize{
+	"a4":      PageA4,
+	"letter":  PageLetter,
+	"legal":   PageLegal,
+	"a3":      PageA3,
+	"tabloid": PageTabloid,
+}
+
 // Orientation constants
 type Orientation string
 
 const (
 	Portrait  Orientation = "portrait"
 	Landscape Orientation = "landscape"
+
+	// MixedOrientation is reported by Layout for documents with pages in both orientations
+	MixedOrientation Orientation = "mixed"
 )
 
 // Alignment constants
@@ -34,6 +46,9 @@ const (
 	AlignLeft   = 0
 	AlignCenter = 1
 	AlignRight  = 2
+	// AlignJustify stretches every wrapped line but a paragraph's last to the full
+	// width by widening the gaps between words. Single lines are drawn left-aligned.
+	AlignJustify = 3
 )
 
 // Color represents RGB color values
@@ -52,6 +67,7 @@ var (
 	ColorLightBlue  = Color{230, 242, 255}
 	ColorGreen      = Color{0, 153, 76}
 	ColorLightGreen = Color{230, 255, 238}
+	ColorRed        = Color{204, 0, 0}
 )
 
 // Style represents text and cell styling options
@@ -63,7 +79,7 @@ type Style struct {
 	FillColor     Color
 	BorderColor   Color
 	BorderWidth   float64
-	Alignment     int // 0=Left, 1=Center, 2=Right
+	Alignment     int // 0=Left, 1=Center, 2=Right, 3=Justify
 	Padding       float64
 	LineHeight    float64
 	HasBackground bool
@@ -136,43 +152,115 @@ type Options struct {
 	FontSize     float64
 	Margin       float64
 	HeaderRow    bool
+	HeaderRows   int  // Number of header rows when HeaderRow is set (default 1); rows above the last are group headers
+	AutoDetectHeader bool // CSV/Excel: decide HeaderRow from the data (a text first row over numeric columns is a header)
 	AutoWidth    bool
 	Title        string
 	Author       string
 	Subject      string
 	Compression  bool
-	Quality      string // "fast", "balanced", "best"
-	HeaderText   string
-	FooterText   string
+	Quality      string // "best", "balanced" and "fast" embed images as they are; "compact", "small" and "minimum" downsample them to 150, 96 and 72 dpi JPEG
+	MaxOutputBytes int64 // Convert again at lower Quality levels while the PDF (or any split part) is larger than this (0 = no limit)
+	HeaderText   string // Supports {{page}}, {{total}}, {{section_page}}, {{section_total}}, {{date}} and {{time}}
+	FooterText   string // Supports {{page}}, {{total}}, {{section_page}}, {{section_total}}, {{date}} and {{time}}
+	HeaderFooterOverflow string // Long header/footer text: "wrap" (default, up to 3 lines) or "truncate"
+	PageNumberStart int // Number shown on the first page (default 1), to continue numbering from a previous document
+	PDFA            bool // Archival PDF/A-1b output. Only LibreOffice rendering (PowerPoint, XLS) supports it; natively rendered files fail with UNSUPPORTED_FORMAT
+	MaxPagesPerFile int // Split the output into name_part1.pdf, name_part2.pdf, ... of at most this many pages (0 = no split). Each part has its own page numbers and totals, and peak memory follows the part size
+	PageRange       string // Keep only these pages of the finished PDF, e.g. "1-10,15" or "3-" (empty = all); not with PDFA or MaxPagesPerFile
+	Thumbnail       string // Also render the first page of the output as a PNG at this path, with pdftoppm or LibreOffice; fails with INVALID_OPTION when neither is installed
+	ThumbnailWidth  int    // Width of the Thumbnail in pixels (default 300); its height follows the page's aspect ratio
 
 	AutoOrientation bool
+	LineNumbers     bool   // Text inputs: number each source line in a left gutter
+	ImageFit        string // Image inputs: "fit" (default, whole image inside the margins) or "fill" (cover the content area, cropping overflow)
 	
 	// Advanced Features
 	CustomFontPath string
+	RequireFont    bool   // Fail instead of falling back to another font when CustomFontPath can't be loaded (or no font at all can)
 	WatermarkText  string
 	WatermarkImage string
 	WatermarkAlpha float64
+	WatermarkTile  bool    // Repeat WatermarkText over the whole page in a diagonal grid instead of once in the center
+	WatermarkAngle float64 // Degrees WatermarkText is turned counterclockwise (default 45; 0 = horizontal)
+	SignatureImage    string // PNG/JPEG of a signature, stamped with SignatureText as a visible signature block (not a cryptographic signature)
+	SignatureText     string // Text below the signature image, e.g. "Signed by Jane Doe on {{date}}"; supports {{page}}, {{date}} and {{time}}
+	SignaturePosition string // Side of the page the signature block sits on, above the footer: "right" (default), "left" or "center"
+	SignaturePages    string // "last" (default) stamps the last page, on a page of its own if it is full; "all" stamps every page
+	TempDir        string // Directory for LibreOffice profiles and intermediate files ("" = $TMPDIR or the OS default)
 	
 	// Table Styling
 	HeaderColor      string  // Hex color for header background
 	HeaderTextColor  string  // Hex color for header text
-	RowColor         string  // Hex color for even rows (alternating)
+	RowColor         string  // Hex color for even rows (alternating); used as ZebraColor when that is unset
+	ZebraColor       string  // Hex color for shaded rows (default RowColor, else light gray)
+	ZebraInterval    int     // Shade every Nth data row (default 2, i.e. alternate rows; 1 shades all)
 	RowTextColor     string  // Hex color for row text
 	BorderColor      string  // Hex color for borders
 	ShowGridLines    bool
+	BorderStyle      string  // Table lines: "all" (default, every cell), "outer" (frame only), "horizontal" (rules between rows) or "none"
+	TableAlign       string  // Horizontal table placement when narrower than the page: "left" (default), "center", "right"
+	PerCellAlignment bool    // Align each table cell by its own content (numbers right) instead of by its column's inferred type
+	TextAlign        string  // Alignment of wrapped paragraphs in text and PPT output: "left" (default), "justify"
+	LineHeight       float64 // Text line spacing as a multiple of the font size (default 1.2)
+	ParagraphSpacing float64 // Extra points after each paragraph of text and slide content (default 6)
+	TextColumns      int     // Text and PPT output: newspaper-style columns per page (default 1)
+	TableCaption     string  // Bold caption drawn above the table
+	EmptyDataMessage string  // Shown in place of the rows of a table without data (default "No data"; "" shows nothing, and an empty CSV file is an error)
+	CellRange        string  // Excel/ODS: convert only this block of each sheet, e.g. "A1:F50", "B:D" or "3:10" (empty = whole sheet)
+	SlideRange       string  // PowerPoint: convert only these slides, counted from 1 in deck order, e.g. "2-5,8" or "10-" (empty = all)
+	RasterizeFallback bool   // PowerPoint, native rendering: draw slides with charts, tables, SmartArt or grouped shapes as an image of the slide made by LibreOffice and pdftoppm, when both are installed
+	RasterDPI        int     // Resolution of RasterizeFallback slide images (default 150)
+	JSONFields       string  // NDJSON: comma-separated keys to use as columns, in order (empty = the first object's keys)
+	Transpose        bool    // Swap rows and columns, e.g. to list a single record's fields as label/value rows; the table then has no header row
+	AutolinkURLs     bool    // Make table cells whose text is an http(s) URL clickable links, drawn in blue
+	ShowSheetTitles  bool    // Excel: caption each sheet's table with the sheet name (default off)
+	SheetTabColors   bool    // Excel: mark the first page of each sheet with a bar in its tab color (sheets without one are skipped)
+	RespectFreezePanes bool  // Excel: rows frozen at the top of a sheet (at most 5) become its header rows, repeated on every page, and autofilter columns get a filter arrow in the header; a sheet's autofilter row is its header when nothing is frozen
+	RenderConditionalFormatting bool // Excel: fill cells per their conditional formatting (value thresholds, top/bottom, average, 2/3-color scales)
+	RenderComments   string  // Excel cell comments: "off" (default), "footnote" (numbered notes at the bottom of the page) or "annotation" (PDF note icons)
+	Legend           map[string]string // Codes used in the data and what they mean, listed in a Code/Description table on a last page of their own (native output)
 	
 	// Row & Cell Customization
 	RowHeight        float64 // Custom row height (0 = auto)
 	HeaderHeight     float64 // Custom header row height (0 = auto)
 	CellPadding      float64 // Cell padding in points (default 4)
 	MinColumnWidth   float64 // Minimum column width (default 40)
-	MaxColumnWidth   float64 // Maximum column width (default 180)
+	MaxColumnWidth   float64 // Maximum column width (default 180). Longer cells wrap onto extra lines, never truncate, so a higher max trades row height for width
+	ShrinkTextToFit  bool    // Same as CellOverflow "shrink"; used when CellOverflow is unset
+	CellOverflow     string  // Cell text wider than its column: "wrap" (default, onto extra lines), "ellipsis" (one line ending in "..."), "clip" (one line cut at the edge) or "shrink" (smaller font, down to 5pt, for a longest word that doesn't fit, then wrap)
+	BreakLongWords   bool    // Wrapped cells: break a word wider than its column (a long URL or hash) across lines (default true), instead of cutting it to one line ending in "..."
 	
 	// Font Styling
 	HeaderFontSize   float64 // Header font size (0 = use FontSize + 1)
 	HeaderFontBold   bool    // Make header text bold (default true)
+	RotateHeaders    bool    // Draw header labels rotated 90° in a taller band, so columns can be as narrow as their data
+
+	// Row Filtering
+	DropEmptyRows    bool    // Skip data rows where every cell is blank
+	Deduplicate      bool    // Skip exact duplicate data rows, keeping the first occurrence
+	MaxMemoryBytes   int64   // Abort with MEMORY_LIMIT once this much cell text has been read (0 = unlimited)
+	Filter           string  // Keep only rows matching "<column> <op> <value>" (e.g. "col3 > 100", "status == active")
+
+	// Pipeline Hooks (run by converter.Convert; not available from the CLI)
+	PreProcess       func(inputPath string) (string, error) `json:"-"` // May return a substitute input (e.g. a decrypted temp file), removed after conversion
+	PostProcess      func(outputPath string) error          `json:"-"` // Runs after a successful conversion (e.g. stamp or upload the PDF)
+
+	// Localization
+	Locale           string  // Locale for booleans and number formatting (e.g. "en_US", "de_DE"); empty keeps values as-is
+	NumberLocale     string  // Locale the input's numbers are written in, for header detection and Filter (default Locale; ";"-delimited CSV files default to comma decimals)
+	DateFormat       string  // Layout for {{date}}: "iso", "short", "rfc1123", "rfc3339" or a Go layout
+	Timezone         string  // IANA timezone for {{date}}/{{time}} (e.g. "Europe/Berlin"; empty = server local)
 }
 
+// Column width limits in points
+const (
+	DefaultMinColumnWidth = 40.0
+	DefaultMaxColumnWidth = 180.0
+	// MinReadableColumnWidth is the floor applied when columns are scaled down to fit the page (~5-6 chars)
+	MinReadableColumnWidth = 35.0
+)
+
 // DefaultOptions returns sensible default options
 func DefaultOptions() Options {
 	return Options{
@@ -182,18 +270,35 @@ func DefaultOptions() Options {
 		FontSize:        10,
 		Margin:          20,
 		HeaderRow:       true,
+		HeaderRows:      1,
 		AutoWidth:       true,
 		Compression:     true,
 		Quality:         "balanced",
+		HeaderFooterOverflow: "wrap",
+		PageNumberStart: 1,
 		WatermarkAlpha:  0.2,
+		WatermarkAngle:  45,
 		ShowGridLines:   true,
+		BorderStyle:     "all",
+		TableAlign:      "left",
+		TextAlign:       "left",
+		LineHeight:      1.2,
+		ParagraphSpacing: 6,
+		TextColumns:     1,
+		EmptyDataMessage: "No data",
+		RenderComments:  CommentsOff,
+		ZebraInterval:   2,
 		AutoOrientation: true,
+		ImageFit:        "fit",
+		RasterDPI:       150,
+		ThumbnailWidth:  300,
 		// Row & Cell defaults
 		RowHeight:       0,   // Auto
 		HeaderHeight:    0,   // Auto
 		CellPadding:     4,
-		MinColumnWidth:  40,
-		MaxColumnWidth:  180,
+		MinColumnWidth:  DefaultMinColumnWidth,
+		MaxColumnWidth:  DefaultMaxColumnWidth,
+		BreakLongWords:  true,
 		// Font defaults
 		HeaderFontSize:  0,    // Auto (FontSize + 1)
 		HeaderFontBold:  true,
@@ -202,6 +307,55 @@ func DefaultOptions() Options {
 
 
 
+// HeaderRowCount returns the number of header rows a table has when it has a header
+func (o Options) HeaderRowCount() int {
+	if o.HeaderRows < 1 {
+		return 1
+	}
+	return o.HeaderRows
+}
+
+// ColumnWidthLimits returns the configured min/max column widths, using the defaults for unset values
+func (o Options) ColumnWidthLimits() (float64, float64) {
+	minWidth, maxWidth := o.MinColumnWidth, o.MaxColumnWidth
+	if minWidth <= 0 {
+		minWidth = DefaultMinColumnWidth
+	}
+	if maxWidth <= 0 {
+		maxWidth = DefaultMaxColumnWidth
+	}
+	if maxWidth < minWidth {
+		maxWidth = minWidth
+	}
+	return minWidth, maxWidth
+}
+
+// ScaledColumnFloor returns the smallest width a column may be scaled down to when fitting the page
+func (o Options) ScaledColumnFloor() float64 {
+	minWidth, _ := o.ColumnWidthLimits()
+	if minWidth < MinReadableColumnWidth {
+		return minWidth
+	}
+	return MinReadableColumnWidth
+}
+
+// TextAlignment returns the Style.Alignment for wrapped paragraphs set by TextAlign
+func (o Options) TextAlignment() int {
+	if o.TextAlign == "justify" {
+		return AlignJustify
+	}
+	return AlignLeft
+}
+
+// TextLineHeight returns the distance between lines of text in the given font size,
+// using style's LineHeight when Options.LineHeight is unset
+func (o Options) TextLineHeight(fontSize float64, style Style) float64 {
+	if o.LineHeight > 0 {
+		return fontSize * o.LineHeight
+	}
+	return fontSize * style.LineHeight
+}
+
 // GetPageRect returns the gopdf.Rect for the configured page size and orientation
 func (o Options) GetPageRect() *gopdf.Rect {
 	w, h := o.PageSize.Width, o.PageSize.Height
@@ -0,0 +1,334 @@
+package pdf
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/signintech/gopdf"
+)
+
+// RichText is the text of a text block with links and formatting over byte ranges
+// of it (AddRichTextBlock)
+type RichText struct {
+	Text    string
+	Links   []TextLink
+	Formats []TextFormat
+}
+
+// TextFormat draws the bytes Start to End of a text block in bold, italic and/or
+// underlined, on top of the block's style
+type TextFormat struct {
+	Start, End              int
+	Bold, Italic, Underline bool
+}
+
+// flags returns the gopdf style of the format
+func (f TextFormat) flags() int {
+	flags := gopdf.Regular
+	if f.Bold {
+		flags |= gopdf.Bold
+	}
+	if f.Italic {
+		flags |= gopdf.Italic
+	}
+	if f.Underline {
+		flags |= gopdf.Underline
+	}
+	return flags
+}
+
+// fontStyleFlags returns the gopdf style of a Style.FontStyle such as "B" or "BI"
+func fontStyleFlags(style string) int {
+	flags := gopdf.Regular
+	style = strings.ToUpper(style)
+	if strings.Contains(style, "B") {
+		flags |= gopdf.Bold
+	}
+	if strings.Contains(style, "I") {
+		flags |= gopdf.Italic
+	}
+	if strings.Contains(style, "U") {
+		flags |= gopdf.Underline
+	}
+	return flags
+}
+
+// formatted reports whether any format overlaps the bytes start to end
+func formatted(formats []TextFormat, start, end int) bool {
+	for _, f := range formats {
+		if f.Start < end && f.End > start {
+			return true
+		}
+	}
+	return false
+}
+
+// formatAt returns the gopdf style of the byte at offset: base with the formats
+// covering it added
+func formatAt(formats []TextFormat, offset, base int) int {
+	for _, f := range formats {
+		if f.Start <= offset && offset < f.End {
+			base |= f.flags()
+		}
+	}
+	return base
+}
+
+// setStyledFont sets the font at size in a gopdf style, bold, italic and
+// underline combined. The bold and italic faces are loaded on first use from
+// files beside the font, such as DejaVuSans-Bold.ttf or arialbd.ttf; where there
+// is none, the regular face stands in.
+func (b *Builder) setStyledFont(flags int, size float64) {
+	if !b.fontLoaded {
+		return
+	}
+	if face := flags &^ gopdf.Underline; face != gopdf.Regular && !b.faces[face] {
+		b.loadFace(face)
+	}
+	b.pdf.SetFontWithStyle("default", flags, size)
+}
+
+// loadFace adds the bold, italic or bold italic face of the font
+func (b *Builder) loadFace(face int) {
+	if b.faces == nil {
+		b.faces = make(map[int]bool)
+	}
+	b.faces[face] = true
+
+	opt := gopdf.TtfOption{Style: face, OnGlyphNotFoundSubstitute: gopdf.DefaultOnGlyphNotFoundSubstitute}
+	if b.fontName == EmbeddedFontName {
+		b.pdf.AddTTFFontDataWithOption("default", embeddedFont, opt)
+		return
+	}
+	for _, path := range faceFiles(b.fontName, face) {
+		if b.pdf.AddTTFFontWithOption("default", path, opt) == nil {
+			return
+		}
+	}
+	b.pdf.AddTTFFontWithOption("default", b.fontName, opt)
+}
+
+// faceFiles returns the files that may hold a face of the font file path, named
+// the DejaVu/Liberation way (-Bold, -Oblique or -Italic) or the Windows way (bd, i)
+func faceFiles(path string, face int) []string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(strings.TrimSuffix(path, ext), "-Regular")
+	var suffixes []string
+	switch face {
+	case gopdf.Bold:
+		suffixes = []string{"-Bold", "bd"}
+	case gopdf.Italic:
+		suffixes = []string{"-Oblique", "-Italic", "i"}
+	default:
+		suffixes = []string{"-BoldOblique", "-BoldItalic", "bi"}
+	}
+	files := make([]string, len(suffixes))
+	for i, suffix := range suffixes {
+		files[i] = base + suffix + ext
+	}
+	return files
+}
+
+// textPiece is a part of a line of a formatted paragraph drawn in one style: a
+// word or part of one, or the space between two words
+type textPiece struct {
+	text  string
+	start int // Byte offset in the block's text
+	flags int // gopdf style
+	width float64
+}
+
+// isSpace reports whether the piece is the space between two words
+func (p textPiece) isSpace() bool {
+	return p.text == " "
+}
+
+// styledWidth returns the width of text in a gopdf style at size
+func (b *Builder) styledWidth(text string, flags int, size float64) float64 {
+	b.setStyledFont(flags, size)
+	return b.MeasureTextWidth(text)
+}
+
+// wrapRichParagraph wraps a paragraph starting at byte start of a text block to
+// width, splitting its words into pieces where the formatting changes. Words are
+// one space apart, in the format of the text between them; a word wider than the
+// line is broken between characters.
+func (b *Builder) wrapRichParagraph(para string, start int, formats []TextFormat, base int, width, size float64) [][]textPiece {
+	var lines [][]textPiece
+	var line []textPiece
+	lineWidth := 0.0
+	for pos := 0; pos < len(para); {
+		// Skip to the next word, noting the space before it
+		space := pos
+		for pos < len(para) {
+			r, n := utf8.DecodeRuneInString(para[pos:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			pos += n
+		}
+		if pos == len(para) {
+			break
+		}
+		end := pos
+		for end < len(para) {
+			r, n := utf8.DecodeRuneInString(para[end:])
+			if unicode.IsSpace(r) {
+				break
+			}
+			end += n
+		}
+		word, wordWidth := b.wordPieces(para, pos, end, start, formats, base, size)
+		pos = end
+
+		if len(line) > 0 {
+			flags := formatAt(formats, start+space, base)
+			gap := textPiece{text: " ", start: start + space, flags: flags, width: b.styledWidth(" ", flags, size)}
+			if lineWidth+gap.width+wordWidth <= width {
+				line = append(append(line, gap), word...)
+				lineWidth += gap.width + wordWidth
+				continue
+			}
+			lines = append(lines, line)
+			line, lineWidth = nil, 0
+		}
+		if wordWidth <= width {
+			line, lineWidth = word, wordWidth
+			continue
+		}
+		broken := b.breakPieces(word, width, size)
+		lines = append(lines, broken[:len(broken)-1]...)
+		line, lineWidth = broken[len(broken)-1], piecesWidth(broken[len(broken)-1])
+	}
+	return append(lines, line)
+}
+
+// wordPieces splits the word at bytes pos to end of para into pieces of one style
+// and returns them with the word's width
+func (b *Builder) wordPieces(para string, pos, end, start int, formats []TextFormat, base int, size float64) ([]textPiece, float64) {
+	var pieces []textPiece
+	width := 0.0
+	for pos < end {
+		flags := formatAt(formats, start+pos, base)
+		next := pos
+		for next < end && formatAt(formats, start+next, base) == flags {
+			_, n := utf8.DecodeRuneInString(para[next:])
+			next += n
+		}
+		piece := textPiece{text: para[pos:next], start: start + pos, flags: flags}
+		piece.width = b.styledWidth(piece.text, flags, size)
+		pieces = append(pieces, piece)
+		width += piece.width
+		pos = next
+	}
+	return pieces, width
+}
+
+// breakPieces breaks the pieces of a word wider than width into lines
+func (b *Builder) breakPieces(word []textPiece, width, size float64) [][]textPiece {
+	var lines [][]textPiece
+	var line []textPiece
+	lineWidth := 0.0
+	for _, piece := range word {
+		from := 0
+		for i, r := range piece.text {
+			w := b.styledWidth(string(r), piece.flags, size)
+			if lineWidth+w > width && (len(line) > 0 || i > from) {
+				if i > from {
+					line = append(line, b.subPiece(piece, from, i, size))
+				}
+				lines = append(lines, line)
+				line, lineWidth, from = nil, 0, i
+			}
+			lineWidth += w
+		}
+		if from < len(piece.text) {
+			line = append(line, b.subPiece(piece, from, len(piece.text), size))
+		}
+	}
+	return append(lines, line)
+}
+
+// subPiece returns the bytes from to to of a piece as a piece of its own
+func (b *Builder) subPiece(p textPiece, from, to int, size float64) textPiece {
+	text := p.text[from:to]
+	return textPiece{text: text, start: p.start + from, flags: p.flags, width: b.styledWidth(text, p.flags, size)}
+}
+
+// piecesWidth returns the width of a line of pieces
+func piecesWidth(pieces []textPiece) float64 {
+	width := 0.0
+	for _, p := range pieces {
+		width += p.width
+	}
+	return width
+}
+
+// drawRichLine draws a line of pieces in the box of a text block at x, w wide,
+// aligned like addTextBlock's lines, and covers its linked pieces with links.
+// Runs of pieces in one style are drawn together, so the text copies with its
+// spaces; justified lines get their spaces widened and are drawn piece by piece.
+func (b *Builder) drawRichLine(pieces []textPiece, x, top, baseline, w, height float64, align int, justify bool, size float64, links []TextLink) {
+	lineWidth := piecesWidth(pieces)
+	extra := 0.0
+	switch {
+	case justify:
+		spaces := 0
+		for _, p := range pieces {
+			if p.isSpace() {
+				spaces++
+			}
+		}
+		if spaces > 0 && lineWidth < w {
+			extra = (w - lineWidth) / float64(spaces)
+		}
+	case align == AlignCenter:
+		x += (w - lineWidth) / 2
+	case align == AlignRight:
+		x += w - lineWidth
+	}
+
+	// Left edge of each piece, and the end of the line
+	xs := make([]float64, len(pieces)+1)
+	xs[0] = x
+	for i, p := range pieces {
+		xs[i+1] = xs[i] + p.width
+		if p.isSpace() {
+			xs[i+1] += extra
+		}
+	}
+
+	for i := 0; i < len(pieces); {
+		j := i + 1
+		for extra == 0 && j < len(pieces) && pieces[j].flags == pieces[i].flags {
+			j++
+		}
+		var text strings.Builder
+		for _, p := range pieces[i:j] {
+			text.WriteString(p.text)
+		}
+		// A lone space is drawn only to underline it
+		if !(j == i+1 && pieces[i].isSpace()) || pieces[i].flags&gopdf.Underline != 0 {
+			b.setStyledFont(pieces[i].flags, size)
+			b.pdf.SetX(xs[i])
+			b.pdf.SetY(baseline)
+			b.pdf.Text(text.String())
+		}
+		i = j
+	}
+
+	current, linkX := -1, 0.0
+	for i, p := range pieces {
+		if link := linkAt(links, p.start, p.start+len(p.text)); link != current {
+			if current >= 0 {
+				b.addTextLink(links[current], linkX, top, xs[i]-linkX, height)
+			}
+			current, linkX = link, xs[i]
+		}
+	}
+	if current >= 0 {
+		b.addTextLink(links[current], linkX, top, xs[len(pieces)]-linkX, height)
+	}
+}
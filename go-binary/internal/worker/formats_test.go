@@ -0,0 +1,222 @@
+package worker
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/converter"
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+)
+
+// formatFixture writes a small input of one format to a path ending in ext
+type formatFixture struct {
+	ext   string
+	write func(t *testing.T, path string)
+	// Converted from this format's fixture by LibreOffice (legacy binary formats)
+	from converter.FormatType
+}
+
+var formatFixtures = map[converter.FormatType]formatFixture{
+	converter.FormatCSV:    {ext: ".csv", write: writeText("Name,Amount\nAda,1.50\nBob,2\n")},
+	converter.FormatTSV:    {ext: ".tsv", write: writeText("Name\tAmount\nAda\t1.50\nBob\t2\n")},
+	converter.FormatXLSX:   {ext: ".xlsx", write: writeWorkbook},
+	converter.FormatXLSM:   {ext: ".xlsm", write: writeWorkbook},
+	converter.FormatXLS:    {ext: ".xls", from: converter.FormatXLSX},
+	converter.FormatODS:    {ext: ".ods", write: writeODS},
+	converter.FormatPPTX:   {ext: ".pptx", write: writePPTX},
+	converter.FormatPPT:    {ext: ".ppt", from: converter.FormatPPTX},
+	converter.FormatPNG:    {ext: ".png", write: writeImage(png.Encode)},
+	converter.FormatJPEG:   {ext: ".jpg", write: writeImage(func(w io.Writer, img image.Image) error { return jpeg.Encode(w, img, nil) })},
+	converter.FormatText:   {ext: ".txt", write: writeText("A plain text report.\nIt has two lines.\n")},
+	converter.FormatNDJSON: {ext: ".ndjson", write: writeText("{\"name\":\"Ada\",\"amount\":1.5}\n{\"name\":\"Bob\",\"amount\":2}\n")},
+}
+
+func writeText(content string) func(*testing.T, string) {
+	return func(t *testing.T, path string) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func writeWorkbook(t *testing.T, path string) {
+	f := excelize.NewFile()
+	defer f.Close()
+	for i, row := range [][]interface{}{{"Name", "Amount"}, {"Ada", 1.5}, {"Bob", 2}} {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		f.SetSheetRow("Sheet1", cell, &row)
+	}
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeImage(encode func(io.Writer, image.Image) error) func(*testing.T, string) {
+	return func(t *testing.T, path string) {
+		img := image.NewRGBA(image.Rect(0, 0, 40, 30))
+		for x := 0; x < 40; x++ {
+			img.Set(x, x*30/40, color.RGBA{200, 30, 30, 255})
+		}
+		var buf bytes.Buffer
+		if err := encode(&buf, img); err != nil {
+			t.Fatal(err)
+		}
+		writeText(buf.String())(t, path)
+	}
+}
+
+// writeZip writes parts, in order, to a zip archive at path
+func writeZip(t *testing.T, path string, parts [][2]string) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, part := range parts {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: part[0], Method: zip.Store})
+		if err == nil {
+			_, err = w.Write([]byte(part[1]))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	writeText(buf.String())(t, path)
+}
+
+func writeODS(t *testing.T, path string) {
+	cell := func(text string) string {
+		return `<table:table-cell office:value-type="string"><text:p>` + text + `</text:p></table:table-cell>`
+	}
+	var rows strings.Builder
+	for _, row := range [][2]string{{"Name", "Amount"}, {"Ada", "1.50"}, {"Bob", "2"}} {
+		rows.WriteString("<table:table-row>" + cell(row[0]) + cell(row[1]) + "</table:table-row>")
+	}
+	writeZip(t, path, [][2]string{
+		{"mimetype", "application/vnd.oasis.opendocument.spreadsheet"},
+		{"META-INF/manifest.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<manifest:manifest xmlns:manifest="urn:oasis:names:tc:opendocument:xmlns:manifest:1.0"><manifest:file-entry manifest:full-path="/" manifest:media-type="application/vnd.oasis.opendocument.spreadsheet"/><manifest:file-entry manifest:full-path="content.xml" manifest:media-type="text/xml"/></manifest:manifest>`},
+		{"content.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" office:version="1.2"><office:body><office:spreadsheet><table:table table:name="Sheet1">` +
+			rows.String() + `</table:table></office:spreadsheet></office:body></office:document-content>`},
+	})
+}
+
+func writePPTX(t *testing.T, path string) {
+	const ns = `xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"`
+	writeZip(t, path, [][2]string{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/ppt/presentation.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.presentation.main+xml"/><Override PartName="/ppt/slides/slide1.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slide+xml"/></Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="ppt/presentation.xml"/></Relationships>`},
+		{"ppt/presentation.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:presentation ` + ns + `><p:sldIdLst><p:sldId id="256" r:id="rId2"/></p:sldIdLst><p:sldSz cx="9144000" cy="6858000"/><p:notesSz cx="6858000" cy="9144000"/></p:presentation>`},
+		{"ppt/_rels/presentation.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide" Target="slides/slide1.xml"/></Relationships>`},
+		{"ppt/slides/slide1.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:sld ` + ns + `><p:cSld><p:spTree><p:nvGrpSpPr><p:cNvPr id="1" name=""/><p:cNvGrpSpPr/><p:nvPr/></p:nvGrpSpPr><p:grpSpPr/><p:sp><p:nvSpPr><p:cNvPr id="2" name="Text"/><p:cNvSpPr txBox="1"/><p:nvPr/></p:nvSpPr><p:spPr><a:xfrm><a:off x="914400" y="914400"/><a:ext cx="7315200" cy="1828800"/></a:xfrm></p:spPr><p:txBody><a:bodyPr/><a:lstStyle/><a:p><a:r><a:t>Quarterly report</a:t></a:r></a:p></p:txBody></p:sp></p:spTree></p:cSld></p:sld>`},
+	})
+}
+
+// libreOffice returns the LibreOffice binary the converters would use, if any
+func libreOffice() (string, bool) {
+	c := converter.NewPPTXConverter()
+	return c.GetLibreOfficePath(), c.HasLibreOffice()
+}
+
+// writeFixture writes the fixture of format to dir, skipping t when it can only
+// be made by LibreOffice and LibreOffice isn't installed
+func writeFixture(t *testing.T, dir string, format converter.FormatType) string {
+	t.Helper()
+	fixture, ok := formatFixtures[format]
+	if !ok {
+		t.Fatalf("no fixture for registered format %q: add one to formatFixtures", format)
+	}
+	path := filepath.Join(dir, "input"+fixture.ext)
+	if fixture.from == "" {
+		fixture.write(t, path)
+		return path
+	}
+	soffice, ok := libreOffice()
+	if !ok {
+		t.Skipf("%s inputs are made and converted by LibreOffice, which isn't installed", format)
+	}
+	source := writeFixture(t, dir, fixture.from)
+	if err := converter.NewLibreOfficeConverter(soffice, dir).ConvertTo(source, path, strings.TrimPrefix(fixture.ext, ".")); err != nil {
+		t.Fatalf("making the %s fixture: %v", format, err)
+	}
+	return path
+}
+
+// buildGopdfconv builds the CLI into dir for the subprocess path, skipping t
+// when the go tool isn't available
+func buildGopdfconv(t *testing.T, dir string) string {
+	t.Helper()
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go tool is needed to build gopdfconv for the subprocess path")
+	}
+	bin := filepath.Join(dir, "gopdfconv")
+	if output, err := exec.Command(goTool, "build", "-o", bin, "github.com/nikunjkothiya/gopdfconv/cmd/gopdfconv").CombinedOutput(); err != nil {
+		t.Fatalf("building gopdfconv: %v\n%s", err, output)
+	}
+	return bin
+}
+
+// TestRegisteredFormats converts every registered format with its format left
+// empty, in this process through converter.Convert and the pool, and in a child
+// gopdfconv process: each detects the format from the extension and reports the
+// same stats
+func TestRegisteredFormats(t *testing.T) {
+	bin := buildGopdfconv(t, t.TempDir())
+	for _, format := range converter.RegisteredFormats() {
+		format := format
+		t.Run(string(format), func(t *testing.T) {
+			dir := t.TempDir()
+			input := writeFixture(t, dir, format)
+			if got := converter.DetectFormat(input); got != format {
+				t.Fatalf("DetectFormat(%s) = %q, want %q", filepath.Base(input), got, format)
+			}
+
+			conv, err := converter.Convert(input, filepath.Join(dir, "convert.pdf"), "", pdf.DefaultOptions(), converter.RunConfig{Native: true})
+			if err != nil {
+				t.Fatalf("Convert: %v", err)
+			}
+			want := converter.StatsOf(conv)
+
+			for _, path := range []struct {
+				name       string
+				subprocess *Subprocess
+			}{
+				{"pool", nil},
+				{"subprocess", &Subprocess{Path: bin, Args: []string{"-native"}}},
+			} {
+				output := filepath.Join(dir, path.name+".pdf")
+				job := Job{ID: path.name, InputPath: input, OutputPath: output, Options: pdf.DefaultOptions()}
+				results := BatchConvert([]Job{job}, BatchOptions{Workers: 1, Native: true, Subprocess: path.subprocess})
+				if len(results) != 1 || !results[0].Success {
+					t.Fatalf("%s: results %+v, want one success", path.name, results)
+				}
+				if info, err := os.Stat(output); err != nil || info.Size() == 0 {
+					t.Fatalf("%s: no PDF written: %v", path.name, err)
+				}
+				if !reflect.DeepEqual(results[0].Stats, want) {
+					t.Errorf("%s: stats %+v, want %+v as from Convert", path.name, results[0].Stats, want)
+				}
+			}
+		})
+	}
+}
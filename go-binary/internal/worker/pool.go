@@ -3,12 +3,17 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
+	"os"
 	"runtime"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/nikunjkothiya/gopdfconv/internal/converter"
 	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
 )
 
 // Job represents a conversion task
@@ -16,7 +21,7 @@ type Job struct {
 	ID         string
 	InputPath  string
 	OutputPath string
-	Format     converter.FormatType
+	Format     converter.FormatType // Empty or FormatAuto detects the format from InputPath
 	Options    pdf.Options
 }
 
@@ -25,8 +30,13 @@ type JobResult struct {
 	Job         Job           `json:"job"`
 	Success     bool          `json:"success"`
 	Error       string        `json:"error,omitempty"`
+	ErrorCode   errors.ErrorCode `json:"error_code,omitempty"`
 	ProcessTime time.Duration `json:"process_time_ns"`
 	OutputSize  int64         `json:"output_size_bytes"`
+	OutputFiles []string      `json:"output_files,omitempty"` // Parts written instead of OutputPath when the output was split
+	Stats       *converter.Stats `json:"stats,omitempty"`     // Nil when LibreOffice rendered the PDF
+	Quality     string        `json:"quality,omitempty"`       // Quality level of the written PDF, with Options.MaxOutputBytes
+	Warnings    []string      `json:"warnings,omitempty"`      // Non-fatal issues, as in the single-file result
 }
 
 // Pool manages a pool of workers for concurrent file processing
@@ -39,18 +49,61 @@ type Pool struct {
 	cancel     context.CancelFunc
 	mu               sync.Mutex
 	isRunning        bool
+	queueMu          sync.RWMutex // Held for writing only to close jobQueue, so Submit never sends on a closed channel
+	queueClosed      bool
+	resultsOnce      sync.Once
 	libreOfficePath  string
 	native           bool
+	noClobber        bool        // Fail jobs whose output file already exists
+	subprocess       *Subprocess // Run jobs in child processes (nil = in this process)
+	onProgress       func(jobID string, percent int)
+	limiter          *tokenBucket // Caps job starts per second (nil = unlimited)
+	officeSlots      chan struct{} // Limits concurrent LibreOffice jobs to MaxLibreOfficeWorkers
+
+	// FailFast cancels the pool after the first failed job. Jobs already running
+	// finish and report their results; queued jobs are dropped without being attempted.
+	FailFast bool
+
+	// Aggregate batch progress, updated atomically by workers
+	expected  int64
+	submitted int64
+	completed int64
+	progress  chan BatchProgress
 }
 
-// NewPool creates a new worker pool
+// BatchProgress is the overall progress of the jobs submitted to a pool
+type BatchProgress struct {
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+	Percent   int `json:"percent"`
+}
+
+// Worker limits. Native conversions are pure Go and CPU-bound, so they scale with the
+// number of cores; DefaultMaxWorkers is only a safe default and can be raised (see
+// NewPoolWithMax). LibreOffice jobs each start a soffice process that needs hundreds
+// of MB and writes its own profile to disk, so beyond a few at once they mostly add
+// memory pressure and timeouts. They get a separate, fixed cap regardless of the pool size.
+const (
+	DefaultMaxWorkers     = 16
+	MaxLibreOfficeWorkers = 4
+)
+
+// NewPool creates a new worker pool with at most DefaultMaxWorkers workers
 func NewPool(workers int, libreOfficePath string) *Pool {
+	return NewPoolWithMax(workers, 0, libreOfficePath)
+}
+
+// NewPoolWithMax creates a new worker pool with at most maxWorkers workers
+// (0 = DefaultMaxWorkers, negative = no cap)
+func NewPoolWithMax(workers, maxWorkers int, libreOfficePath string) *Pool {
 	if workers <= 0 {
 		workers = runtime.NumCPU()
 	}
-	// Limit to reasonable number
-	if workers > 16 {
-		workers = 16
+	if maxWorkers == 0 {
+		maxWorkers = DefaultMaxWorkers
+	}
+	if maxWorkers > 0 && workers > maxWorkers {
+		workers = maxWorkers
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -62,7 +115,75 @@ func NewPool(workers int, libreOfficePath string) *Pool {
 		ctx:             ctx,
 		cancel:          cancel,
 		libreOfficePath: libreOfficePath,
+		progress:        make(chan BatchProgress, 1),
+		officeSlots:     make(chan struct{}, MaxLibreOfficeWorkers),
+	}
+}
+
+// Workers returns the number of workers after applying the caps
+func (p *Pool) Workers() int {
+	return p.workers
+}
+
+// SetRateLimit caps how many jobs start per second across all workers (0 = unlimited).
+// It must be called before Start.
+func (p *Pool) SetRateLimit(jobsPerSecond float64) {
+	if jobsPerSecond <= 0 {
+		p.limiter = nil
+		return
 	}
+	p.limiter = newTokenBucket(jobsPerSecond, 1)
+}
+
+// SetExpectedJobs sets the batch size used for percentages before all jobs are submitted
+func (p *Pool) SetExpectedJobs(n int) {
+	atomic.StoreInt64(&p.expected, int64(n))
+}
+
+// Progress returns a snapshot of the aggregate progress. It is safe to call from any goroutine.
+func (p *Pool) Progress() BatchProgress {
+	completed := atomic.LoadInt64(&p.completed)
+	total := atomic.LoadInt64(&p.submitted)
+	if expected := atomic.LoadInt64(&p.expected); expected > total {
+		total = expected
+	}
+
+	bp := BatchProgress{Completed: int(completed), Total: int(total)}
+	if total > 0 {
+		bp.Percent = int(completed * 100 / total)
+	}
+	return bp
+}
+
+// ProgressUpdates returns a channel receiving the aggregate progress as jobs finish.
+// Only the latest update is buffered, so a slow reader never blocks the workers.
+func (p *Pool) ProgressUpdates() <-chan BatchProgress {
+	return p.progress
+}
+
+// publishProgress records a finished job and publishes the new aggregate progress
+func (p *Pool) publishProgress() {
+	atomic.AddInt64(&p.completed, 1)
+	bp := p.Progress()
+
+	select {
+	case p.progress <- bp:
+	default:
+		// Replace the stale update nobody has read yet
+		select {
+		case <-p.progress:
+		default:
+		}
+		select {
+		case p.progress <- bp:
+		default:
+		}
+	}
+}
+
+// SetProgressCallback sets the callback for per-job progress reporting
+func (p *Pool) SetProgressCallback(callback func(jobID string, percent int)) {
+	p.onProgress = callback
 }
 
 // Start begins the worker pool
@@ -93,16 +214,52 @@ func (p *Pool) worker(id int) {
 			if !ok {
 				return
 			}
-			result := p.processJob(job)
-			select {
-			case p.results <- result:
-			case <-p.ctx.Done():
+			if p.ctx.Err() != nil {
+				// Cancelled while this job was queued
+				return
+			}
+			if p.limiter != nil {
+				if err := p.limiter.wait(p.ctx); err != nil {
+					return
+				}
+			}
+			release, ok := p.acquireOfficeSlot(job)
+			if !ok {
+				// Cancelled while waiting for a LibreOffice slot; the job is not attempted
 				return
 			}
+			result := p.processJob(job)
+			release()
+			p.publishProgress()
+			if !result.Success && p.FailFast {
+				p.cancel()
+			}
+			// A job that ran always reports its result, even after a cancel (including
+			// our own fail-fast cancel): results are read until closeResults closes them
+			p.results <- result
 		}
 	}
 }
 
+// acquireOfficeSlot waits for a LibreOffice slot when job needs one, judged by
+// the format Convert will pick and, for PowerPoint rendered natively, by
+// Options.RasterizeFallback, which runs LibreOffice and pdftoppm for some slides;
+// other native jobs keep running meanwhile. It returns the function giving the
+// slot back, and false when the pool was cancelled first.
+func (p *Pool) acquireOfficeSlot(job Job) (func(), bool) {
+	format := converter.ResolveFormat(job.Format, job.InputPath)
+	rasterizes := job.Options.RasterizeFallback && (format == converter.FormatPPTX || format == converter.FormatPPT)
+	if !rasterizes && !converter.UsesLibreOffice(format, p.native) {
+		return func() {}, true
+	}
+	select {
+	case p.officeSlots <- struct{}{}:
+		return func() { <-p.officeSlots }, true
+	case <-p.ctx.Done():
+		return nil, false
+	}
+}
+
 // processJob performs the actual conversion
 func (p *Pool) processJob(job Job) JobResult {
 	start := time.Now()
@@ -110,94 +267,99 @@ func (p *Pool) processJob(job Job) JobResult {
 		Job: job,
 	}
 
-	// Detect format if auto
-	format := job.Format
-	if format == converter.FormatAuto {
-		format = converter.DetectFormat(job.InputPath)
-	}
-
-	var err error
-
-	switch format {
-	case converter.FormatCSV:
-		csvConverter := converter.NewCSVConverter()
-		err = csvConverter.Convert(job.InputPath, job.OutputPath, job.Options)
-
-	case converter.FormatXLSX, converter.FormatXLS:
-		// For XLSX, try native first. For XLS, try LibreOffice first if available.
-		if format == converter.FormatXLS {
-			pptxConverter := converter.NewPPTXConverter()
-			if p.libreOfficePath != "" {
-				pptxConverter.SetLibreOfficePath(p.libreOfficePath)
-			}
-			if pptxConverter.HasLibreOffice() {
-				err = pptxConverter.Convert(job.InputPath, job.OutputPath, job.Options)
-			} else {
-				excelConverter := converter.NewExcelConverter()
-				err = excelConverter.Convert(job.InputPath, job.OutputPath, job.Options)
-			}
-		} else {
-			excelConverter := converter.NewExcelConverter()
-			err = excelConverter.Convert(job.InputPath, job.OutputPath, job.Options)
-		}
-
-	case converter.FormatPPTX:
-		pptxConverter := converter.NewPPTXConverter()
-		if p.libreOfficePath != "" {
-			pptxConverter.SetLibreOfficePath(p.libreOfficePath)
-		}
-		if p.native {
-			pptxConverter.SetUseLibreOffice(false)
-		}
-		err = pptxConverter.Convert(job.InputPath, job.OutputPath, job.Options)
-
-	case converter.FormatPPT:
-		// Check if LibreOffice is available for better fidelity
-		pptxConverter := converter.NewPPTXConverter()
-		if p.libreOfficePath != "" {
-			pptxConverter.SetLibreOfficePath(p.libreOfficePath)
-		}
-		if pptxConverter.HasLibreOffice() && !p.native {
-			loConverter := converter.NewLibreOfficeConverter(pptxConverter.GetLibreOfficePath())
-			err = loConverter.Convert(job.InputPath, job.OutputPath)
-		} else {
-			// Fall back to native PPT parser (text extraction only)
-			pptConverter := converter.NewPPTConverter()
-			err = pptConverter.Convert(job.InputPath, job.OutputPath, job.Options)
+	// Progress callback tagged with the job ID
+	var progressCallback func(int)
+	if p.onProgress != nil {
+		progressCallback = func(percent int) {
+			p.onProgress(job.ID, percent)
 		}
+	}
 
-	default:
-		result.Success = false
-		result.Error = "Unsupported format: " + string(format)
+	if p.subprocess != nil {
+		p.runSubprocess(job, &result)
+		result.ProcessTime = time.Since(start)
 		return result
 	}
 
+	// A cancelled pool (fail-fast or a signal) stops the job between rows and kills
+	// the programs it started, as the subprocess path does
+	conv, err := converter.ConvertCtx(p.ctx, job.InputPath, job.OutputPath, job.Format, job.Options, converter.RunConfig{
+		LibreOfficePath: p.libreOfficePath,
+		Native:          p.native,
+		OnProgress:      progressCallback,
+		NoClobber:       p.noClobber,
+	})
+
 	result.ProcessTime = time.Since(start)
 
 	if err != nil {
 		result.Success = false
 		result.Error = err.Error()
+		result.ErrorCode = errors.ErrConversionFailed
+		var convErr *errors.ConversionError
+		if stderrors.As(err, &convErr) {
+			result.ErrorCode = convErr.Code
+		}
 	} else {
 		result.Success = true
+		result.Stats = converter.StatsOf(conv)
+		result.Quality = converter.QualityOf(conv)
+		result.Warnings = converter.WarningsOf(conv)
+		files := []string{job.OutputPath}
+		if split := converter.LayoutOf(conv).OutputFiles; len(split) > 1 {
+			result.OutputFiles = split
+			files = split
+		}
+		for _, path := range files {
+			if info, statErr := os.Stat(path); statErr == nil {
+				result.OutputSize += info.Size()
+			}
+		}
 	}
 
 	return result
 }
 
-// Submit adds a job to the queue
+// Submit adds a job to the queue. Jobs submitted after CloseQueue or Stop are dropped.
 func (p *Pool) Submit(job Job) {
+	p.queueMu.RLock()
+	defer p.queueMu.RUnlock()
+	if p.queueClosed {
+		return
+	}
+
+	atomic.AddInt64(&p.submitted, 1)
 	select {
 	case p.jobQueue <- job:
 	case <-p.ctx.Done():
 	}
 }
 
+// CloseQueue signals that no more jobs will be submitted; workers exit once the
+// queue is drained. It is safe to call more than once and together with Stop.
+func (p *Pool) CloseQueue() {
+	p.queueMu.Lock()
+	defer p.queueMu.Unlock()
+	if !p.queueClosed {
+		p.queueClosed = true
+		close(p.jobQueue)
+	}
+}
+
+// closeResults closes the results channel once all workers have exited
+func (p *Pool) closeResults() {
+	p.wg.Wait()
+	p.resultsOnce.Do(func() { close(p.results) })
+}
+
 // Results returns the results channel
 func (p *Pool) Results() <-chan JobResult {
 	return p.results
 }
 
-// Stop gracefully stops the worker pool
+// Stop gracefully stops the worker pool: it closes the queue, lets the workers
+// finish the jobs already queued and then cancels the pool. Results must be read
+// until they are closed, or the workers can't hand over the last ones.
 func (p *Pool) Stop() {
 	p.mu.Lock()
 	if !p.isRunning {
@@ -207,10 +369,11 @@ func (p *Pool) Stop() {
 	p.isRunning = false
 	p.mu.Unlock()
 
-	close(p.jobQueue)
+	// A Submit blocked on a full queue returns once a worker takes a job, so
+	// CloseQueue can proceed; only FailFast cancels before the queue is drained
+	p.CloseQueue()
+	p.closeResults()
 	p.cancel()
-	p.wg.Wait()
-	close(p.results)
 }
 
 // Wait blocks until all jobs are processed
@@ -218,49 +381,122 @@ func (p *Pool) Wait() {
 	p.wg.Wait()
 }
 
+// BatchOptions configures a batch conversion
+type BatchOptions struct {
+	Workers         int
+	MaxWorkers      int // Upper bound on Workers (0 = DefaultMaxWorkers, negative = no cap)
+	LibreOfficePath string
+	Native          bool
+	NoClobber       bool                            // Fail jobs whose output file already exists (OUTPUT_EXISTS)
+	RateLimit       float64                         // Max jobs started per second (0 = unlimited)
+	FailFast        bool                            // Stop the batch after the first failed job
+	Subprocess      *Subprocess                     // Run each job in its own gopdfconv process (nil = in this process)
+	OnProgress      func(jobID string, percent int) // Per-job progress
+	OnBatchProgress func(BatchProgress)             // Aggregate progress as jobs finish
+}
+
 // BatchConvert performs batch conversion with the worker pool
-func BatchConvert(jobs []Job, workers int, libreOfficePath string, native bool) []JobResult {
-	pool := NewPool(workers, libreOfficePath)
-	pool.native = native
+func BatchConvert(jobs []Job, opts BatchOptions) []JobResult {
+	pool := NewPoolWithMax(opts.Workers, opts.MaxWorkers, opts.LibreOfficePath)
+	pool.native = opts.Native
+	pool.noClobber = opts.NoClobber
+	pool.SetProgressCallback(opts.OnProgress)
+	pool.SetRateLimit(opts.RateLimit)
+	pool.SetExpectedJobs(len(jobs))
+	pool.FailFast = opts.FailFast
+	pool.subprocess = opts.Subprocess
 	pool.Start()
 
-	// Submit all jobs
+	// Submit all jobs, stopping early if the pool was cancelled
 	go func() {
 		for _, job := range jobs {
+			if pool.ctx.Err() != nil {
+				break
+			}
 			pool.Submit(job)
 		}
 		// Close the job queue after all jobs are submitted
-		close(pool.jobQueue)
+		pool.CloseQueue()
 	}()
 
+	// Close results once every worker has exited, so collection also ends
+	// when a fail-fast cancel leaves jobs unprocessed
+	go pool.closeResults()
+
 	// Collect results
 	var results []JobResult
-	resultCount := 0
-	expectedCount := len(jobs)
-
 	for result := range pool.results {
 		results = append(results, result)
-		resultCount++
-		if resultCount >= expectedCount {
-			break
+		if opts.OnBatchProgress != nil {
+			opts.OnBatchProgress(pool.Progress())
 		}
 	}
 
-	pool.cancel()
-	pool.wg.Wait()
+	pool.Stop()
 
 	return results
 }
 
+// notAttempted returns the IDs of jobs that have no result, in submission order
+func notAttempted(jobs []Job, results []JobResult) []string {
+	done := make(map[string]bool, len(results))
+	for _, r := range results {
+		done[r.Job.ID] = true
+	}
+
+	var ids []string
+	for _, job := range jobs {
+		if !done[job.ID] {
+			ids = append(ids, job.ID)
+		}
+	}
+	return ids
+}
+
 // BatchResult summarizes batch conversion results
 type BatchResult struct {
 	TotalJobs    int           `json:"total_jobs"`
 	Successful   int           `json:"successful"`
 	Failed       int           `json:"failed"`
 	TotalTime    time.Duration `json:"total_time_ns"`
+	ErrorSummary []ErrorSummary `json:"error_summary,omitempty"` // Failures grouped by error code
+	NotAttempted []string      `json:"not_attempted,omitempty"` // IDs of jobs skipped after a fail-fast cancel
 	Results      []JobResult   `json:"results"`
 }
 
+// ErrorSummary groups failed jobs that share an error code
+type ErrorSummary struct {
+	Code   errors.ErrorCode `json:"code"`
+	Count  int              `json:"count"`
+	Sample string           `json:"sample_message"` // Error message of the first failed job with this code
+	JobIDs []string         `json:"job_ids"`
+}
+
+// summarizeErrors groups failed results by error code, most frequent first
+func summarizeErrors(results []JobResult) []ErrorSummary {
+	var summaries []ErrorSummary
+	index := make(map[errors.ErrorCode]int)
+
+	for _, r := range results {
+		if r.Success {
+			continue
+		}
+		i, ok := index[r.ErrorCode]
+		if !ok {
+			i = len(summaries)
+			index[r.ErrorCode] = i
+			summaries = append(summaries, ErrorSummary{Code: r.ErrorCode, Sample: r.Error})
+		}
+		summaries[i].Count++
+		summaries[i].JobIDs = append(summaries[i].JobIDs, r.Job.ID)
+	}
+
+	sort.SliceStable(summaries, func(a, b int) bool {
+		return summaries[a].Count > summaries[b].Count
+	})
+	return summaries
+}
+
 // ToJSON returns the batch result as JSON
 func (br BatchResult) ToJSON() string {
 	data, _ := json.MarshalIndent(br, "", "  ")
@@ -268,9 +504,9 @@ func (br BatchResult) ToJSON() string {
 }
 
 // RunBatch executes a batch conversion and returns summarized results
-func RunBatch(jobs []Job, workers int, libreOfficePath string, native bool) BatchResult {
+func RunBatch(jobs []Job, opts BatchOptions) BatchResult {
 	start := time.Now()
-	results := BatchConvert(jobs, workers, libreOfficePath, native)
+	results := BatchConvert(jobs, opts)
 
 	batch := BatchResult{
 		TotalJobs: len(jobs),
@@ -285,6 +521,8 @@ func RunBatch(jobs []Job, workers int, libreOfficePath string, native bool) Batc
 			batch.Failed++
 		}
 	}
+	batch.ErrorSummary = summarizeErrors(results)
+	batch.NotAttempted = notAttempted(jobs, results)
 
 	return batch
 }
@@ -0,0 +1,221 @@
+package worker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/converter"
+	"github.com/nikunjkothiya/gopdfconv/internal/pdf"
+)
+
+// csvJobs writes n small CSV files to dir and returns a job converting each
+func csvJobs(t testing.TB, dir string, n int) []Job {
+	t.Helper()
+	jobs := make([]Job, n)
+	for i := range jobs {
+		input := filepath.Join(dir, fmt.Sprintf("in%d.csv", i))
+		data := "Name,Amount\n" + strings.Repeat(fmt.Sprintf("row %d,%d.50\n", i, i), 20)
+		if err := os.WriteFile(input, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		jobs[i] = Job{
+			ID:         fmt.Sprintf("job-%d", i),
+			InputPath:  input,
+			OutputPath: filepath.Join(dir, fmt.Sprintf("out%d.pdf", i)),
+			Format:     converter.FormatCSV,
+			Options:    pdf.DefaultOptions(),
+		}
+	}
+	return jobs
+}
+
+func TestFailFastReportsEveryJobThatRan(t *testing.T) {
+	for round := 0; round < 10; round++ {
+		dir := t.TempDir()
+		jobs := csvJobs(t, dir, 30)
+		jobs[0].InputPath = filepath.Join(dir, "missing.csv") // Fails first
+
+		batch := RunBatch(jobs, BatchOptions{Workers: 4, FailFast: true, Native: true})
+		if batch.Failed == 0 {
+			t.Fatalf("round %d: the missing input didn't fail", round)
+		}
+		if got := len(batch.Results) + len(batch.NotAttempted); got != len(jobs) {
+			t.Fatalf("round %d: %d results and %d not attempted for %d jobs", round, len(batch.Results), len(batch.NotAttempted), len(jobs))
+		}
+		notAttempted := make(map[string]bool)
+		for _, id := range batch.NotAttempted {
+			notAttempted[id] = true
+		}
+		for _, job := range jobs {
+			if _, err := os.Stat(job.OutputPath); err == nil && notAttempted[job.ID] {
+				t.Fatalf("round %d: %s wrote its output but is reported as not attempted", round, job.ID)
+			}
+		}
+	}
+}
+
+func TestCancelKeepsFinishedResults(t *testing.T) {
+	jobs := csvJobs(t, t.TempDir(), 3)
+	pool := NewPool(1, "")
+	pool.native = true
+	pool.Start()
+	for _, job := range jobs {
+		pool.Submit(job)
+	}
+
+	// Nobody reads the results yet: the two-slot buffer fills and the worker waits
+	// to hand over the third result while the pool is cancelled
+	for pool.Progress().Completed < len(jobs) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond) // Lets the worker block on the full results
+	pool.cancel()
+	time.Sleep(50 * time.Millisecond) // Lets the worker see the cancel before anything is read
+	go pool.closeResults()
+
+	var got int
+	for range pool.Results() {
+		got++
+	}
+	if got != len(jobs) {
+		t.Fatalf("got %d results after cancel, want %d", got, len(jobs))
+	}
+}
+
+// TestCancelStopsRunningJob cancels the pool while a long CSV is being drawn:
+// the job stops between rows and reports the cancel instead of finishing
+func TestCancelStopsRunningJob(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "large.csv")
+	var data strings.Builder
+	data.WriteString("Name,Amount\n")
+	for i := 0; i < 200000; i++ {
+		fmt.Fprintf(&data, "Customer %d,%d.50\n", i, i)
+	}
+	if err := os.WriteFile(input, []byte(data.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	job := Job{ID: "large", InputPath: input, OutputPath: filepath.Join(dir, "large.pdf"), Format: converter.FormatCSV, Options: pdf.DefaultOptions()}
+
+	pool := NewPool(1, "")
+	pool.native = true
+	started := make(chan struct{})
+	var once sync.Once
+	pool.SetProgressCallback(func(string, int) { once.Do(func() { close(started) }) })
+	pool.Start()
+	pool.Submit(job)
+
+	<-started // Drawing the table has begun
+	pool.cancel()
+	select {
+	case result := <-pool.Results():
+		if result.Success || !strings.Contains(result.Error, "cancelled") {
+			t.Fatalf("result %+v, want the job to fail as cancelled", result)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("the running job didn't stop after the cancel")
+	}
+	if _, err := os.Stat(job.OutputPath); err == nil {
+		t.Error("the cancelled job wrote its output")
+	}
+	pool.closeResults()
+}
+
+func TestCancelWhileWaitingForLibreOfficeSlot(t *testing.T) {
+	// An empty or auto format needs the slot too: the input is detected as XLS
+	for _, format := range []converter.FormatType{converter.FormatXLS, converter.FormatAuto, ""} {
+		pool := NewPool(1, "")
+		for i := 0; i < cap(pool.officeSlots); i++ {
+			pool.officeSlots <- struct{}{} // Every slot is busy
+		}
+		pool.Start()
+		pool.Submit(Job{ID: "xls", InputPath: "report.xls", OutputPath: "report.pdf", Format: format})
+		time.Sleep(50 * time.Millisecond) // Lets the worker wait for a slot
+		pool.cancel()
+
+		done := make(chan int)
+		go func() {
+			pool.closeResults()
+			done <- len(pool.results)
+		}()
+		select {
+		case n := <-done:
+			if n != 0 {
+				t.Fatalf("format %q: got %d results for a job that never got a slot, want none", format, n)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("format %q: the worker still waits for a LibreOffice slot after the cancel", format)
+		}
+	}
+}
+
+// TestOfficeSlotForRasterizeFallback checks which native jobs wait for a
+// LibreOffice slot: only PowerPoint decks with Options.RasterizeFallback, which
+// exports slides through LibreOffice and pdftoppm
+func TestOfficeSlotForRasterizeFallback(t *testing.T) {
+	rasterize := pdf.DefaultOptions()
+	rasterize.RasterizeFallback = true
+	tests := []struct {
+		input string
+		opts  pdf.Options
+		slot  bool
+	}{
+		{"deck.pptx", pdf.DefaultOptions(), false},
+		{"deck.pptx", rasterize, true},
+		{"deck.ppt", rasterize, true},
+		{"data.csv", rasterize, false},
+		{"book.xlsx", rasterize, false},
+	}
+	for _, tt := range tests {
+		pool := NewPool(1, "")
+		pool.native = true
+		for i := 0; i < cap(pool.officeSlots); i++ {
+			pool.officeSlots <- struct{}{} // Every slot is busy
+		}
+		pool.cancel() // A job that needs a slot gives up instead of waiting
+
+		_, ok := pool.acquireOfficeSlot(Job{InputPath: tt.input, Options: tt.opts})
+		if waited := !ok; waited != tt.slot {
+			t.Errorf("%s with RasterizeFallback %v: needed a slot %v, want %v", tt.input, tt.opts.RasterizeFallback, waited, tt.slot)
+		}
+	}
+}
+
+func TestStopDrainsQueuedJobs(t *testing.T) {
+	jobs := csvJobs(t, t.TempDir(), 12)
+	pool := NewPool(2, "")
+	pool.native = true
+	pool.Start()
+
+	collected := make(chan int)
+	go func() {
+		n := 0
+		for range pool.Results() {
+			n++
+		}
+		collected <- n
+	}()
+	for _, job := range jobs {
+		pool.Submit(job)
+	}
+	pool.Stop()
+
+	if n := <-collected; n != len(jobs) {
+		t.Fatalf("got %d results after Stop, want %d", n, len(jobs))
+	}
+}
+
+func TestBatchConvertRepeated(t *testing.T) {
+	jobs := csvJobs(t, t.TempDir(), 4)
+	for round := 0; round < 10; round++ {
+		opts := BatchOptions{Workers: 3, Native: true, FailFast: round%2 == 1}
+		if results := BatchConvert(jobs, opts); len(results) != len(jobs) {
+			t.Fatalf("round %d: got %d results, want %d", round, len(results), len(jobs))
+		}
+	}
+}
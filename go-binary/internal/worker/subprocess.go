@@ -0,0 +1,124 @@
+package worker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/nikunjkothiya/gopdfconv/internal/converter"
+	"github.com/nikunjkothiya/gopdfconv/pkg/errors"
+)
+
+// Subprocess runs each job as a separate gopdfconv process instead of in the pool's
+// own process, so a crash while converting one file (e.g. soffice taking the
+// converter down with it) fails only that job. Job.Options is not used: the
+// conversion options must be passed to the child as flags in Args.
+type Subprocess struct {
+	Path string   // gopdfconv binary
+	Args []string // Flags given to every child, before -input/-output/-format
+}
+
+// childOutput is the part of the child's result JSON the pool needs
+type childOutput struct {
+	Success     bool                    `json:"success"`
+	Error       *errors.ConversionError `json:"error"`
+	OutputFiles []string                `json:"output_files"`
+	FileSize    int64                   `json:"file_size_bytes"`
+	Stats       *converter.Stats        `json:"stats"`
+	Quality     string                  `json:"quality"`
+	Warnings    []string                `json:"warnings"`
+}
+
+// childProgress is one line of the child's -progress-fd channel
+type childProgress struct {
+	Percent int `json:"percent"`
+}
+
+// runSubprocess converts job in a child process and fills result from its JSON output
+func (p *Pool) runSubprocess(job Job, result *JobResult) {
+	args := append([]string{}, p.subprocess.Args...)
+	args = append(args, "-input", job.InputPath, "-output", job.OutputPath, "-json", "-quiet")
+	if job.Format != "" {
+		args = append(args, "-format", string(job.Format))
+	}
+
+	cmd := exec.CommandContext(p.ctx, p.subprocess.Path, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// Forward the child's progress lines as this job's progress
+	var progress *os.File
+	done := make(chan struct{})
+	if p.onProgress != nil {
+		r, w, err := os.Pipe()
+		if err == nil {
+			cmd.ExtraFiles = []*os.File{w} // fd 3 in the child
+			cmd.Args = append(cmd.Args, "-progress-fd", "3")
+			progress = w
+			go func() {
+				defer close(done)
+				defer r.Close()
+				scanner := bufio.NewScanner(r)
+				for scanner.Scan() {
+					var event childProgress
+					if json.Unmarshal(scanner.Bytes(), &event) == nil {
+						p.onProgress(job.ID, event.Percent)
+					}
+				}
+			}()
+		}
+	}
+	if progress == nil {
+		close(done)
+	}
+
+	runErr := cmd.Run()
+	if progress != nil {
+		progress.Close()
+	}
+	<-done
+
+	var out childOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		// No result: the child crashed or was killed before printing one
+		result.Success = false
+		result.ErrorCode = errors.ErrConversionFailed
+		result.Error = fmt.Sprintf("converter process failed: %v", runErr)
+		if msg := firstLine(stderr.String()); msg != "" {
+			result.Error += ": " + msg
+		}
+		return
+	}
+
+	if !out.Success {
+		result.Success = false
+		result.ErrorCode = errors.ErrConversionFailed
+		result.Error = fmt.Sprintf("converter process failed: %v", runErr)
+		if out.Error != nil {
+			result.ErrorCode = out.Error.Code
+			result.Error = out.Error.Error()
+		}
+		return
+	}
+
+	result.Success = true
+	result.OutputSize = out.FileSize
+	result.Stats = out.Stats
+	result.Quality = out.Quality
+	result.Warnings = out.Warnings
+	if len(out.OutputFiles) > 1 {
+		result.OutputFiles = out.OutputFiles
+	}
+}
+
+// firstLine returns the first non-empty line of s, which for a crashed child is
+// the panic message ahead of its stack trace
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(line)
+}
@@ -2,6 +2,7 @@ package errors
 
 import (
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 )
 
@@ -18,6 +19,8 @@ const (
 	ErrUnsupportedFormat ErrorCode = "UNSUPPORTED_FORMAT"
 	ErrWriteFailed       ErrorCode = "WRITE_FAILED"
 	ErrParseFailed       ErrorCode = "PARSE_FAILED"
+	ErrInvalidOption     ErrorCode = "INVALID_OPTION"
+	ErrOutputExists      ErrorCode = "OUTPUT_EXISTS"
 )
 
 // ConversionError is a structured error with JSON output for Laravel parsing
@@ -26,6 +29,7 @@ type ConversionError struct {
 	Message string    `json:"message"`
 	File    string    `json:"file,omitempty"`
 	Details string    `json:"details,omitempty"`
+	Cause   ErrorCode `json:"cause,omitempty"` // Code of a wrapped ConversionError, when overridden by Wrap
 }
 
 func (e *ConversionError) Error() string {
@@ -65,11 +69,39 @@ func NewWithDetails(code ErrorCode, message, file, details string) *ConversionEr
 	}
 }
 
-// Wrap wraps an existing error with additional context
+// Wrap wraps an existing error with additional context. If err is (or wraps) a
+// ConversionError, its File is preserved, its message becomes the details and its
+// code is kept as Cause; the given code is used as the outer code.
 func Wrap(err error, code ErrorCode, message string) *ConversionError {
+	var inner *ConversionError
+	if stderrors.As(err, &inner) {
+		wrapped := &ConversionError{
+			Code:    code,
+			Message: message,
+			File:    inner.File,
+			Details: inner.Message,
+		}
+		if inner.Code != code {
+			wrapped.Cause = inner.Code
+		}
+		if inner.Details != "" {
+			wrapped.Details += ": " + inner.Details
+		}
+		return wrapped
+	}
 	return &ConversionError{
 		Code:    code,
 		Message: message,
 		Details: err.Error(),
 	}
 }
+
+// WrapPreserve is like Wrap, but a wrapped ConversionError's code wins over the given
+// code, so callers can add context without hiding a more specific error code.
+func WrapPreserve(err error, code ErrorCode, message string) *ConversionError {
+	wrapped := Wrap(err, code, message)
+	if wrapped.Cause != "" {
+		wrapped.Code, wrapped.Cause = wrapped.Cause, ""
+	}
+	return wrapped
+}
@@ -224,11 +224,13 @@ class PdfBuilder
     }
 
     /**
-     * Set custom TTF font path
+     * Set custom TTF font path. With $required, the conversion fails instead of
+     * falling back to another font when it can't be loaded.
      */
-    public function font(string $path): self
+    public function font(string $path, bool $required = false): self
     {
         $this->options['font'] = $path;
+        $this->options['require_font'] = $required;
         return $this;
     }
 
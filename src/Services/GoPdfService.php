@@ -20,7 +20,7 @@ class GoPdfService
     protected array $defaults;
     protected array $timeouts;
 
-    protected const SUPPORTED_FORMATS = ['csv', 'tsv', 'xlsx', 'xls', 'xlsm', 'pptx', 'ppt'];
+    protected const SUPPORTED_FORMATS = ['csv', 'tsv', 'xlsx', 'xls', 'xlsm', 'ods', 'pptx', 'ppt'];
 
     public function __construct(
         ?string $binaryPath = null,
@@ -394,6 +394,9 @@ class GoPdfService
         if (isset($options['font'])) {
             $command[] = '--font=' . $options['font'];
         }
+        if (!empty($options['require_font'])) {
+            $command[] = '--require-font';
+        }
         if (isset($options['watermark_text'])) {
             $command[] = '--watermark-text=' . $options['watermark_text'];
         }
@@ -154,4 +154,104 @@ class PdfGenerationTest extends TestCase
         
         echo "\n[Test 5] Generated Watermarked PDF: $outputFile";
     }
+
+    /**
+     * Test 6: Long Header & Footer
+     * Verifies header/footer text longer than the page width is wrapped within the content width.
+     */
+    public function test_long_header_footer_wraps()
+    {
+        $outputFile = $this->outputDir . '/06_long_header_footer.pdf';
+        if (file_exists($outputFile)) unlink($outputFile);
+
+        $longText = str_repeat("Quarterly Consolidated Regional Sales Performance Report ", 5);
+
+        $this->getService()->csv($this->inputFile)
+            ->toPdf($outputFile)
+            ->headerText($longText)
+            ->footerText($longText)
+            ->convert();
+
+        $this->assertFileExists($outputFile);
+        $this->assertGreaterThan(1000, filesize($outputFile));
+        
+        echo "\n[Test 6] Generated Long Header/Footer PDF: $outputFile";
+    }
+
+    /**
+     * Test 7: Jagged Rows
+     * Verifies data rows with more cells than the header are rendered under empty header labels.
+     */
+    public function test_jagged_rows_render_extra_columns()
+    {
+        $outputFile = $this->outputDir . '/07_jagged_rows.pdf';
+        if (file_exists($outputFile)) unlink($outputFile);
+
+        $this->getService()->csv(__DIR__ . '/../fixtures/jagged_rows.csv')
+            ->toPdf($outputFile)
+            ->convert();
+
+        $this->assertFileExists($outputFile);
+        $this->assertGreaterThan(1000, filesize($outputFile));
+        
+        echo "\n[Test 7] Generated Jagged Rows PDF: $outputFile";
+    }
+
+    /**
+     * Test 8: European CSV
+     * Verifies a semicolon-delimited CSV with comma decimals and dot thousands is converted.
+     */
+    public function test_european_semicolon_csv()
+    {
+        $outputFile = $this->outputDir . '/08_european_numbers.pdf';
+        if (file_exists($outputFile)) unlink($outputFile);
+
+        $this->getService()->csv(__DIR__ . '/../fixtures/european_numbers.csv')
+            ->toPdf($outputFile)
+            ->convert();
+
+        $this->assertFileExists($outputFile);
+        $this->assertGreaterThan(1000, filesize($outputFile));
+        
+        echo "\n[Test 8] Generated European CSV PDF: $outputFile";
+    }
+
+    /**
+     * Test 9: Mixed Formatting PPTX
+     * Verifies a deck with bold, italic and underlined runs is rendered natively.
+     */
+    public function test_mixed_formatting_pptx()
+    {
+        $outputFile = $this->outputDir . '/09_mixed_formatting.pdf';
+        if (file_exists($outputFile)) unlink($outputFile);
+
+        $this->getService()->pptx(__DIR__ . '/../fixtures/mixed_formatting.pptx')
+            ->native()
+            ->toPdf($outputFile)
+            ->convert();
+
+        $this->assertFileExists($outputFile);
+        $this->assertGreaterThan(1000, filesize($outputFile));
+        
+        echo "\n[Test 9] Generated Mixed Formatting PDF: $outputFile";
+    }
+
+    /**
+     * Test 10: Macro-enabled Workbook
+     * Verifies an XLSM workbook with a VBA project is converted from its cell values.
+     */
+    public function test_macro_enabled_workbook()
+    {
+        $outputFile = $this->outputDir . '/10_macro_workbook.pdf';
+        if (file_exists($outputFile)) unlink($outputFile);
+
+        $this->getService()->excel(__DIR__ . '/../fixtures/macro_workbook.xlsm')
+            ->toPdf($outputFile)
+            ->convert();
+
+        $this->assertFileExists($outputFile);
+        $this->assertGreaterThan(1000, filesize($outputFile));
+        
+        echo "\n[Test 10] Generated Macro Workbook PDF: $outputFile";
+    }
 }